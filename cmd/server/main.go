@@ -3,17 +3,48 @@ package main
 import (
 	"context"
 	"fmt"
+	nethttp "net/http"
 
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments"
+	attachmentStore "github.com/Polilo-User/test-task-hitalent/internal/attachments/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes"
+	changeStore "github.com/Polilo-User/test-task-hitalent/internal/changes/store"
 	"github.com/Polilo-User/test-task-hitalent/internal/chats"
 	chatStore "github.com/Polilo-User/test-task-hitalent/internal/chats/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
 	"github.com/Polilo-User/test-task-hitalent/internal/config"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/app"
 	psql "github.com/Polilo-User/test-task-hitalent/internal/core/drivers/gorm"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/listeners/grpc"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/listeners/http"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks"
+	hookStore "github.com/Polilo-User/test-task-hitalent/internal/hooks/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency"
+	idempotencyStore "github.com/Polilo-User/test-task-hitalent/internal/idempotency/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports"
+	importStore "github.com/Polilo-User/test-task-hitalent/internal/imports/store"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages"
 	messageStore "github.com/Polilo-User/test-task-hitalent/internal/messages/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications"
+	notificationStore "github.com/Polilo-User/test-task-hitalent/internal/notifications/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox"
+	outboxStore "github.com/Polilo-User/test-task-hitalent/internal/outbox/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads"
+	readStore "github.com/Polilo-User/test-task-hitalent/internal/reads/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention"
+	retentionStore "github.com/Polilo-User/test-task-hitalent/internal/retention/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled"
+	scheduledStore "github.com/Polilo-User/test-task-hitalent/internal/scheduled/store"
+	graphqltransport "github.com/Polilo-User/test-task-hitalent/internal/transport/graphql"
+	grpctransport "github.com/Polilo-User/test-task-hitalent/internal/transport/grpc"
 	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl"
+	unfurlStore "github.com/Polilo-User/test-task-hitalent/internal/unfurl/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks"
+	webhookStore "github.com/Polilo-User/test-task-hitalent/internal/webhooks/store"
 
 	"github.com/cenkalti/backoff/v4"
 	"go.uber.org/zap"
@@ -41,23 +72,106 @@ func appStart(ctx context.Context, a *app.App) ([]app.Listener, error) {
 		rollbackMigrations(ctx, db)
 	})
 
+	ws := webhookStore.New(db.GetDB())
+	w := webhooks.New(ws, &nethttp.Client{Timeout: cfg.WEBHOOK_TIMEOUT}, cfg.WEBHOOK_MAX_RETRIES, cfg.WEBHOOK_RESCAN_INTERVAL)
+
+	cmd := commands.NewRegistry()
+	if cfg.COMMANDS_ENDPOINT != "" {
+		remote := commands.NewRemote(cfg.COMMANDS_ENDPOINT, &nethttp.Client{}, cfg.COMMANDS_TIMEOUT)
+		for _, name := range cfg.COMMANDS_NAMES {
+			cmd.Register(name, "run by an external service", remote)
+		}
+	}
+
+	blobs := newBlobStore(cfg)
+	at := attachments.New(attachmentStore.New(db.GetDB()), blobs,
+		cfg.ATTACHMENT_URL_SECRET, cfg.ATTACHMENT_URL_TTL, cfg.ATTACHMENT_MAX_SIZE)
+	proc := attachments.NewProcessor(attachmentStore.New(db.GetDB()), blobs, cfg.ATTACHMENT_PROCESS_INTERVAL)
+
+	uf := unfurl.New(unfurlStore.New(db.GetDB()), unfurl.NewClient(cfg.UNFURL_TIMEOUT), cfg.UNFURL_CACHE_TTL, cfg.UNFURL_POLL_INTERVAL)
+
 	cs := chatStore.New(db.GetDB())
-	ms := messageStore.New(db.GetDB())
+	ms := messages.WithPreviews(messages.WithAttachments(messageStore.New(db.GetDB()), at), uf)
 	c := chats.New(cs, ms)
-	m := messages.New(ms, c)
+	m := messages.New(ms, c, cmd)
+	sw := messages.NewSweeper(messageStore.New(db.GetDB()), cfg.MESSAGE_SWEEP_INTERVAL)
+	rt := retention.New(retentionStore.New(db.GetDB()), cfg.RETENTION_DAYS, cfg.RETENTION_INTERVAL)
+
+	hk := hooks.New(hookStore.New(db.GetDB()), c, m)
+
+	idem := idempotency.New(idempotencyStore.New(db.GetDB()), cfg.IDEMPOTENCY_TTL)
+
+	nt := notifications.New(notificationStore.New(db.GetDB()))
+	rd := reads.New(readStore.New(db.GetDB()))
+
+	sc := scheduled.New(scheduledStore.New(db.GetDB()), c, cfg.SCHEDULER_POLL_INTERVAL)
+
+	im := imports.New(importStore.New(db.GetDB()), cfg.IMPORT_POLL_INTERVAL)
 
-	httpServer := httptransport.New(c, m, db.GetDB())
+	broker := events.NewBroker()
+
+	relay := outbox.New(outboxStore.New(db.GetDB()), cfg.OUTBOX_POLL_INTERVAL, cfg.OUTBOX_BATCH_SIZE, w, uf)
+	ch := changes.New(changeStore.New(db.GetDB()), cfg.OUTBOX_RETENTION)
+	follower := changes.NewFollower(changeStore.New(db.GetDB()), broker, cfg.OUTBOX_POLL_INTERVAL, cfg.OUTBOX_BATCH_SIZE)
+
+	gql, err := graphqltransport.New(c, m, graphqltransport.WithReads(rd))
+	if err != nil {
+		return nil, err
+	}
+
+	httpServer := httptransport.New(c, m, db.GetDB(),
+		httptransport.WithWebhooks(w),
+		httptransport.WithHooks(hk),
+		httptransport.WithGraphQL(gql),
+		httptransport.WithImports(im, cfg.IMPORT_MAX_SIZE),
+		httptransport.WithIdempotency(idem),
+		httptransport.WithAttachments(at, cfg.ATTACHMENT_MAX_SIZE),
+		httptransport.WithNotifications(nt),
+		httptransport.WithReads(rd),
+		httptransport.WithSync(ch),
+		httptransport.WithScheduling(sc),
+		httptransport.WithRetention(rt),
+	)
 
 	h, err := http.New(httpServer, cfg.HTTP_PORT)
 	if err != nil {
 		return nil, err
 	}
 
+	g := grpc.New(grpctransport.New(c, m, broker), cfg.GRPC_PORT)
+
 	return []app.Listener{
 		h,
+		g,
+		relay,
+		ch,
+		follower,
+		sc,
+		sw,
+		rt,
+		w,
+		im,
+		idem,
+		at,
+		proc,
+		uf,
 	}, nil
 }
 
+func newBlobStore(cfg *config.Config) blob.BlobStore {
+	if cfg.BLOB_BACKEND == "s3" {
+		return blob.NewS3(blob.S3Config{
+			Endpoint:  cfg.S3_ENDPOINT,
+			Region:    cfg.S3_REGION,
+			Bucket:    cfg.S3_BUCKET,
+			AccessKey: cfg.S3_ACCESS_KEY,
+			SecretKey: cfg.S3_SECRET_KEY,
+		}, &nethttp.Client{})
+	}
+
+	return blob.NewLocal(cfg.BLOB_DIR)
+}
+
 func initDatabase(ctx context.Context, cfg *config.Config, a *app.App) (*psql.Driver, error) {
 	db := psql.New(cfg.PSQL)
 
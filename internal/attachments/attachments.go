@@ -0,0 +1,411 @@
+package attachments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrAttachmentNotFound = errors.Error("attachment_not_found: attachment not found")
+	ErrMessageNotFound    = errors.Error("message_not_found: message not found")
+	ErrInvalidName        = errors.Error("invalid_attachment_name: attachment name must be 1 to 255 characters")
+	ErrAttachmentTooLarge = errors.Error("attachment_too_large: the attachment exceeds the maximum size")
+	ErrInvalidSignature   = errors.Error("invalid_signature: the download link is invalid")
+	ErrLinkExpired        = errors.Error("link_expired: the download link has expired")
+)
+
+const (
+	maxNameLength = 255
+	// sniffLength is how many leading bytes are looked at to detect the content type.
+	sniffLength = 512
+	// sweepInterval is how often the blobs of deleted attachments are deleted.
+	sweepInterval = time.Minute
+	// sweepBatchSize is the number of blobs deleted per sweep.
+	sweepBatchSize = 100
+)
+
+type Store interface {
+	InsertAttachment(ctx context.Context, a *model.Attachment) (*model.Attachment, error)
+	GetAttachment(ctx context.Context, id string) (*model.Attachment, error)
+	ListAttachments(ctx context.Context, messageID string) ([]model.Attachment, error)
+	ListAttachmentsByMessages(ctx context.Context, messageIDs []string) ([]model.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	MessageExist(ctx context.Context, chatID, messageID string) (bool, error)
+	ListOrphanedBlobs(ctx context.Context, limit int) ([]model.OrphanedBlob, error)
+	DeleteOrphanedBlob(ctx context.Context, id string) error
+	ClaimProcessing(ctx context.Context, staleAfter time.Duration) (*model.Attachment, error)
+	CompleteProcessing(ctx context.Context, a *model.Attachment) error
+}
+
+// AttachmentService stores files attached to messages. Their metadata lives in the
+// database and their content in a blob store. Content is downloaded through
+// signed links that expire, which can be handed to clients that cannot
+// authenticate, such as browsers following a link.
+type AttachmentService struct {
+	store   Store
+	blobs   blob.BlobStore
+	secret  []byte
+	urlTTL  time.Duration
+	maxSize int64
+}
+
+// New creates the service. Links are signed with secret; when it is empty a random
+// one is used, so links are only valid on this instance until it restarts.
+func New(s Store, blobs blob.BlobStore, secret string, urlTTL time.Duration, maxSize int64) *AttachmentService {
+	if secret == "" {
+		secret = rand.Text()
+	}
+
+	return &AttachmentService{
+		store:   s,
+		blobs:   blobs,
+		secret:  []byte(secret),
+		urlTTL:  urlTTL,
+		maxSize: maxSize,
+	}
+}
+
+// Upload attaches the file read from r to a message. The file is spooled to disk
+// first to learn its size and digest, and its content type is detected from its
+// leading bytes rather than trusted from the client. Images are queued for
+// thumbnails to be generated.
+func (a *AttachmentService) Upload(ctx context.Context, chatID, messageID, name string, r io.Reader) (*model.Attachment, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.messageExist(ctx, chatID, messageID); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	size, err := io.Copy(f, io.LimitReader(r, a.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > a.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+
+	// Where a photo was taken is stripped before anyone can download it.
+	if _, err := stripLocation(f, contentType); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	blobName, err := newBlobName()
+	if err != nil {
+		return nil, err
+	}
+
+	key := "attachments/" + chatID + "/" + messageID + "/" + blobName
+	if err := a.blobs.Put(ctx, key, f, size, contentType); err != nil {
+		return nil, err
+	}
+
+	att := &model.Attachment{
+		MessageID:   pointer.ToString(messageID),
+		ChatID:      pointer.ToString(chatID),
+		Name:        pointer.ToString(name),
+		Size:        pointer.ToInt64(size),
+		ContentType: pointer.ToString(contentType),
+		SHA256:      pointer.ToString(hex.EncodeToString(h.Sum(nil))),
+		StorageKey:  pointer.ToString(key),
+	}
+	if isImage(contentType) {
+		att.ProcessingStatus = pointer.ToString(model.ProcessingPending)
+	}
+
+	att, err = a.store.InsertAttachment(ctx, att)
+	if err != nil {
+		if err := a.blobs.Delete(context.WithoutCancel(ctx), key); err != nil {
+			logging.From(ctx).Error("failed to delete blob of failed upload", zap.String("key", key), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	a.sign(att)
+	return att, nil
+}
+
+func (a *AttachmentService) ListAttachments(ctx context.Context, chatID, messageID string) ([]model.Attachment, error) {
+	if err := a.messageExist(ctx, chatID, messageID); err != nil {
+		return nil, err
+	}
+
+	atts, err := a.store.ListAttachments(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range atts {
+		a.sign(&atts[i])
+	}
+	return atts, nil
+}
+
+func (a *AttachmentService) GetAttachment(ctx context.Context, id string) (*model.Attachment, error) {
+	att, err := a.getAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a.sign(att)
+	return att, nil
+}
+
+// DeleteAttachment deletes an attachment. Its blob is deleted in the background.
+func (a *AttachmentService) DeleteAttachment(ctx context.Context, id string) error {
+	if _, err := a.getAttachment(ctx, id); err != nil {
+		return err
+	}
+
+	return a.store.DeleteAttachment(ctx, id)
+}
+
+// Open verifies a download link and returns the attachment it was issued for
+// along with its content.
+func (a *AttachmentService) Open(ctx context.Context, id, expires, signature string) (*model.Attachment, io.ReadSeekCloser, error) {
+	if err := a.verify(id, expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	att, err := a.getAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := a.open(ctx, pointer.GetString(att.StorageKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return att, r, nil
+}
+
+// OpenThumbnail verifies a thumbnail download link and returns the thumbnail it
+// was issued for along with its content.
+func (a *AttachmentService) OpenThumbnail(ctx context.Context, id, name, expires, signature string) (*model.Thumbnail, io.ReadSeekCloser, error) {
+	if err := a.verify(id+"/"+name, expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	att, err := a.getAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, t := range att.Thumbnails {
+		if pointer.GetString(t.Name) != name {
+			continue
+		}
+
+		r, err := a.open(ctx, pointer.GetString(t.StorageKey))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &t, r, nil
+	}
+
+	return nil, nil, ErrAttachmentNotFound
+}
+
+// AttachmentsByMessages returns the attachments of the given messages, keyed by
+// message id.
+func (a *AttachmentService) AttachmentsByMessages(ctx context.Context, messageIDs []string) (map[string][]model.Attachment, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	atts, err := a.store.ListAttachmentsByMessages(ctx, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byMessage := make(map[string][]model.Attachment)
+	for i := range atts {
+		a.sign(&atts[i])
+		id := pointer.GetString(atts[i].MessageID)
+		byMessage[id] = append(byMessage[id], atts[i])
+	}
+	return byMessage, nil
+}
+
+// Listen deletes the blobs of deleted attachments until the context is cancelled.
+func (a *AttachmentService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("attachment sweeper starting")
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("attachment sweeper stopped")
+			return nil
+		case <-ticker.C:
+			for {
+				n, err := a.Sweep(ctx)
+				if err != nil {
+					logging.From(ctx).Error("failed to delete orphaned blobs", zap.Error(err))
+				}
+				if err != nil || n < sweepBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Sweep deletes a batch of blobs of deleted attachments and returns how many it deleted.
+func (a *AttachmentService) Sweep(ctx context.Context) (int, error) {
+	orphans, err := a.store.ListOrphanedBlobs(ctx, sweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, o := range orphans {
+		if err := a.blobs.Delete(ctx, pointer.GetString(o.StorageKey)); err != nil {
+			return i, err
+		}
+		if err := a.store.DeleteOrphanedBlob(ctx, pointer.GetString(o.ID)); err != nil {
+			return i, err
+		}
+	}
+
+	return len(orphans), nil
+}
+
+func (a *AttachmentService) getAttachment(ctx context.Context, id string) (*model.Attachment, error) {
+	att, err := a.store.GetAttachment(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	return att, err
+}
+
+func (a *AttachmentService) messageExist(ctx context.Context, chatID, messageID string) error {
+	ok, err := a.store.MessageExist(ctx, chatID, messageID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+func (a *AttachmentService) open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	r, err := a.blobs.Open(ctx, key)
+	if errors.Is(err, blob.ErrBlobNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	return r, err
+}
+
+// sign sets the download links of an attachment and its thumbnails, valid for the
+// configured time.
+func (a *AttachmentService) sign(att *model.Attachment) {
+	id := pointer.GetString(att.ID)
+	expires := strconv.FormatInt(time.Now().Add(a.urlTTL).Unix(), 10)
+
+	att.URL = pointer.ToString(fmt.Sprintf("/v1/attachments/%s/content?expires=%s&signature=%s",
+		id, expires, hex.EncodeToString(a.signature(id, expires))))
+
+	for i := range att.Thumbnails {
+		t := &att.Thumbnails[i]
+		name := pointer.GetString(t.Name)
+		t.URL = pointer.ToString(fmt.Sprintf("/v1/attachments/%s/thumbnails/%s?expires=%s&signature=%s",
+			id, name, expires, hex.EncodeToString(a.signature(id+"/"+name, expires))))
+	}
+}
+
+// verify checks that a link to the resource was signed by this service and has not
+// expired yet.
+func (a *AttachmentService) verify(resource, expires, signature string) error {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || subtle.ConstantTimeCompare(sig, a.signature(resource, expires)) != 1 {
+		return ErrInvalidSignature
+	}
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > exp {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (a *AttachmentService) signature(resource, expires string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(resource + "\n" + expires))
+	return mac.Sum(nil)
+}
+
+// cleanName keeps the last element of a file name, which browsers may send as a
+// full path.
+func cleanName(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || name == "/" || name == "" || !utf8.ValidString(name) || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+func newBlobName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,316 @@
+package attachments_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image/jpeg"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments"
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+func TestAttachmentService_Upload_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs := blob.NewLocal(t.TempDir())
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().MessageExist(gomock.Any(), "1", "2").Return(true, nil).Times(1)
+	s.EXPECT().InsertAttachment(gomock.Any(), gomock.AssignableToTypeOf(&model.Attachment{})).
+		DoAndReturn(func(ctx context.Context, a *model.Attachment) (*model.Attachment, error) {
+			a.ID = pointer.ToString("3")
+			return a, nil
+		}).Times(1)
+
+	a := attachments.New(s, blobs, "secret", time.Minute, 1024)
+	require.NotNil(t, a)
+
+	att, err := a.Upload(context.Background(), "1", "2", `C:\Users\me\image.png`, strings.NewReader(pngHeader+"data"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "image.png", pointer.GetString(att.Name))
+	assert.Equal(t, "image/png", pointer.GetString(att.ContentType))
+	assert.Equal(t, int64(len(pngHeader)+4), pointer.GetInt64(att.Size))
+	assert.Len(t, pointer.GetString(att.SHA256), 64)
+	assert.True(t, strings.HasPrefix(pointer.GetString(att.StorageKey), "attachments/1/2/"))
+	assert.True(t, strings.HasPrefix(pointer.GetString(att.URL), "/v1/attachments/3/content?"))
+
+	r, err := blobs.Open(context.Background(), pointer.GetString(att.StorageKey))
+	require.NoError(t, err)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, pngHeader+"data", string(b))
+}
+
+func TestAttachmentService_Upload_StripsLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	blobs := blob.NewLocal(t.TempDir())
+	photo := testJPEG(t, 64, 32, 6)
+	require.True(t, bytes.Contains(photo, gpsLatitude))
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().MessageExist(gomock.Any(), "1", "2").Return(true, nil).Times(1)
+	s.EXPECT().InsertAttachment(gomock.Any(), gomock.AssignableToTypeOf(&model.Attachment{})).
+		DoAndReturn(func(ctx context.Context, a *model.Attachment) (*model.Attachment, error) {
+			a.ID = pointer.ToString("3")
+			return a, nil
+		}).Times(1)
+
+	a := attachments.New(s, blobs, "secret", time.Minute, 1<<20)
+
+	att, err := a.Upload(ctx, "1", "2", "photo.jpg", bytes.NewReader(photo))
+	require.NoError(t, err)
+
+	assert.Equal(t, "image/jpeg", pointer.GetString(att.ContentType))
+	assert.Equal(t, model.ProcessingPending, pointer.GetString(att.ProcessingStatus))
+
+	r, err := blobs.Open(ctx, pointer.GetString(att.StorageKey))
+	require.NoError(t, err)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(b)
+	assert.Equal(t, hex.EncodeToString(sum[:]), pointer.GetString(att.SHA256))
+	assert.Len(t, b, len(photo))
+	assert.False(t, bytes.Contains(b, gpsLatitude))
+
+	// The photo is otherwise intact, down to its orientation.
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(b))
+	require.NoError(t, err)
+	assert.Equal(t, 64, cfg.Width)
+	assert.True(t, bytes.Contains(b, []byte{0x12, 0x01, 3, 0, 1, 0, 0, 0, 6, 0}))
+}
+
+func TestAttachmentService_Upload_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		setup   func(s *mocks.MockStore)
+		wantErr error
+	}{
+		{
+			name:    "empty name",
+			file:    " ",
+			content: "data",
+			wantErr: attachments.ErrInvalidName,
+		},
+		{
+			name:    "message not found",
+			file:    "a.txt",
+			content: "data",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().MessageExist(gomock.Any(), "1", "2").Return(false, nil).Times(1)
+			},
+			wantErr: attachments.ErrMessageNotFound,
+		},
+		{
+			name:    "too large",
+			file:    "a.txt",
+			content: strings.Repeat("a", 11),
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().MessageExist(gomock.Any(), "1", "2").Return(true, nil).Times(1)
+			},
+			wantErr: attachments.ErrAttachmentTooLarge,
+		},
+		{
+			name:    "store failure",
+			file:    "a.txt",
+			content: "data",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().MessageExist(gomock.Any(), "1", "2").Return(true, nil).Times(1)
+				s.EXPECT().InsertAttachment(gomock.Any(), gomock.Any()).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantErr: errors.Error("test fail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			a := attachments.New(s, blob.NewLocal(t.TempDir()), "secret", time.Minute, 10)
+
+			att, err := a.Upload(context.Background(), "1", "2", tt.file, strings.NewReader(tt.content))
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, att)
+		})
+	}
+}
+
+func TestAttachmentService_Open_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs := blob.NewLocal(t.TempDir())
+	require.NoError(t, blobs.Put(context.Background(), "attachments/1/2/x", strings.NewReader("data"), 4, "text/plain"))
+
+	stored := &model.Attachment{ID: pointer.ToString("3"), StorageKey: pointer.ToString("attachments/1/2/x")}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetAttachment(gomock.Any(), "3").Return(stored, nil).Times(2)
+
+	a := attachments.New(s, blobs, "secret", time.Minute, 1024)
+
+	att, err := a.GetAttachment(context.Background(), "3")
+	require.NoError(t, err)
+
+	link, err := url.Parse(pointer.GetString(att.URL))
+	require.NoError(t, err)
+
+	_, r, err := a.Open(context.Background(), "3", link.Query().Get("expires"), link.Query().Get("signature"))
+	require.NoError(t, err)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(b))
+}
+
+func TestAttachmentService_Open_Error(t *testing.T) {
+	stored := &model.Attachment{ID: pointer.ToString("3"), StorageKey: pointer.ToString("attachments/1/2/missing")}
+
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		tamper    func(q url.Values)
+		getErr    error
+		wantErr   error
+		wantFetch bool
+	}{
+		{
+			name:    "invalid signature",
+			ttl:     time.Minute,
+			tamper:  func(q url.Values) { q.Set("signature", strings.Repeat("0", 64)) },
+			wantErr: attachments.ErrInvalidSignature,
+		},
+		{
+			name: "extended expiry",
+			ttl:  time.Minute,
+			tamper: func(q url.Values) {
+				exp, _ := strconv.ParseInt(q.Get("expires"), 10, 64)
+				q.Set("expires", strconv.FormatInt(exp+3600, 10))
+			},
+			wantErr: attachments.ErrInvalidSignature,
+		},
+		{
+			name:    "expired",
+			ttl:     -time.Minute,
+			wantErr: attachments.ErrLinkExpired,
+		},
+		{
+			name:      "deleted attachment",
+			ttl:       time.Minute,
+			getErr:    gorm.ErrRecordNotFound,
+			wantErr:   attachments.ErrAttachmentNotFound,
+			wantFetch: true,
+		},
+		{
+			name:      "missing blob",
+			ttl:       time.Minute,
+			wantErr:   attachments.ErrAttachmentNotFound,
+			wantFetch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().GetAttachment(gomock.Any(), "3").Return(stored, nil).Times(1)
+			if tt.wantFetch {
+				s.EXPECT().GetAttachment(gomock.Any(), "3").Return(stored, tt.getErr).Times(1)
+			}
+
+			a := attachments.New(s, blob.NewLocal(t.TempDir()), "secret", tt.ttl, 1024)
+
+			att, err := a.GetAttachment(context.Background(), "3")
+			require.NoError(t, err)
+
+			link, err := url.Parse(pointer.GetString(att.URL))
+			require.NoError(t, err)
+			q := link.Query()
+			if tt.tamper != nil {
+				tt.tamper(q)
+			}
+
+			got, r, err := a.Open(context.Background(), "3", q.Get("expires"), q.Get("signature"))
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestAttachmentService_Sweep_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs := blob.NewLocal(t.TempDir())
+	require.NoError(t, blobs.Put(context.Background(), "attachments/1/2/x", strings.NewReader("data"), 4, ""))
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListOrphanedBlobs(gomock.Any(), gomock.Any()).Return([]model.OrphanedBlob{
+		{ID: pointer.ToString("1"), StorageKey: pointer.ToString("attachments/1/2/x")},
+		{ID: pointer.ToString("2"), StorageKey: pointer.ToString("attachments/1/2/missing")},
+	}, nil).Times(1)
+	s.EXPECT().DeleteOrphanedBlob(gomock.Any(), "1").Return(nil).Times(1)
+	s.EXPECT().DeleteOrphanedBlob(gomock.Any(), "2").Return(nil).Times(1)
+
+	a := attachments.New(s, blobs, "secret", time.Minute, 1024)
+
+	n, err := a.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = blobs.Open(context.Background(), "attachments/1/2/x")
+	assert.ErrorIs(t, err, blob.ErrBlobNotFound)
+}
+
+func TestAttachmentService_DeleteAttachment_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetAttachment(gomock.Any(), "3").Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	a := attachments.New(s, blob.NewLocal(t.TempDir()), "secret", time.Minute, 1024)
+
+	err := a.DeleteAttachment(context.Background(), "3")
+	assert.ErrorIs(t, err, attachments.ErrAttachmentNotFound)
+}
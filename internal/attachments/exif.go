@@ -0,0 +1,310 @@
+package attachments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	tagOrientation = 0x0112
+	tagGPSIFD      = 0x8825
+
+	// maxExifSize bounds the Exif block read into memory; JPEG segments cannot be
+	// larger anyway.
+	maxExifSize = 1 << 16
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// exifBlock is where the TIFF structure holding the Exif metadata of an image is
+// found in its file. In PNG files it is the data of an eXIf chunk, which is
+// followed by a CRC that has to be updated along with it.
+type exifBlock struct {
+	offset int64
+	length int64
+	png    bool
+}
+
+// findExif locates the Exif metadata of a JPEG or PNG image. It returns nil when
+// the image has none.
+func findExif(r io.ReaderAt, contentType string) (*exifBlock, error) {
+	switch contentType {
+	case "image/jpeg":
+		return findJPEGExif(r)
+	case "image/png":
+		return findPNGExif(r)
+	default:
+		return nil, nil
+	}
+}
+
+// findJPEGExif walks the segments preceding the image data looking for an APP1
+// segment with an Exif header.
+func findJPEGExif(r io.ReaderAt) (*exifBlock, error) {
+	var marker [4]byte
+	offset := int64(2)
+
+	for {
+		if _, err := r.ReadAt(marker[:], offset); err != nil {
+			return nil, eofAsNone(err)
+		}
+		if marker[0] != 0xff {
+			return nil, nil
+		}
+		// Start of scan or end of image: the metadata segments are behind us.
+		if marker[1] == 0xda || marker[1] == 0xd9 {
+			return nil, nil
+		}
+
+		length := int64(binary.BigEndian.Uint16(marker[2:]))
+		if marker[1] == 0xe1 && length > int64(len(exifHeader))+2 {
+			header := make([]byte, len(exifHeader))
+			if _, err := r.ReadAt(header, offset+4); err != nil {
+				return nil, eofAsNone(err)
+			}
+			if bytes.Equal(header, exifHeader) {
+				start := offset + 4 + int64(len(exifHeader))
+				return &exifBlock{offset: start, length: offset + 2 + length - start}, nil
+			}
+		}
+
+		offset += 2 + length
+	}
+}
+
+// findPNGExif walks the chunks of a PNG file looking for an eXIf chunk.
+func findPNGExif(r io.ReaderAt) (*exifBlock, error) {
+	var chunk [8]byte
+	offset := int64(len(pngHeader))
+
+	for {
+		if _, err := r.ReadAt(chunk[:], offset); err != nil {
+			return nil, eofAsNone(err)
+		}
+
+		length := int64(binary.BigEndian.Uint32(chunk[:4]))
+		switch string(chunk[4:]) {
+		case "eXIf":
+			if length > maxExifSize {
+				return nil, nil
+			}
+			return &exifBlock{offset: offset + 8, length: length, png: true}, nil
+		case "IEND":
+			return nil, nil
+		}
+
+		offset += 8 + length + 4
+	}
+}
+
+func eofAsNone(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil
+	}
+	return err
+}
+
+// readExif returns the TIFF structure of the block.
+func readExif(r io.ReaderAt, b *exifBlock) ([]byte, error) {
+	if b.length > maxExifSize {
+		return nil, nil
+	}
+
+	t := make([]byte, b.length)
+	if _, err := r.ReadAt(t, b.offset); err != nil {
+		return nil, eofAsNone(err)
+	}
+	return t, nil
+}
+
+// stripLocation removes the GPS data from the Exif metadata of a JPEG or PNG image
+// in place, reporting whether there was any. The rest of the metadata, such as the
+// orientation, is kept, and the file keeps its size.
+func stripLocation(f interface {
+	io.ReaderAt
+	io.WriterAt
+}, contentType string) (bool, error) {
+	b, err := findExif(f, contentType)
+	if err != nil || b == nil {
+		return false, err
+	}
+
+	t, err := readExif(f, b)
+	if err != nil || t == nil {
+		return false, err
+	}
+
+	if !stripGPS(t) {
+		return false, nil
+	}
+
+	if _, err := f.WriteAt(t, b.offset); err != nil {
+		return false, err
+	}
+
+	if b.png {
+		crc := crc32.NewIEEE()
+		crc.Write([]byte("eXIf"))
+		crc.Write(t)
+
+		var sum [4]byte
+		binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+		if _, err := f.WriteAt(sum[:], b.offset+b.length); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// orientation returns the Exif orientation of an image, 1 when it has none.
+func orientation(r io.ReaderAt, contentType string) int {
+	b, err := findExif(r, contentType)
+	if err != nil || b == nil {
+		return 1
+	}
+
+	t, err := readExif(r, b)
+	if err != nil || t == nil {
+		return 1
+	}
+
+	tf, ok := parseTIFF(t)
+	if !ok {
+		return 1
+	}
+
+	e, ok := tf.find(tf.ifd0, tagOrientation)
+	if !ok || e.typ != 3 {
+		return 1
+	}
+
+	o := int(tf.order.Uint16(t[e.at+8:]))
+	if o < 1 || o > 8 {
+		return 1
+	}
+	return o
+}
+
+// stripGPS empties the GPS IFD of a TIFF structure, zeroing its entries and the
+// values they point to. It reports whether there was anything to remove.
+func stripGPS(t []byte) bool {
+	tf, ok := parseTIFF(t)
+	if !ok {
+		return false
+	}
+
+	ptr, ok := tf.find(tf.ifd0, tagGPSIFD)
+	if !ok {
+		return false
+	}
+
+	ifd := int(tf.order.Uint32(t[ptr.at+8:]))
+	n, ok := tf.count(ifd)
+	if !ok || n == 0 {
+		return false
+	}
+
+	for i := 0; i < n; i++ {
+		e := tf.entry(ifd, i)
+		if size := e.size(); size > 4 {
+			off := int(tf.order.Uint32(t[e.at+8:]))
+			if off >= 0 && size <= len(t) && off <= len(t)-size {
+				clear(t[off : off+size])
+			}
+		}
+	}
+
+	clear(t[ifd+2 : ifd+2+n*12])
+	tf.order.PutUint16(t[ifd:], 0)
+
+	return true
+}
+
+type tiff struct {
+	b     []byte
+	order binary.ByteOrder
+	ifd0  int
+}
+
+type tiffEntry struct {
+	at    int
+	tag   uint16
+	typ   uint16
+	count uint32
+}
+
+// typeSizes are the sizes in bytes of the TIFF field types.
+var typeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+func (e tiffEntry) size() int {
+	s, ok := typeSizes[e.typ]
+	if !ok || e.count > maxExifSize {
+		return 0
+	}
+	return s * int(e.count)
+}
+
+func parseTIFF(b []byte) (*tiff, bool) {
+	if len(b) < 8 {
+		return nil, false
+	}
+
+	var order binary.ByteOrder
+	switch string(b[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, false
+	}
+
+	t := &tiff{b: b, order: order, ifd0: int(order.Uint32(b[4:]))}
+	if _, ok := t.count(t.ifd0); !ok {
+		return nil, false
+	}
+	return t, true
+}
+
+// count returns the number of entries of the IFD at offset, checking they fit.
+func (t *tiff) count(offset int) (int, bool) {
+	if offset < 8 || offset > len(t.b)-2 {
+		return 0, false
+	}
+
+	n := int(t.order.Uint16(t.b[offset:]))
+	if offset+2+n*12 > len(t.b) {
+		return 0, false
+	}
+	return n, true
+}
+
+func (t *tiff) entry(ifd, i int) tiffEntry {
+	at := ifd + 2 + i*12
+	return tiffEntry{
+		at:    at,
+		tag:   t.order.Uint16(t.b[at:]),
+		typ:   t.order.Uint16(t.b[at+2:]),
+		count: t.order.Uint32(t.b[at+4:]),
+	}
+}
+
+func (t *tiff) find(ifd int, tag uint16) (tiffEntry, bool) {
+	n, ok := t.count(ifd)
+	if !ok {
+		return tiffEntry{}, false
+	}
+
+	for i := 0; i < n; i++ {
+		if e := t.entry(ifd, i); e.tag == tag {
+			return e, true
+		}
+	}
+	return tiffEntry{}, false
+}
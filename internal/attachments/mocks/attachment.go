@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/attachments (interfaces: Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimProcessing mocks base method.
+func (m *MockStore) ClaimProcessing(arg0 context.Context, arg1 time.Duration) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimProcessing", arg0, arg1)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimProcessing indicates an expected call of ClaimProcessing.
+func (mr *MockStoreMockRecorder) ClaimProcessing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimProcessing", reflect.TypeOf((*MockStore)(nil).ClaimProcessing), arg0, arg1)
+}
+
+// CompleteProcessing mocks base method.
+func (m *MockStore) CompleteProcessing(arg0 context.Context, arg1 *model.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteProcessing", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteProcessing indicates an expected call of CompleteProcessing.
+func (mr *MockStoreMockRecorder) CompleteProcessing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteProcessing", reflect.TypeOf((*MockStore)(nil).CompleteProcessing), arg0, arg1)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockStore) DeleteAttachment(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockStoreMockRecorder) DeleteAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockStore)(nil).DeleteAttachment), arg0, arg1)
+}
+
+// DeleteOrphanedBlob mocks base method.
+func (m *MockStore) DeleteOrphanedBlob(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrphanedBlob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrphanedBlob indicates an expected call of DeleteOrphanedBlob.
+func (mr *MockStoreMockRecorder) DeleteOrphanedBlob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphanedBlob", reflect.TypeOf((*MockStore)(nil).DeleteOrphanedBlob), arg0, arg1)
+}
+
+// GetAttachment mocks base method.
+func (m *MockStore) GetAttachment(arg0 context.Context, arg1 string) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockStoreMockRecorder) GetAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockStore)(nil).GetAttachment), arg0, arg1)
+}
+
+// InsertAttachment mocks base method.
+func (m *MockStore) InsertAttachment(arg0 context.Context, arg1 *model.Attachment) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAttachment indicates an expected call of InsertAttachment.
+func (mr *MockStoreMockRecorder) InsertAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAttachment", reflect.TypeOf((*MockStore)(nil).InsertAttachment), arg0, arg1)
+}
+
+// ListAttachments mocks base method.
+func (m *MockStore) ListAttachments(arg0 context.Context, arg1 string) ([]model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttachments", arg0, arg1)
+	ret0, _ := ret[0].([]model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachments indicates an expected call of ListAttachments.
+func (mr *MockStoreMockRecorder) ListAttachments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachments", reflect.TypeOf((*MockStore)(nil).ListAttachments), arg0, arg1)
+}
+
+// ListAttachmentsByMessages mocks base method.
+func (m *MockStore) ListAttachmentsByMessages(arg0 context.Context, arg1 []string) ([]model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttachmentsByMessages", arg0, arg1)
+	ret0, _ := ret[0].([]model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachmentsByMessages indicates an expected call of ListAttachmentsByMessages.
+func (mr *MockStoreMockRecorder) ListAttachmentsByMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachmentsByMessages", reflect.TypeOf((*MockStore)(nil).ListAttachmentsByMessages), arg0, arg1)
+}
+
+// ListOrphanedBlobs mocks base method.
+func (m *MockStore) ListOrphanedBlobs(arg0 context.Context, arg1 int) ([]model.OrphanedBlob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrphanedBlobs", arg0, arg1)
+	ret0, _ := ret[0].([]model.OrphanedBlob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrphanedBlobs indicates an expected call of ListOrphanedBlobs.
+func (mr *MockStoreMockRecorder) ListOrphanedBlobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrphanedBlobs", reflect.TypeOf((*MockStore)(nil).ListOrphanedBlobs), arg0, arg1)
+}
+
+// MessageExist mocks base method.
+func (m *MockStore) MessageExist(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MessageExist", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MessageExist indicates an expected call of MessageExist.
+func (mr *MockStoreMockRecorder) MessageExist(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageExist", reflect.TypeOf((*MockStore)(nil).MessageExist), arg0, arg1, arg2)
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+const (
+	ProcessingPending = "pending"
+	ProcessingRunning = "running"
+	ProcessingDone    = "done"
+	ProcessingFailed  = "failed"
+)
+
+// Attachment is a file attached to a message. Its content is kept in a blob store
+// under StorageKey, and URL is a signed, expiring link to download it.
+//
+// Images are processed in the background: ProcessingStatus is set for them, and
+// once processing is done Width and Height hold their dimensions as displayed and
+// Thumbnails their previews. It is nil for other files.
+type Attachment struct {
+	ID                  *string     `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	MessageID           *string     `json:"message_id" db:"message_id"`
+	ChatID              *string     `json:"chat_id" db:"chat_id"`
+	Name                *string     `json:"name" db:"name"`
+	Size                *int64      `json:"size" db:"size"`
+	ContentType         *string     `json:"content_type" db:"content_type"`
+	SHA256              *string     `json:"sha256" db:"sha256" gorm:"column:sha256"`
+	StorageKey          *string     `json:"-" db:"storage_key"`
+	Width               *int        `json:"width,omitempty" db:"width"`
+	Height              *int        `json:"height,omitempty" db:"height"`
+	ProcessingStatus    *string     `json:"processing_status,omitempty" db:"processing_status"`
+	ProcessingError     *string     `json:"-" db:"processing_error"`
+	ProcessingStartedAt *time.Time  `json:"-" db:"processing_started_at"`
+	CreatedAt           *time.Time  `json:"created_at" db:"created_at"`
+	Thumbnails          []Thumbnail `json:"thumbnails,omitempty" gorm:"foreignKey:AttachmentID"`
+	URL                 *string     `json:"url,omitempty" gorm:"-"`
+}
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// Thumbnail is a downscaled preview of an image attachment, fitting a square of
+// the size its name stands for.
+type Thumbnail struct {
+	AttachmentID *string `json:"-" db:"attachment_id" gorm:"primaryKey"`
+	Name         *string `json:"name" db:"name" gorm:"primaryKey"`
+	Width        *int    `json:"width" db:"width"`
+	Height       *int    `json:"height" db:"height"`
+	Size         *int64  `json:"size" db:"size"`
+	ContentType  *string `json:"content_type" db:"content_type"`
+	StorageKey   *string `json:"-" db:"storage_key"`
+	URL          *string `json:"url,omitempty" gorm:"-"`
+}
+
+func (Thumbnail) TableName() string {
+	return "attachment_thumbnails"
+}
+
+// OrphanedBlob is the blob of a deleted attachment waiting to be deleted.
+type OrphanedBlob struct {
+	ID         *string `db:"id" gorm:"primaryKey;autoIncrement"`
+	StorageKey *string `db:"storage_key"`
+}
+
+func (OrphanedBlob) TableName() string {
+	return "orphaned_blobs"
+}
@@ -0,0 +1,147 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// processingStaleAfter is how long an image may be processed before it is
+	// considered abandoned by a worker that died and is processed again.
+	processingStaleAfter = 5 * time.Minute
+
+	maxProcessingErrorLength = 1024
+)
+
+// Processor generates the thumbnails of uploaded images in the background and
+// records their dimensions. Any instance of the service may process an image.
+type Processor struct {
+	store    Store
+	blobs    blob.BlobStore
+	interval time.Duration
+}
+
+func NewProcessor(s Store, blobs blob.BlobStore, interval time.Duration) *Processor {
+	return &Processor{
+		store:    s,
+		blobs:    blobs,
+		interval: interval,
+	}
+}
+
+// Listen processes uploaded images until the context is cancelled.
+func (p *Processor) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("attachment processor starting")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := p.ProcessNext(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to process attachment", zap.Error(err))
+		}
+
+		// Another image may be waiting right behind the one processed.
+		if err == nil && processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("attachment processor stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext claims the oldest image waiting to be processed and processes it,
+// reporting whether there was one. An image that cannot be processed is marked as
+// failed; the returned error only reports failures to claim or record one.
+func (p *Processor) ProcessNext(ctx context.Context) (bool, error) {
+	att, err := p.store.ClaimProcessing(ctx, processingStaleAfter)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	runErr := p.process(ctx, att)
+	if runErr != nil && ctx.Err() != nil {
+		return true, runErr
+	}
+
+	att.ProcessingStatus = pointer.ToString(model.ProcessingDone)
+	att.ProcessingError = nil
+	if runErr != nil {
+		logging.From(ctx).Error("attachment processing failed", zap.String("attachment_id", pointer.GetString(att.ID)), zap.Error(runErr))
+
+		att.ProcessingStatus = pointer.ToString(model.ProcessingFailed)
+		att.ProcessingError = pointer.ToString(truncate(runErr.Error(), maxProcessingErrorLength))
+		att.Thumbnails = nil
+	}
+
+	return true, p.store.CompleteProcessing(ctx, att)
+}
+
+func (p *Processor) process(ctx context.Context, att *model.Attachment) error {
+	r, err := p.blobs.Open(ctx, pointer.GetString(att.StorageKey))
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	width, height, thumbs, err := makeThumbnails(content, pointer.GetString(att.ContentType))
+	if err != nil {
+		return err
+	}
+
+	att.Width = pointer.ToInt(width)
+	att.Height = pointer.ToInt(height)
+	att.Thumbnails = make([]model.Thumbnail, 0, len(thumbs))
+
+	for _, t := range thumbs {
+		key := pointer.GetString(att.StorageKey) + "-" + t.name
+		if err := p.blobs.Put(ctx, key, bytes.NewReader(t.data), int64(len(t.data)), t.contentType); err != nil {
+			return err
+		}
+
+		att.Thumbnails = append(att.Thumbnails, model.Thumbnail{
+			AttachmentID: att.ID,
+			Name:         pointer.ToString(t.name),
+			Width:        pointer.ToInt(t.width),
+			Height:       pointer.ToInt(t.height),
+			Size:         pointer.ToInt64(int64(len(t.data))),
+			ContentType:  pointer.ToString(t.contentType),
+			StorageKey:   pointer.ToString(key),
+		})
+	}
+
+	return nil
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,272 @@
+package attachments_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments"
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// gpsLatitude is the latitude written into the GPS metadata of test photos, as
+// three little-endian rationals.
+var gpsLatitude = []byte{
+	55, 0, 0, 0, 1, 0, 0, 0,
+	45, 0, 0, 0, 1, 0, 0, 0,
+	0xd2, 0x04, 0, 0, 100, 0, 0, 0,
+}
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(width, height)))
+	return buf.Bytes()
+}
+
+// testJPEG encodes a JPEG photo whose Exif metadata holds the orientation and a
+// GPS latitude.
+func testJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(width, height), nil))
+	img := buf.Bytes()
+
+	le := binary.LittleEndian
+	entry := func(b []byte, tag, typ uint16, count, value uint32) []byte {
+		b = le.AppendUint16(b, tag)
+		b = le.AppendUint16(b, typ)
+		b = le.AppendUint32(b, count)
+		return le.AppendUint32(b, value)
+	}
+
+	// IFD0 at 8 holds the orientation and points to the GPS IFD at 38, whose
+	// latitude is stored out of line at 68.
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = entry(tiff, 0x0112, 3, 1, uint32(orientation))
+	tiff = entry(tiff, 0x8825, 4, 1, 38)
+	tiff = le.AppendUint32(tiff, 0)
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = entry(tiff, 0x0001, 2, 2, 'N')
+	tiff = entry(tiff, 0x0002, 5, 3, 68)
+	tiff = le.AppendUint32(tiff, 0)
+	tiff = append(tiff, gpsLatitude...)
+
+	app1 := []byte{0xff, 0xe1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(2+6+len(tiff)))
+	app1 = append(app1, "Exif\x00\x00"...)
+	app1 = append(app1, tiff...)
+
+	out := append([]byte{}, img[:2]...)
+	out = append(out, app1...)
+	return append(out, img[2:]...)
+}
+
+func TestProcessor_ProcessNext_Success(t *testing.T) {
+	type size struct {
+		name          string
+		width, height int
+	}
+
+	tests := []struct {
+		name           string
+		content        func(t *testing.T) []byte
+		contentType    string
+		wantWidth      int
+		wantHeight     int
+		wantThumbnails []size
+	}{
+		{
+			name:        "png",
+			content:     func(t *testing.T) []byte { return testPNG(t, 600, 300) },
+			contentType: "image/png",
+			wantWidth:   600,
+			wantHeight:  300,
+			wantThumbnails: []size{
+				{name: "small", width: 160, height: 80},
+				{name: "medium", width: 480, height: 240},
+			},
+		},
+		{
+			name:        "rotated jpeg",
+			content:     func(t *testing.T) []byte { return testJPEG(t, 600, 300, 6) },
+			contentType: "image/jpeg",
+			wantWidth:   300,
+			wantHeight:  600,
+			wantThumbnails: []size{
+				{name: "small", width: 80, height: 160},
+				{name: "medium", width: 240, height: 480},
+			},
+		},
+		{
+			name:        "smaller than every thumbnail",
+			content:     func(t *testing.T) []byte { return testPNG(t, 100, 50) },
+			contentType: "image/png",
+			wantWidth:   100,
+			wantHeight:  50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			blobs := blob.NewLocal(t.TempDir())
+			content := tt.content(t)
+			require.NoError(t, blobs.Put(ctx, "key", bytes.NewReader(content), int64(len(content)), tt.contentType))
+
+			var completed *model.Attachment
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().ClaimProcessing(gomock.Any(), gomock.Any()).Return(&model.Attachment{
+				ID:               pointer.ToString("1"),
+				ContentType:      pointer.ToString(tt.contentType),
+				StorageKey:       pointer.ToString("key"),
+				ProcessingStatus: pointer.ToString(model.ProcessingRunning),
+			}, nil).Times(1)
+			s.EXPECT().CompleteProcessing(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, a *model.Attachment) error {
+					completed = a
+					return nil
+				}).Times(1)
+
+			processed, err := attachments.NewProcessor(s, blobs, time.Second).ProcessNext(ctx)
+			require.NoError(t, err)
+			assert.True(t, processed)
+
+			require.NotNil(t, completed)
+			assert.Equal(t, model.ProcessingDone, pointer.GetString(completed.ProcessingStatus))
+			assert.Equal(t, tt.wantWidth, pointer.GetInt(completed.Width))
+			assert.Equal(t, tt.wantHeight, pointer.GetInt(completed.Height))
+			require.Len(t, completed.Thumbnails, len(tt.wantThumbnails))
+
+			for i, want := range tt.wantThumbnails {
+				th := completed.Thumbnails[i]
+				assert.Equal(t, want.name, pointer.GetString(th.Name))
+				assert.Equal(t, want.width, pointer.GetInt(th.Width))
+				assert.Equal(t, want.height, pointer.GetInt(th.Height))
+				assert.Equal(t, tt.contentType, pointer.GetString(th.ContentType))
+				assert.Equal(t, "key-"+want.name, pointer.GetString(th.StorageKey))
+
+				r, err := blobs.Open(ctx, pointer.GetString(th.StorageKey))
+				require.NoError(t, err)
+				b, err := io.ReadAll(r)
+				r.Close()
+				require.NoError(t, err)
+				assert.Equal(t, pointer.GetInt64(th.Size), int64(len(b)))
+
+				cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+				require.NoError(t, err)
+				assert.Equal(t, want.width, cfg.Width)
+				assert.Equal(t, want.height, cfg.Height)
+			}
+		})
+	}
+}
+
+func TestProcessor_ProcessNext_Error(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(s *mocks.MockStore)
+		wantProcessed bool
+		wantErr       error
+	}{
+		{
+			name: "nothing to process",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimProcessing(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).Times(1)
+			},
+		},
+		{
+			name: "claim failed",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimProcessing(gomock.Any(), gomock.Any()).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantErr: errors.Error("test fail"),
+		},
+		{
+			name: "corrupt image",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimProcessing(gomock.Any(), gomock.Any()).Return(&model.Attachment{
+					ID:          pointer.ToString("1"),
+					ContentType: pointer.ToString("image/png"),
+					StorageKey:  pointer.ToString("corrupt"),
+				}, nil).Times(1)
+				s.EXPECT().CompleteProcessing(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, a *model.Attachment) error {
+						assert.Equal(t, model.ProcessingFailed, pointer.GetString(a.ProcessingStatus))
+						assert.NotEmpty(t, pointer.GetString(a.ProcessingError))
+						assert.Nil(t, a.Width)
+						assert.Empty(t, a.Thumbnails)
+						return nil
+					}).Times(1)
+			},
+			wantProcessed: true,
+		},
+		{
+			name: "missing blob",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimProcessing(gomock.Any(), gomock.Any()).Return(&model.Attachment{
+					ID:          pointer.ToString("1"),
+					ContentType: pointer.ToString("image/png"),
+					StorageKey:  pointer.ToString("missing"),
+				}, nil).Times(1)
+				s.EXPECT().CompleteProcessing(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, a *model.Attachment) error {
+						assert.Equal(t, model.ProcessingFailed, pointer.GetString(a.ProcessingStatus))
+						return nil
+					}).Times(1)
+			},
+			wantProcessed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			blobs := blob.NewLocal(t.TempDir())
+			require.NoError(t, blobs.Put(ctx, "corrupt", bytes.NewReader([]byte(pngHeader+"garbage")), 15, "image/png"))
+
+			s := mocks.NewMockStore(ctrl)
+			tt.setup(s)
+
+			processed, err := attachments.NewProcessor(s, blobs, time.Second).ProcessNext(ctx)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantProcessed, processed)
+		})
+	}
+}
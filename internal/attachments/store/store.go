@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+func (s *Store) InsertAttachment(ctx context.Context, a *model.Attachment) (*model.Attachment, error) {
+	if err := s.db.WithContext(ctx).Create(a).Error; err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Store) GetAttachment(ctx context.Context, id string) (*model.Attachment, error) {
+	var a model.Attachment
+
+	if err := s.db.WithContext(ctx).Preload("Thumbnails", orderThumbnails).Where("id = ?", id).Take(&a).Error; err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (s *Store) ListAttachments(ctx context.Context, messageID string) ([]model.Attachment, error) {
+	var a []model.Attachment
+
+	err := s.db.WithContext(ctx).Preload("Thumbnails", orderThumbnails).
+		Where("message_id = ?", messageID).
+		Order("id").
+		Find(&a).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (s *Store) ListAttachmentsByMessages(ctx context.Context, messageIDs []string) ([]model.Attachment, error) {
+	var a []model.Attachment
+
+	err := s.db.WithContext(ctx).Preload("Thumbnails", orderThumbnails).
+		Where("message_id IN ?", messageIDs).
+		Order("id").
+		Find(&a).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DeleteAttachment deletes an attachment. Its blob is queued for deletion by a
+// trigger in the same transaction.
+func (s *Store) DeleteAttachment(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&model.Attachment{}, "id = ?", id).Error
+}
+
+func (s *Store) MessageExist(ctx context.Context, chatID, messageID string) (bool, error) {
+	var exists bool
+	err := s.db.WithContext(ctx).Table("messages").
+		Select("count(*) > 0").
+		Where("id = ? AND chat_id = ?", messageID, chatID).
+		Find(&exists).Error
+	return exists, err
+}
+
+// ListOrphanedBlobs returns up to limit blobs of deleted attachments, oldest first.
+func (s *Store) ListOrphanedBlobs(ctx context.Context, limit int) ([]model.OrphanedBlob, error) {
+	var b []model.OrphanedBlob
+
+	if err := s.db.WithContext(ctx).Order("id").Limit(limit).Find(&b).Error; err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (s *Store) DeleteOrphanedBlob(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&model.OrphanedBlob{}, "id = ?", id).Error
+}
+
+// ClaimProcessing marks the oldest image waiting to be processed as running and
+// returns it. Images whose processing started more than staleAfter ago are
+// claimed again. It returns gorm.ErrRecordNotFound when there is nothing to process.
+func (s *Store) ClaimProcessing(ctx context.Context, staleAfter time.Duration) (*model.Attachment, error) {
+	var a model.Attachment
+
+	res := s.db.WithContext(ctx).Raw(`
+		UPDATE attachments
+		SET processing_status = ?, processing_started_at = now()
+		WHERE id = (
+			SELECT id FROM attachments
+			WHERE processing_status = ?
+				OR (processing_status = ? AND processing_started_at < now() - make_interval(secs => ?))
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.ProcessingRunning, model.ProcessingPending, model.ProcessingRunning, staleAfter.Seconds(),
+	).Scan(&a)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &a, nil
+}
+
+// CompleteProcessing records the outcome of processing an image together with its
+// thumbnails, replacing those of an earlier attempt.
+func (s *Store) CompleteProcessing(ctx context.Context, a *model.Attachment) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(a).
+			Select("width", "height", "processing_status", "processing_error").
+			Omit(clause.Associations).
+			Updates(a).Error
+		if err != nil {
+			return err
+		}
+
+		if len(a.Thumbnails) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&a.Thumbnails).Error
+	})
+}
+
+func orderThumbnails(db *gorm.DB) *gorm.DB {
+	return db.Order("width")
+}
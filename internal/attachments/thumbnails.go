@@ -0,0 +1,205 @@
+package attachments
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"golang.org/x/image/draw"
+)
+
+const errImageTooLarge = errors.Error("image has too many pixels to process")
+
+const (
+	// maxPixels bounds the size of the images decoded for thumbnails, since a small
+	// file can decode to a huge image.
+	maxPixels = 50_000_000
+
+	jpegQuality = 80
+)
+
+// thumbnailSize is a named square that thumbnails are scaled down to fit.
+type thumbnailSize struct {
+	name string
+	edge int
+}
+
+// thumbnailSizes are the thumbnails generated for images. Sizes the image is not
+// larger than are skipped, since the image itself serves as that preview.
+var thumbnailSizes = []thumbnailSize{
+	{name: "small", edge: 160},
+	{name: "medium", edge: 480},
+	{name: "large", edge: 1280},
+}
+
+// isImage reports whether thumbnails are generated for files of the content type.
+func isImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// thumbnail is one encoded thumbnail of an image.
+type thumbnail struct {
+	name        string
+	width       int
+	height      int
+	contentType string
+	data        []byte
+}
+
+// makeThumbnails decodes an image, turns it upright according to its Exif
+// orientation and scales it down to every thumbnail size it is larger than. It
+// returns the dimensions of the upright image along with the thumbnails.
+// JPEG images get JPEG thumbnails; PNG and GIF images, which may be transparent,
+// get PNG thumbnails of their first frame.
+func makeThumbnails(content []byte, contentType string) (int, int, []thumbnail, error) {
+	r := bytes.NewReader(content)
+
+	cfg, err := decodeConfig(r, contentType)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return 0, 0, nil, errImageTooLarge
+	}
+
+	img, err := decode(io.NewSectionReader(r, 0, r.Size()), contentType)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	o := orientation(r, contentType)
+
+	// Images are scaled before being turned upright, which is much cheaper on
+	// the smaller thumbnails.
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if swapsAxes(o) {
+		width, height = height, width
+	}
+
+	var thumbs []thumbnail
+	for _, size := range thumbnailSizes {
+		if width <= size.edge && height <= size.edge {
+			continue
+		}
+
+		w, h := fit(width, height, size.edge)
+		sw, sh := w, h
+		if swapsAxes(o) {
+			sw, sh = h, w
+		}
+		scaled := image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+		dst := orient(scaled, o)
+
+		var buf bytes.Buffer
+		thumbType := "image/png"
+		if contentType == "image/jpeg" {
+			thumbType = "image/jpeg"
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
+		thumbs = append(thumbs, thumbnail{
+			name:        size.name,
+			width:       w,
+			height:      h,
+			contentType: thumbType,
+			data:        buf.Bytes(),
+		})
+	}
+
+	return width, height, thumbs, nil
+}
+
+func decodeConfig(r io.Reader, contentType string) (image.Config, error) {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.DecodeConfig(r)
+	case "image/png":
+		return png.DecodeConfig(r)
+	default:
+		return gif.DecodeConfig(r)
+	}
+}
+
+func decode(r io.Reader, contentType string) (image.Image, error) {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Decode(r)
+	case "image/png":
+		return png.Decode(r)
+	default:
+		return gif.Decode(r)
+	}
+}
+
+// fit scales width and height down to fit a square of the given edge, keeping the
+// aspect ratio.
+func fit(width, height, edge int) (int, int) {
+	if width >= height {
+		return edge, max(1, height*edge/width)
+	}
+	return max(1, width*edge/height), edge
+}
+
+// swapsAxes reports whether turning an image of the Exif orientation upright
+// swaps its width and height.
+func swapsAxes(o int) bool {
+	return o >= 5 && o <= 8
+}
+
+// orient turns an image upright according to its Exif orientation: 2 to 8 are
+// the combinations of mirroring and rotating by multiples of 90 degrees.
+func orient(img image.Image, o int) image.Image {
+	if o <= 1 || o > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if swapsAxes(o) {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch o {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	return dst
+}
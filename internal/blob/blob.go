@@ -0,0 +1,22 @@
+package blob
+
+import (
+	"context"
+	"io"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+)
+
+const ErrBlobNotFound = errors.Error("blob_not_found: blob not found")
+
+// BlobStore keeps binary objects under keys chosen by the caller. Keys are slash
+// separated paths of URL safe segments.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns a reader of the blob stored under key. Seeking is cheap, so
+	// ranges of large blobs can be read without reading what precedes them.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,154 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/blob"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal stand-in for an S3 compatible service keeping objects in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") ||
+		r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.URL.Path
+	switch r.Method {
+	case http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = b
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		b, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(b))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func stores(t *testing.T) map[string]blob.BlobStore {
+	t.Helper()
+
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}, types: map[string]string{}})
+	t.Cleanup(srv.Close)
+
+	return map[string]blob.BlobStore{
+		"local": blob.NewLocal(t.TempDir()),
+		"s3": blob.NewS3(blob.S3Config{
+			Endpoint:  srv.URL,
+			Region:    "us-east-1",
+			Bucket:    "attachments",
+			AccessKey: "access",
+			SecretKey: "secret",
+		}, srv.Client()),
+	}
+}
+
+func TestBlobStore_PutOpen_Success(t *testing.T) {
+	content := []byte("hello, attachments")
+
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			err := s.Put(ctx, "chats/1/a file.txt", bytes.NewReader(content), int64(len(content)), "text/plain")
+			require.NoError(t, err)
+
+			r, err := s.Open(ctx, "chats/1/a file.txt")
+			require.NoError(t, err)
+			defer r.Close()
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, content, b)
+
+			size, err := r.Seek(0, io.SeekEnd)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), size)
+
+			_, err = r.Seek(7, io.SeekStart)
+			require.NoError(t, err)
+
+			b = make([]byte, 5)
+			_, err = io.ReadFull(r, b)
+			require.NoError(t, err)
+			assert.Equal(t, "attac", string(b))
+		})
+	}
+}
+
+func TestBlobStore_Delete_Success(t *testing.T) {
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			require.NoError(t, s.Put(ctx, "a/b", strings.NewReader("x"), 1, ""))
+			require.NoError(t, s.Delete(ctx, "a/b"))
+			require.NoError(t, s.Delete(ctx, "a/b"))
+
+			_, err := s.Open(ctx, "a/b")
+			assert.ErrorIs(t, err, blob.ErrBlobNotFound)
+		})
+	}
+}
+
+func TestBlobStore_Open_Error(t *testing.T) {
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Open(context.Background(), "missing")
+			assert.ErrorIs(t, err, blob.ErrBlobNotFound)
+		})
+	}
+}
+
+func TestBlobStore_Put_Error(t *testing.T) {
+	for name, s := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			err := s.Put(context.Background(), "short", strings.NewReader("abc"), 10, "")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLocal_Put_InvalidKey(t *testing.T) {
+	l := blob.NewLocal(t.TempDir())
+
+	for _, key := range []string{"", "/etc/passwd", "../escape", "a//b", "a/./b"} {
+		err := l.Put(context.Background(), key, strings.NewReader("x"), 1, "")
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,157 @@
+package changes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ErrInvalidToken = errors.Error("invalid_sync_token: sync token is malformed")
+	ErrTokenExpired = errors.Error("sync_token_expired: the changes since this sync token were trimmed, a full resync is required")
+)
+
+const (
+	// trimInterval is how often changes past the retention period are trimmed.
+	trimInterval = time.Hour
+	// trimBatch bounds the number of changes trimmed in one transaction.
+	trimBatch = 1000
+)
+
+type Store interface {
+	ListChanges(ctx context.Context, after model.Position, limit int) (*model.Log, error)
+	Head(ctx context.Context) (*model.Position, error)
+	Trim(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// ChangeService lets clients catch up on the creates, edits and deletes of chats
+// and messages they missed. The changes are read from the outbox, where every
+// event is recorded along with its change, and kept there for the retention period.
+// Chats have no members, so the changes are the same for every client.
+type ChangeService struct {
+	store     Store
+	retention time.Duration
+}
+
+func New(s Store, retention time.Duration) *ChangeService {
+	return &ChangeService{
+		store:     s,
+		retention: retention,
+	}
+}
+
+// Sync returns up to limit changes recorded after the position a token points
+// to, oldest first, with the token to sync from next. Without a token no changes
+// are returned, only the token of the latest position: clients take it before
+// loading everything in full, and sync from it afterwards.
+func (c *ChangeService) Sync(ctx context.Context, since string, limit int64) (*model.Page, error) {
+	if since == "" {
+		head, err := c.store.Head(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &model.Page{Changes: []events.Event{}, Next: encodeToken(*head)}, nil
+	}
+
+	after, err := decodeToken(since)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more change than asked for tells whether there are more.
+	l, err := c.store.ListChanges(ctx, after, int(limit)+1)
+	if err != nil {
+		return nil, err
+	}
+	if after.Before(l.Horizon) {
+		return nil, ErrTokenExpired
+	}
+
+	page := &model.Page{Changes: make([]events.Event, 0, len(l.Changes)), Next: since}
+	if int64(len(l.Changes)) > limit {
+		l.Changes = l.Changes[:limit]
+		page.HasMore = true
+	}
+	for _, ch := range l.Changes {
+		page.Changes = append(page.Changes, ch.ToEvent())
+	}
+	if len(l.Changes) > 0 {
+		page.Next = encodeToken(l.Changes[len(l.Changes)-1].Position())
+	}
+
+	return page, nil
+}
+
+// Listen trims changes past the retention period until the context is cancelled.
+func (c *ChangeService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("change log trimmer starting")
+
+	ticker := time.NewTicker(trimInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Trim(ctx); err != nil {
+			logging.From(ctx).Error("failed to trim the change log", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("change log trimmer stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Trim trims the changes recorded before the retention period, returning how many
+// were trimmed. Changes not published yet are kept until they are, but clients can
+// no longer sync them.
+func (c *ChangeService) Trim(ctx context.Context) (int, error) {
+	before := time.Now().Add(-c.retention)
+
+	var total int
+	for {
+		n, err := c.store.Trim(ctx, before, trimBatch)
+		total += n
+		if err != nil || n < trimBatch {
+			return total, err
+		}
+	}
+}
+
+// encodeToken returns the opaque sync token of a position.
+func encodeToken(p model.Position) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", p.TxID, p.ID)))
+}
+
+func decodeToken(token string) (model.Position, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return model.Position{}, ErrInvalidToken
+	}
+
+	txid, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return model.Position{}, ErrInvalidToken
+	}
+
+	var p model.Position
+	if p.TxID, err = strconv.ParseUint(txid, 10, 64); err != nil {
+		return model.Position{}, ErrInvalidToken
+	}
+	if p.ID, err = strconv.ParseInt(id, 10, 64); err != nil || p.ID < 0 {
+		return model.Position{}, ErrInvalidToken
+	}
+
+	return p, nil
+}
@@ -0,0 +1,172 @@
+package changes_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	outboxmodel "github.com/Polilo-User/test-task-hitalent/internal/outbox/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func token(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func change(txid uint64, id int64) model.Change {
+	return model.Change{
+		Record: outboxmodel.Record{
+			ID:      id,
+			ChatID:  "1",
+			Event:   "message.created",
+			Payload: json.RawMessage(`{}`),
+		},
+		TxID: txid,
+	}
+}
+
+func TestChangeService_Sync_Success(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int64
+		changes     []model.Change
+		wantIDs     []string
+		wantNext    string
+		wantHasMore bool
+	}{
+		{
+			name:        "full page",
+			limit:       2,
+			changes:     []model.Change{change(10, 3), change(12, 2), change(12, 5)},
+			wantIDs:     []string{"3", "2"},
+			wantNext:    token("12.2"),
+			wantHasMore: true,
+		},
+		{
+			name:     "last page",
+			limit:    5,
+			changes:  []model.Change{change(10, 3)},
+			wantIDs:  []string{"3"},
+			wantNext: token("10.3"),
+		},
+		{
+			name:     "no changes",
+			limit:    5,
+			wantIDs:  []string{},
+			wantNext: token("9.7"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 9, ID: 7}, int(tt.limit)+1).
+				Return(&model.Log{Horizon: model.Position{TxID: 9, ID: 7}, Changes: tt.changes}, nil).Times(1)
+
+			page, err := changes.New(s, time.Hour).Sync(context.Background(), token("9.7"), tt.limit)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, e := range page.Changes {
+				ids = append(ids, e.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantNext, page.Next)
+			assert.Equal(t, tt.wantHasMore, page.HasMore)
+		})
+	}
+}
+
+func TestChangeService_Sync_Head(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().Head(gomock.Any()).Return(&model.Position{TxID: 42, ID: 8}, nil).Times(1)
+
+	page, err := changes.New(s, time.Hour).Sync(context.Background(), "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.Equal(t, token("42.8"), page.Next)
+	assert.False(t, page.HasMore)
+}
+
+func TestChangeService_Sync_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		since   string
+		log     *model.Log
+		logErr  error
+		wantErr error
+	}{
+		{
+			name:    "not base64",
+			since:   "!!",
+			wantErr: changes.ErrInvalidToken,
+		},
+		{
+			name:    "no separator",
+			since:   token("12"),
+			wantErr: changes.ErrInvalidToken,
+		},
+		{
+			name:    "negative id",
+			since:   token("12.-1"),
+			wantErr: changes.ErrInvalidToken,
+		},
+		{
+			name:    "trimmed",
+			since:   token("12.5"),
+			log:     &model.Log{Horizon: model.Position{TxID: 12, ID: 6}},
+			wantErr: changes.ErrTokenExpired,
+		},
+		{
+			name:    "fails",
+			since:   token("12.5"),
+			logErr:  errors.Error("test fail"),
+			wantErr: errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.log != nil || tt.logErr != nil {
+				s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 12, ID: 5}, 11).Return(tt.log, tt.logErr).Times(1)
+			}
+
+			page, err := changes.New(s, time.Hour).Sync(context.Background(), tt.since, 10)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, page)
+		})
+	}
+}
+
+func TestChangeService_Trim(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	gomock.InOrder(
+		s.EXPECT().Trim(gomock.Any(), gomock.Any(), 1000).Return(1000, nil),
+		s.EXPECT().Trim(gomock.Any(), gomock.Any(), 1000).Return(1000, nil),
+		s.EXPECT().Trim(gomock.Any(), gomock.Any(), 1000).Return(3, nil),
+	)
+
+	n, err := changes.New(s, time.Hour).Trim(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2003, n)
+}
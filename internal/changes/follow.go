@@ -0,0 +1,106 @@
+package changes
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+
+	"go.uber.org/zap"
+)
+
+// Sink receives the changes read by a Follower.
+type Sink interface {
+	Publish(ctx context.Context, e events.Event) error
+	// Reset tells the sink that changes were trimmed before they could be read,
+	// so it missed them.
+	Reset()
+}
+
+// Follower tails the change log from the moment it starts, handing every change
+// to its sink in log order. Every replica runs its own, so each of them sees all
+// the changes whichever replica wrote or relayed them. A change is read once its
+// transaction and every older one have ended.
+type Follower struct {
+	store    Store
+	sink     Sink
+	interval time.Duration
+	batch    int
+
+	// at is the position of the last change handed to the sink.
+	at *model.Position
+}
+
+func NewFollower(s Store, sink Sink, interval time.Duration, batch int) *Follower {
+	return &Follower{
+		store:    s,
+		sink:     sink,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Listen follows the change log until the context is cancelled.
+func (f *Follower) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("change log follower starting")
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := f.FollowBatch(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to follow the change log", zap.Error(err))
+		}
+
+		// A full batch means more changes are probably waiting, so read again right away.
+		if err == nil && n == f.batch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("change log follower stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// FollowBatch hands the next batch of changes to the sink, returning how many
+// it read. The first batch starts from the head of the log. When the log was
+// trimmed past the changes not read yet, the sink is reset and following goes
+// on from the horizon.
+func (f *Follower) FollowBatch(ctx context.Context) (int, error) {
+	if f.at == nil {
+		head, err := f.store.Head(ctx)
+		if err != nil {
+			return 0, err
+		}
+		f.at = head
+	}
+
+	l, err := f.store.ListChanges(ctx, *f.at, f.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	if f.at.Before(l.Horizon) {
+		logging.From(ctx).Warn("change log was trimmed before it was followed, resetting subscribers")
+		f.sink.Reset()
+		f.at = &l.Horizon
+		return 0, nil
+	}
+
+	for i, ch := range l.Changes {
+		if err := f.sink.Publish(ctx, ch.ToEvent()); err != nil {
+			return i, err
+		}
+		p := ch.Position()
+		f.at = &p
+	}
+
+	return len(l.Changes), nil
+}
@@ -0,0 +1,111 @@
+package changes_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollower_FollowBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	sink := mocks.NewMockSink(ctrl)
+
+	// The first batch starts from the head, the next one from the last change read.
+	s.EXPECT().Head(gomock.Any()).Return(&model.Position{TxID: 9, ID: 7}, nil).Times(1)
+	gomock.InOrder(
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 9, ID: 7}, 2).
+			Return(&model.Log{Changes: []model.Change{change(10, 3), change(11, 8)}}, nil),
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 11, ID: 8}, 2).
+			Return(&model.Log{}, nil),
+	)
+	gomock.InOrder(
+		sink.EXPECT().Publish(gomock.Any(), change(10, 3).ToEvent()).Return(nil),
+		sink.EXPECT().Publish(gomock.Any(), change(11, 8).ToEvent()).Return(nil),
+	)
+
+	f := changes.NewFollower(s, sink, time.Second, 2)
+
+	n, err := f.FollowBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = f.FollowBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestFollower_FollowBatch_PublishFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	sink := mocks.NewMockSink(ctrl)
+
+	s.EXPECT().Head(gomock.Any()).Return(&model.Position{TxID: 9, ID: 7}, nil).Times(1)
+	gomock.InOrder(
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 9, ID: 7}, 10).
+			Return(&model.Log{Changes: []model.Change{change(10, 3), change(11, 8)}}, nil),
+		// The change that failed is published again.
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 10, ID: 3}, 10).
+			Return(&model.Log{Changes: []model.Change{change(11, 8)}}, nil),
+	)
+	gomock.InOrder(
+		sink.EXPECT().Publish(gomock.Any(), change(10, 3).ToEvent()).Return(nil),
+		sink.EXPECT().Publish(gomock.Any(), change(11, 8).ToEvent()).Return(errors.Error("boom")),
+		sink.EXPECT().Publish(gomock.Any(), change(11, 8).ToEvent()).Return(nil),
+	)
+
+	f := changes.NewFollower(s, sink, time.Second, 10)
+
+	n, err := f.FollowBatch(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = f.FollowBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestFollower_FollowBatch_Trimmed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	sink := mocks.NewMockSink(ctrl)
+
+	s.EXPECT().Head(gomock.Any()).Return(&model.Position{TxID: 9, ID: 7}, nil).Times(1)
+	gomock.InOrder(
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 9, ID: 7}, 10).
+			Return(&model.Log{Horizon: model.Position{TxID: 20, ID: 30}, Changes: []model.Change{change(21, 31)}}, nil),
+		// Following goes on from the horizon.
+		s.EXPECT().ListChanges(gomock.Any(), model.Position{TxID: 20, ID: 30}, 10).
+			Return(&model.Log{Horizon: model.Position{TxID: 20, ID: 30}, Changes: []model.Change{change(21, 31)}}, nil),
+	)
+	gomock.InOrder(
+		// Subscribers are reset instead of being handed changes out of order.
+		sink.EXPECT().Reset().Times(1),
+		sink.EXPECT().Publish(gomock.Any(), change(21, 31).ToEvent()).Return(nil),
+	)
+
+	f := changes.NewFollower(s, sink, time.Second, 10)
+
+	n, err := f.FollowBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = f.FollowBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
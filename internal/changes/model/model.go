@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	outboxmodel "github.com/Polilo-User/test-task-hitalent/internal/outbox/model"
+)
+
+// Position is a place in the change log. Changes are ordered by the transaction
+// that recorded them, then by id, which is the order in which they become final.
+type Position struct {
+	TxID uint64 `db:"txid" gorm:"column:txid"`
+	ID   int64  `db:"id"`
+}
+
+// Before reports whether p comes before o in the change log.
+func (p Position) Before(o Position) bool {
+	return p.TxID < o.TxID || (p.TxID == o.TxID && p.ID < o.ID)
+}
+
+// Change is an outbox record read as an entry of the change log.
+type Change struct {
+	outboxmodel.Record
+	TxID uint64 `db:"txid" gorm:"column:txid"`
+}
+
+// Position returns the position of the change in the change log.
+func (c Change) Position() Position {
+	return Position{TxID: c.TxID, ID: c.ID}
+}
+
+// Log is a slice of the change log along with its horizon, the position of the
+// last change trimmed from it.
+type Log struct {
+	Horizon Position
+	Changes []Change
+}
+
+// Page is a page of changes returned to a syncing client. Next is the token to
+// sync from next, and HasMore tells whether more changes are waiting already.
+type Page struct {
+	Changes []events.Event `json:"changes"`
+	Next    string         `json:"next"`
+	HasMore bool           `json:"has_more"`
+}
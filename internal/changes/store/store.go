@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// ListChanges returns up to limit changes following a position, along with the
+// horizon of the log. Both are read from the same snapshot, so a trim cannot go
+// unnoticed. Only changes of transactions older than every running transaction
+// are returned: no change can be committed before them anymore.
+func (s *Store) ListChanges(ctx context.Context, after model.Position, limit int) (*model.Log, error) {
+	var l model.Log
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`SELECT txid::text::bigint AS txid, id FROM outbox_horizon`).
+			Scan(&l.Horizon).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			SELECT id, txid::text::bigint AS txid, chat_id, event, payload, created_at, published_at
+			FROM outbox
+			WHERE (txid, id) > (CAST(? AS text)::xid8, ?)
+				AND txid < pg_snapshot_xmin(pg_current_snapshot())
+			ORDER BY txid, id
+			LIMIT ?`, formatTxID(after.TxID), after.ID, limit).
+			Scan(&l.Changes).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// Head returns the position of the latest change that can be read, or the
+// horizon when the log holds none.
+func (s *Store) Head(ctx context.Context) (*model.Position, error) {
+	var p model.Position
+
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT txid::text::bigint AS txid, id FROM (
+			(SELECT txid, id FROM outbox
+			WHERE txid < pg_snapshot_xmin(pg_current_snapshot())
+			ORDER BY txid DESC, id DESC
+			LIMIT 1)
+			UNION ALL
+			SELECT txid, id FROM outbox_horizon
+		) heads
+		ORDER BY txid DESC, id DESC
+		LIMIT 1`).
+		Scan(&p).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Trim moves the horizon past up to limit of the oldest changes recorded before
+// a time, and deletes those of them that were published. It returns how many
+// changes the horizon moved past.
+func (s *Store) Trim(ctx context.Context, before time.Time, limit int) (int, error) {
+	var trimmed int
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ps []model.Position
+		err := tx.Raw(`
+			SELECT txid::text::bigint AS txid, id
+			FROM outbox
+			WHERE (txid, id) > (SELECT txid, id FROM outbox_horizon)
+				AND txid < pg_snapshot_xmin(pg_current_snapshot())
+				AND created_at < ?
+			ORDER BY txid, id
+			LIMIT ?`, before, limit).
+			Scan(&ps).Error
+		if err != nil {
+			return err
+		}
+		if len(ps) == 0 {
+			return nil
+		}
+		trimmed = len(ps)
+		cut := ps[len(ps)-1]
+
+		err = tx.Exec(`
+			DELETE FROM outbox
+			WHERE (txid, id) <= (CAST(? AS text)::xid8, ?) AND published_at IS NOT NULL`,
+			formatTxID(cut.TxID), cut.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE outbox_horizon SET txid = CAST(? AS text)::xid8, id = ?
+			WHERE (txid, id) < (CAST(? AS text)::xid8, ?)`,
+			formatTxID(cut.TxID), cut.ID, formatTxID(cut.TxID), cut.ID).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return trimmed, nil
+}
+
+// formatTxID formats a transaction id for comparison with an xid8 column, which
+// has no cast from any integer type.
+func formatTxID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
@@ -2,21 +2,44 @@ package chats
 
 import (
 	"context"
+	"math"
 
 	"github.com/Polilo-User/test-task-hitalent/internal/chats/model"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
 	messageModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 const (
 	ErrChatNotFound = errors.Error("chat_not_found: chat not found")
+	ErrInvalidTTL   = errors.Error("invalid_message_ttl: message_ttl must be a positive number of seconds")
+	// ErrInvalidRetention is returned when a chat is given a retention out of range.
+	ErrInvalidRetention = errors.Error("invalid_retention_days: retention_days must be between 1 and 36500")
+	// ErrLegalHold is returned when deleting a chat, or any of its messages, while
+	// the chat is under legal hold.
+	ErrLegalHold = errors.Error("legal_hold: chat is under legal hold")
+)
+
+const (
+	// maxMessageTTL is the longest message TTL a chat can have, in seconds.
+	maxMessageTTL = math.MaxInt32
+	// MaxRetentionDays is the longest retention a chat can have.
+	MaxRetentionDays = 36500
 )
 
 type Store interface {
 	InsertChat(ctx context.Context, user *model.Chat) (*model.Chat, error)
 	GetChat(ctx context.Context, id string) (*model.Chat, error)
+	GetChatVersion(ctx context.Context, id string) (*model.Version, error)
+	ListChats(ctx context.Context, limit int64, after string) ([]model.Chat, error)
+	GetChatsByIDs(ctx context.Context, ids []string) ([]model.Chat, error)
+	UpdateMessageTTL(ctx context.Context, id string, ttl *int64) (*model.Chat, error)
+	UpdateRetention(ctx context.Context, id string, days *int64) (*model.Chat, error)
+	UpdateLegalHold(ctx context.Context, id string, hold bool) (*model.Chat, error)
 	DeleteChat(ctx context.Context, id string) error
 	ChatExist(ctx context.Context, id string) (bool, error)
 }
@@ -25,6 +48,20 @@ type Message interface {
 	GetMessagesByChat(ctx context.Context, id string, limit int64) ([]messageModel.Message, error)
 }
 
+// GetChatOption changes what GetChat loads besides the chat itself.
+type GetChatOption func(*getChatOptions)
+
+type getChatOptions struct {
+	withoutMessages bool
+}
+
+// WithoutMessages makes GetChat skip the message query, leaving Messages empty.
+func WithoutMessages() GetChatOption {
+	return func(o *getChatOptions) {
+		o.withoutMessages = true
+	}
+}
+
 type ChatService struct {
 	store    Store
 	messages Message
@@ -38,15 +75,101 @@ func New(s Store, m Message) *ChatService {
 }
 
 func (c *ChatService) CreateChat(ctx context.Context, chat *model.Chat) (*model.Chat, error) {
+	if chat.MessageTTL != nil && !validTTL(*chat.MessageTTL) {
+		return nil, ErrInvalidTTL
+	}
+	if chat.RetentionDays != nil && !validRetention(*chat.RetentionDays) {
+		return nil, ErrInvalidRetention
+	}
+	// Holds are only ever placed with SetLegalHold.
+	chat.LegalHold = nil
 	return c.store.InsertChat(ctx, chat)
 }
 
-func (c *ChatService) GetChat(ctx context.Context, id string, limit int64) (*model.Chat, error) {
+// SetMessageTTL sets the number of seconds after which new messages of a chat
+// expire, or lets them live forever when ttl is nil. Messages already posted
+// keep the expiry they have.
+func (c *ChatService) SetMessageTTL(ctx context.Context, id string, ttl *int64) (*model.Chat, error) {
+	if ttl != nil && !validTTL(*ttl) {
+		return nil, ErrInvalidTTL
+	}
+
+	ch, err := c.store.UpdateMessageTTL(ctx, id, ttl)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrChatNotFound
+	}
+	return ch, err
+}
+
+func validTTL(ttl int64) bool {
+	return ttl > 0 && ttl <= maxMessageTTL
+}
+
+// SetRetention sets the number of days after which the messages of a chat are
+// purged, overriding the global retention policy, or makes the chat follow the
+// global policy again when days is nil.
+func (c *ChatService) SetRetention(ctx context.Context, id string, days *int64) (*model.Chat, error) {
+	if days != nil && !validRetention(*days) {
+		return nil, ErrInvalidRetention
+	}
+
+	ch, err := c.store.UpdateRetention(ctx, id, days)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrChatNotFound
+	}
+	return ch, err
+}
+
+func validRetention(days int64) bool {
+	return days > 0 && days <= MaxRetentionDays
+}
+
+// SetLegalHold places a chat under legal hold, or lifts the hold. While it is
+// held, the chat and its messages are kept whatever deletes them otherwise: the
+// API, message expiry and retention alike.
+func (c *ChatService) SetLegalHold(ctx context.Context, id string, hold bool) (*model.Chat, error) {
+	ch, err := c.store.UpdateLegalHold(ctx, id, hold)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrChatNotFound
+	}
+	return ch, err
+}
+
+// CheckLegalHold returns ErrLegalHold when a chat is under legal hold, and
+// ErrChatNotFound when there is no such chat.
+func (c *ChatService) CheckLegalHold(ctx context.Context, id string) error {
+	ch, err := c.store.GetChat(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrChatNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if pointer.GetBool(ch.LegalHold) {
+		return ErrLegalHold
+	}
+	return nil
+}
+
+// GetChat returns a chat with up to limit of its messages, unless told otherwise by opts.
+func (c *ChatService) GetChat(ctx context.Context, id string, limit int64, opts ...GetChatOption) (*model.Chat, error) {
+	var o getChatOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	ch, err := c.store.GetChat(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChatNotFound
+		}
 		return nil, err
 	}
 
+	if o.withoutMessages {
+		return ch, nil
+	}
+
 	messages, err := c.messages.GetMessagesByChat(ctx, id, limit)
 	if err != nil {
 		return nil, err
@@ -57,7 +180,41 @@ func (c *ChatService) GetChat(ctx context.Context, id string, limit int64) (*mod
 	return ch, nil
 }
 
+// GetChatVersion returns the current version of a chat, which is cheap enough to
+// check on every poll before loading the chat itself.
+func (c *ChatService) GetChatVersion(ctx context.Context, id string) (*model.Version, error) {
+	v, err := c.store.GetChatVersion(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ListChats returns a page of chats from newest to oldest, without their messages.
+func (c *ChatService) ListChats(ctx context.Context, limit int64, after string) ([]model.Chat, error) {
+	return c.store.ListChats(ctx, limit, after)
+}
+
+// GetChatsByIDs returns the chats with the given ids, without their messages.
+// Unknown ids are skipped.
+func (c *ChatService) GetChatsByIDs(ctx context.Context, ids []string) ([]model.Chat, error) {
+	return c.store.GetChatsByIDs(ctx, ids)
+}
+
+// DeleteChat deletes a chat and its messages, unless it is under legal hold.
+// Deleting a chat that does not exist does nothing.
 func (c *ChatService) DeleteChat(ctx context.Context, id string) error {
+	err := c.CheckLegalHold(ctx, id)
+	if errors.Is(err, ErrChatNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 	return c.store.DeleteChat(ctx, id)
 }
 
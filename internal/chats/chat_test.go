@@ -15,6 +15,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestNew_Success(t *testing.T) {
@@ -211,6 +212,107 @@ func TestChats_GetChat_Error(t *testing.T) {
 	}
 }
 
+func TestChats_GetChat_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c := chats.New(s, m)
+
+	s.EXPECT().GetChat(gomock.Any(), "1").Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	chat, err := c.GetChat(context.Background(), "1", 20)
+
+	assert.ErrorIs(t, err, chats.ErrChatNotFound)
+	assert.Nil(t, chat)
+}
+
+func TestChats_GetChat_WithoutMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c := chats.New(s, m)
+
+	want := &model.Chat{
+		ID:    pointer.ToString("1"),
+		Title: pointer.ToString("testChat"),
+	}
+
+	s.EXPECT().GetChat(gomock.Any(), "1").Return(want, nil).Times(1)
+	m.EXPECT().GetMessagesByChat(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	chat, err := c.GetChat(context.Background(), "1", 20, chats.WithoutMessages())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, chat)
+	assert.Nil(t, chat.Messages)
+}
+
+func TestChats_GetChatVersion_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c := chats.New(s, m)
+
+	want := &model.Version{
+		ChatID:        pointer.ToString("1"),
+		Version:       pointer.ToInt64(2),
+		LastMessageID: pointer.ToString("10"),
+	}
+
+	s.EXPECT().GetChatVersion(gomock.Any(), "1").Return(want, nil).Times(1)
+
+	v, err := c.GetChatVersion(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, v)
+}
+
+func TestChats_GetChatVersion_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:     "not found",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  chats.ErrChatNotFound,
+		},
+		{
+			name:     "store error",
+			storeErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+
+			c := chats.New(s, m)
+
+			s.EXPECT().GetChatVersion(gomock.Any(), "1").Return(nil, tt.storeErr).Times(1)
+
+			v, err := c.GetChatVersion(context.Background(), "1")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, v)
+		})
+	}
+}
+
 func TestChats_DeleteChat_Success(t *testing.T) {
 	type args struct {
 		id string
@@ -239,6 +341,7 @@ func TestChats_DeleteChat_Success(t *testing.T) {
 
 			ctx := context.Background()
 
+			s.EXPECT().GetChat(gomock.Any(), tt.args.id).Return(&model.Chat{ID: pointer.ToString(tt.args.id), LegalHold: pointer.ToBool(false)}, nil).Times(1)
 			s.EXPECT().DeleteChat(gomock.Any(), tt.args.id).Return(nil).Times(1)
 
 			err := c.DeleteChat(ctx, tt.args.id)
@@ -277,6 +380,7 @@ func TestChats_DeleteChat_Error(t *testing.T) {
 
 			ctx := context.Background()
 
+			s.EXPECT().GetChat(gomock.Any(), tt.args.id).Return(&model.Chat{ID: pointer.ToString(tt.args.id)}, nil).Times(1)
 			s.EXPECT().DeleteChat(gomock.Any(), tt.args.id).Return(tt.wantErr).Times(1)
 
 			err := c.DeleteChat(ctx, tt.args.id)
@@ -284,3 +388,229 @@ func TestChats_DeleteChat_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestChats_CreateChat_InvalidTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := chats.New(mocks.NewMockStore(ctrl), mocks.NewMockMessage(ctrl))
+
+	chat, err := c.CreateChat(context.Background(), &model.Chat{
+		Title:      pointer.ToString("testChat"),
+		MessageTTL: pointer.ToInt64(0),
+	})
+	assert.Equal(t, chats.ErrInvalidTTL, err)
+	assert.Nil(t, chat)
+}
+
+func TestChats_SetMessageTTL_Success(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  *int64
+	}{
+		{
+			name: "set",
+			ttl:  pointer.ToInt64(3600),
+		},
+		{
+			name: "cleared",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			want := &model.Chat{ID: pointer.ToString("1"), MessageTTL: tt.ttl}
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().UpdateMessageTTL(gomock.Any(), "1", tt.ttl).Return(want, nil).Times(1)
+
+			chat, err := chats.New(s, mocks.NewMockMessage(ctrl)).SetMessageTTL(context.Background(), "1", tt.ttl)
+			require.NoError(t, err)
+			assert.Equal(t, want, chat)
+		})
+	}
+}
+
+func TestChats_SetMessageTTL_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      *int64
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:    "negative",
+			ttl:     pointer.ToInt64(-1),
+			wantErr: chats.ErrInvalidTTL,
+		},
+		{
+			name:    "too long",
+			ttl:     pointer.ToInt64(1 << 31),
+			wantErr: chats.ErrInvalidTTL,
+		},
+		{
+			name:     "chat not found",
+			ttl:      pointer.ToInt64(60),
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  chats.ErrChatNotFound,
+		},
+		{
+			name:     "fails",
+			ttl:      pointer.ToInt64(60),
+			storeErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.storeErr != nil {
+				s.EXPECT().UpdateMessageTTL(gomock.Any(), "1", tt.ttl).Return(nil, tt.storeErr).Times(1)
+			}
+
+			chat, err := chats.New(s, mocks.NewMockMessage(ctrl)).SetMessageTTL(context.Background(), "1", tt.ttl)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, chat)
+		})
+	}
+}
+
+func TestChats_DeleteChat_LegalHold(t *testing.T) {
+	tests := []struct {
+		name    string
+		chat    *model.Chat
+		getErr  error
+		wantErr error
+	}{
+		{
+			name:    "held",
+			chat:    &model.Chat{ID: pointer.ToString("1"), LegalHold: pointer.ToBool(true)},
+			wantErr: chats.ErrLegalHold,
+		},
+		{
+			name:   "chat not found",
+			getErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:    "fails",
+			getErr:  errors.Error("test fail"),
+			wantErr: errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The chat must never reach the store's delete.
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().GetChat(gomock.Any(), "1").Return(tt.chat, tt.getErr).Times(1)
+
+			err := chats.New(s, mocks.NewMockMessage(ctrl)).DeleteChat(context.Background(), "1")
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestChats_SetRetention(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     *int64
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name: "set",
+			days: pointer.ToInt64(365),
+		},
+		{
+			name: "cleared",
+		},
+		{
+			name:    "zero",
+			days:    pointer.ToInt64(0),
+			wantErr: chats.ErrInvalidRetention,
+		},
+		{
+			name:    "too long",
+			days:    pointer.ToInt64(chats.MaxRetentionDays + 1),
+			wantErr: chats.ErrInvalidRetention,
+		},
+		{
+			name:     "chat not found",
+			days:     pointer.ToInt64(30),
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  chats.ErrChatNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+
+			var want *model.Chat
+			if tt.wantErr == nil {
+				want = &model.Chat{ID: pointer.ToString("1"), RetentionDays: tt.days}
+			}
+			if tt.wantErr == nil || tt.storeErr != nil {
+				s.EXPECT().UpdateRetention(gomock.Any(), "1", tt.days).Return(want, tt.storeErr).Times(1)
+			}
+
+			chat, err := chats.New(s, mocks.NewMockMessage(ctrl)).SetRetention(context.Background(), "1", tt.days)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, want, chat)
+		})
+	}
+}
+
+func TestChats_SetLegalHold(t *testing.T) {
+	tests := []struct {
+		name     string
+		hold     bool
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name: "placed",
+			hold: true,
+		},
+		{
+			name: "lifted",
+		},
+		{
+			name:     "chat not found",
+			hold:     true,
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  chats.ErrChatNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var want *model.Chat
+			if tt.storeErr == nil {
+				want = &model.Chat{ID: pointer.ToString("1"), LegalHold: pointer.ToBool(tt.hold)}
+			}
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().UpdateLegalHold(gomock.Any(), "1", tt.hold).Return(want, tt.storeErr).Times(1)
+
+			chat, err := chats.New(s, mocks.NewMockMessage(ctrl)).SetLegalHold(context.Background(), "1", tt.hold)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, want, chat)
+		})
+	}
+}
@@ -118,6 +118,36 @@ func (mr *MockStoreMockRecorder) GetChat(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockStore)(nil).GetChat), arg0, arg1)
 }
 
+// GetChatVersion mocks base method.
+func (m *MockStore) GetChatVersion(arg0 context.Context, arg1 string) (*model.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatVersion", arg0, arg1)
+	ret0, _ := ret[0].(*model.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatVersion indicates an expected call of GetChatVersion.
+func (mr *MockStoreMockRecorder) GetChatVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatVersion", reflect.TypeOf((*MockStore)(nil).GetChatVersion), arg0, arg1)
+}
+
+// GetChatsByIDs mocks base method.
+func (m *MockStore) GetChatsByIDs(arg0 context.Context, arg1 []string) ([]model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatsByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatsByIDs indicates an expected call of GetChatsByIDs.
+func (mr *MockStoreMockRecorder) GetChatsByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatsByIDs", reflect.TypeOf((*MockStore)(nil).GetChatsByIDs), arg0, arg1)
+}
+
 // InsertChat mocks base method.
 func (m *MockStore) InsertChat(arg0 context.Context, arg1 *model.Chat) (*model.Chat, error) {
 	m.ctrl.T.Helper()
@@ -132,3 +162,63 @@ func (mr *MockStoreMockRecorder) InsertChat(arg0, arg1 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertChat", reflect.TypeOf((*MockStore)(nil).InsertChat), arg0, arg1)
 }
+
+// ListChats mocks base method.
+func (m *MockStore) ListChats(arg0 context.Context, arg1 int64, arg2 string) ([]model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockStoreMockRecorder) ListChats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockStore)(nil).ListChats), arg0, arg1, arg2)
+}
+
+// UpdateLegalHold mocks base method.
+func (m *MockStore) UpdateLegalHold(arg0 context.Context, arg1 string, arg2 bool) (*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLegalHold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLegalHold indicates an expected call of UpdateLegalHold.
+func (mr *MockStoreMockRecorder) UpdateLegalHold(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLegalHold", reflect.TypeOf((*MockStore)(nil).UpdateLegalHold), arg0, arg1, arg2)
+}
+
+// UpdateMessageTTL mocks base method.
+func (m *MockStore) UpdateMessageTTL(arg0 context.Context, arg1 string, arg2 *int64) (*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageTTL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessageTTL indicates an expected call of UpdateMessageTTL.
+func (mr *MockStoreMockRecorder) UpdateMessageTTL(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageTTL", reflect.TypeOf((*MockStore)(nil).UpdateMessageTTL), arg0, arg1, arg2)
+}
+
+// UpdateRetention mocks base method.
+func (m *MockStore) UpdateRetention(arg0 context.Context, arg1 string, arg2 *int64) (*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRetention", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRetention indicates an expected call of UpdateRetention.
+func (mr *MockStoreMockRecorder) UpdateRetention(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRetention", reflect.TypeOf((*MockStore)(nil).UpdateRetention), arg0, arg1, arg2)
+}
@@ -6,9 +6,35 @@ import (
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
 )
 
+// Chat is a chat room. LastSeq is the seq of the latest message stored in it.
+// MessageTTL is the number of seconds after which messages posted to the chat
+// expire, unless they expire at a time of their own. RetentionDays overrides the
+// global retention policy for the chat. While LegalHold is set, neither the chat
+// nor any of its messages can be deleted.
 type Chat struct {
-	ID        *string         `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
-	Title     *string         `json:"title" db:"title"`
-	CreatedAt *time.Time      `json:"created_at" db:"created_at"`
-	Messages  []model.Message `json:"messages"`
+	ID            *string         `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	Title         *string         `json:"title" db:"title"`
+	CreatedAt     *time.Time      `json:"created_at" db:"created_at"`
+	LastSeq       *int64          `json:"last_seq,omitempty" db:"last_seq" gorm:"->;default:null"`
+	MessageTTL    *int64          `json:"message_ttl,omitempty" db:"message_ttl"`
+	RetentionDays *int64          `json:"retention_days,omitempty" db:"retention_days"`
+	LegalHold     *bool           `json:"legal_hold,omitempty" db:"legal_hold" gorm:"default:false"`
+	Messages      []model.Message `json:"messages"`
+
+	// UnreadCount and FirstUnreadID are set on reads by an identified user.
+	UnreadCount   *int64  `json:"unread_count,omitempty" gorm:"-"`
+	FirstUnreadID *string `json:"first_unread_id,omitempty" gorm:"-"`
+}
+
+// Version identifies the state of a chat and its messages. It changes whenever
+// the chat or one of its messages is created, modified or deleted. Messages stop
+// being returned when they expire, before they are deleted, so LastExpiredAt, when
+// the latest of the expired messages still stored expired, tells those states apart.
+type Version struct {
+	ChatID        *string    `json:"chat_id" db:"chat_id"`
+	Version       *int64     `json:"version" db:"version"`
+	UpdatedAt     *time.Time `json:"updated_at" db:"updated_at"`
+	LastMessageID *string    `json:"last_message_id" db:"last_message_id"`
+	LastMessageAt *time.Time `json:"last_message_at" db:"last_message_at"`
+	LastExpiredAt *time.Time `json:"last_expired_at" db:"last_expired_at"`
 }
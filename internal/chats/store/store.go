@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	outboxStore "github.com/Polilo-User/test-task-hitalent/internal/outbox/store"
 	"gorm.io/gorm"
 )
 
@@ -18,7 +20,18 @@ func New(db *gorm.DB) *Store {
 }
 
 func (s *Store) InsertChat(ctx context.Context, c *model.Chat) (*model.Chat, error) {
-	err := s.db.Create(c).Error
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(c).Error; err != nil {
+			return err
+		}
+
+		e, err := events.New(events.ChatCreated, *c.ID, c)
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -35,12 +48,119 @@ func (s *Store) GetChat(ctx context.Context, id string) (*model.Chat, error) {
 	return &c, nil
 }
 
-func (s *Store) DeleteChat(ctx context.Context, id string) error {
-	if err := s.db.Table("chats").Delete(&model.Chat{}, "id = ?", id).Error; err != nil {
-		return err
+// GetChatVersion returns the version of a chat together with its latest message
+// and the latest expiry of its messages that expired but were not deleted yet,
+// without loading any message bodies.
+func (s *Store) GetChatVersion(ctx context.Context, id string) (*model.Version, error) {
+	var v model.Version
+
+	err := s.db.WithContext(ctx).
+		Table("chats AS c").
+		Select("c.id AS chat_id, c.version, c.updated_at, m.id AS last_message_id, m.created_at AS last_message_at, e.expires_at AS last_expired_at").
+		Joins("LEFT JOIN LATERAL (SELECT id, created_at FROM messages WHERE chat_id = c.id ORDER BY id DESC LIMIT 1) m ON true").
+		Joins("LEFT JOIN LATERAL (SELECT MAX(expires_at) AS expires_at FROM messages WHERE chat_id = c.id AND expires_at <= now()) e ON true").
+		Where("c.id = ?", id).
+		Take(&v).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// ListChats returns up to limit chats from newest to oldest, starting after the chat
+// with id after when it is set.
+func (s *Store) ListChats(ctx context.Context, limit int64, after string) ([]model.Chat, error) {
+	var c []model.Chat
+
+	q := s.db.WithContext(ctx).Table("chats").Order("id DESC").Limit(int(limit))
+	if after != "" {
+		q = q.Where("id < ?", after)
+	}
+
+	if err := q.Find(&c).Error; err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *Store) GetChatsByIDs(ctx context.Context, ids []string) ([]model.Chat, error) {
+	var c []model.Chat
+
+	if err := s.db.WithContext(ctx).Table("chats").Where("id IN ?", ids).Find(&c).Error; err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UpdateMessageTTL sets the message TTL of a chat, or clears it when ttl is nil,
+// and records a chat.updated event. It returns gorm.ErrRecordNotFound when there
+// is no such chat.
+func (s *Store) UpdateMessageTTL(ctx context.Context, id string, ttl *int64) (*model.Chat, error) {
+	return s.update(ctx, id, "message_ttl", ttl)
+}
+
+// UpdateRetention sets the retention of a chat in days, or clears it when days is
+// nil, and records a chat.updated event. It returns gorm.ErrRecordNotFound when
+// there is no such chat.
+func (s *Store) UpdateRetention(ctx context.Context, id string, days *int64) (*model.Chat, error) {
+	return s.update(ctx, id, "retention_days", days)
+}
+
+// UpdateLegalHold places a chat under legal hold or lifts it, and records a
+// chat.updated event. It returns gorm.ErrRecordNotFound when there is no such chat.
+func (s *Store) UpdateLegalHold(ctx context.Context, id string, hold bool) (*model.Chat, error) {
+	return s.update(ctx, id, "legal_hold", hold)
+}
+
+// update sets a single column of a chat, which must not come from user input.
+func (s *Store) update(ctx context.Context, id, column string, value any) (*model.Chat, error) {
+	var c model.Chat
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Raw("UPDATE chats SET "+column+" = ? WHERE id = ? RETURNING *", value, id).Scan(&c)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		e, err := events.New(events.ChatUpdated, id, &c)
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
 
+	return &c, nil
+}
+
+// DeleteChat deletes a chat along with its messages. The database refuses to
+// delete a chat under legal hold.
+func (s *Store) DeleteChat(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("chats").Delete(&model.Chat{}, "id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		e, err := events.New(events.ChatDeleted, id, map[string]string{"id": id})
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
 }
 
 func (s *Store) ChatExist(ctx context.Context, id string) (bool, error) {
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+)
+
+const (
+	// ErrUsage is returned by handlers when the command arguments are invalid.
+	ErrUsage = errors.Error("command_usage: invalid command arguments")
+	// ErrCommandFailed is returned when a command could not be executed.
+	ErrCommandFailed = errors.Error("command_failed: command failed")
+)
+
+var commandPattern = regexp.MustCompile(`(?s)^/([A-Za-z0-9_-]+)(?:\s+(.*))?$`)
+
+// Invocation describes a command typed into a chat.
+type Invocation struct {
+	Name   string `json:"command"`
+	Args   string `json:"args"`
+	Text   string `json:"text"`
+	ChatID string `json:"chat_id"`
+	Author string `json:"author,omitempty"`
+}
+
+// Reply is a system message posted into the chat in response to a command.
+type Reply struct {
+	Text string `json:"text"`
+}
+
+// Handler executes a command.
+type Handler interface {
+	Handle(ctx context.Context, inv Invocation) ([]Reply, error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc func(ctx context.Context, inv Invocation) ([]Reply, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, inv Invocation) ([]Reply, error) {
+	return f(ctx, inv)
+}
+
+// Parse reports whether text is a command such as "/poll Lunch? | Pizza | Sushi"
+// and splits it into its name and arguments.
+func Parse(text string) (Invocation, bool) {
+	text = strings.TrimSpace(text)
+
+	match := commandPattern.FindStringSubmatch(text)
+	if match == nil {
+		return Invocation{}, false
+	}
+
+	return Invocation{
+		Name: strings.ToLower(match[1]),
+		Args: strings.TrimSpace(match[2]),
+		Text: text,
+	}, true
+}
+
+// Registry routes commands to their handlers. Only registered commands run, so a
+// message such as "/shrug" or "/usr/bin" that names none is posted as it is.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	help     map[string]string
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		handlers: make(map[string]Handler),
+		help:     make(map[string]string),
+	}
+
+	r.Register("help", "list the available commands", HandlerFunc(r.helpCommand))
+	r.Register("poll", "start a poll: /poll Question | Option 1 | Option 2", HandlerFunc(pollCommand))
+
+	return r
+}
+
+// Register adds or replaces the handler of a command.
+func (r *Registry) Register(name, description string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name = strings.ToLower(name)
+	r.handlers[name] = h
+	r.help[name] = description
+}
+
+// Lookup returns the handler for a command.
+func (r *Registry) Lookup(name string) (Handler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.handlers[strings.ToLower(name)]
+	return h, ok
+}
+
+func (r *Registry) helpCommand(ctx context.Context, inv Invocation) ([]Reply, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.help))
+	for name := range r.help {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Available commands:")
+	for _, name := range names {
+		b.WriteString("\n/" + name + " - " + r.help[name])
+	}
+
+	return []Reply{{Text: b.String()}}, nil
+}
+
+func pollCommand(ctx context.Context, inv Invocation) ([]Reply, error) {
+	parts := strings.Split(inv.Args, "|")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	if len(parts) < 3 || parts[0] == "" {
+		return nil, ErrUsage.Wrap(errors.New("usage: /poll Question | Option 1 | Option 2"))
+	}
+
+	var b strings.Builder
+	b.WriteString("Poll: " + parts[0])
+	for i, option := range parts[1:] {
+		if option == "" {
+			return nil, ErrUsage.Wrap(errors.New("poll options must not be empty"))
+		}
+		b.WriteString("\n" + strconv.Itoa(i+1) + ". " + option)
+	}
+
+	return []Reply{{Text: b.String()}}, nil
+}
@@ -0,0 +1,163 @@
+package commands_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantOK  bool
+		wantInv commands.Invocation
+	}{
+		{
+			name:    "command with args",
+			text:    "  /Poll Lunch? | Pizza | Sushi ",
+			wantOK:  true,
+			wantInv: commands.Invocation{Name: "poll", Args: "Lunch? | Pizza | Sushi", Text: "/Poll Lunch? | Pizza | Sushi"},
+		},
+		{
+			name:    "command without args",
+			text:    "/help",
+			wantOK:  true,
+			wantInv: commands.Invocation{Name: "help", Text: "/help"},
+		},
+		{
+			name: "plain text",
+			text: "hello /help",
+		},
+		{
+			name: "path",
+			text: "/usr/local/bin",
+		},
+		{
+			name: "bare slash",
+			text: "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, ok := commands.Parse(tt.text)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantInv, inv)
+		})
+	}
+}
+
+func TestRegistry_Poll(t *testing.T) {
+	r := commands.NewRegistry()
+
+	h, ok := r.Lookup("poll")
+	require.True(t, ok)
+
+	replies, err := h.Handle(context.Background(), commands.Invocation{Name: "poll", Args: "Lunch? | Pizza | Sushi"})
+	require.NoError(t, err)
+	assert.Equal(t, []commands.Reply{{Text: "Poll: Lunch?\n1. Pizza\n2. Sushi"}}, replies)
+
+	_, err = h.Handle(context.Background(), commands.Invocation{Name: "poll", Args: "Lunch?"})
+	assert.ErrorIs(t, err, commands.ErrUsage)
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := commands.NewRegistry()
+
+	_, ok := r.Lookup("deploy")
+	assert.False(t, ok)
+
+	deploy := commands.HandlerFunc(func(ctx context.Context, inv commands.Invocation) ([]commands.Reply, error) {
+		return []commands.Reply{{Text: "deploying " + inv.Args}}, nil
+	})
+	r.Register("deploy", "deploy a service", deploy)
+
+	h, ok := r.Lookup("DEPLOY")
+	require.True(t, ok)
+	replies, err := h.Handle(context.Background(), commands.Invocation{Args: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "deploying api", replies[0].Text)
+
+	help, ok := r.Lookup("help")
+	require.True(t, ok)
+	replies, err = help.Handle(context.Background(), commands.Invocation{})
+	require.NoError(t, err)
+	assert.Contains(t, replies[0].Text, "/deploy - deploy a service")
+
+	// Only registered commands run, other slashes are plain text.
+	_, ok = r.Lookup("shrug")
+	assert.False(t, ok)
+}
+
+func TestRemote_Handle_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var inv commands.Invocation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&inv))
+		assert.Equal(t, "weather", inv.Name)
+		assert.Equal(t, "1", inv.ChatID)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"replies": []map[string]string{{"text": "sunny in " + inv.Args}},
+		})
+	}))
+	defer srv.Close()
+
+	r := commands.NewRemote(srv.URL, srv.Client(), time.Second)
+
+	replies, err := r.Handle(context.Background(), commands.Invocation{Name: "weather", Args: "Oslo", ChatID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []commands.Reply{{Text: "sunny in Oslo"}}, replies)
+}
+
+func TestRemote_Handle_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr error
+	}{
+		{
+			name: "usage error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "missing city", http.StatusBadRequest)
+			},
+			wantErr: commands.ErrUsage,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantErr: commands.ErrCommandFailed,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(200 * time.Millisecond):
+				}
+			},
+			wantErr: commands.ErrCommandFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			r := commands.NewRemote(srv.URL, srv.Client(), 50*time.Millisecond)
+
+			replies, err := r.Handle(context.Background(), commands.Invocation{Name: "weather"})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, replies)
+		})
+	}
+}
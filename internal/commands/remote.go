@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const maxRemoteResponse = 1 << 20
+
+type remoteResponse struct {
+	Replies []Reply `json:"replies"`
+}
+
+// Remote dispatches commands to an HTTP endpoint out of process. It is registered
+// under the names of the commands the endpoint handles. The endpoint receives the
+// Invocation as JSON and answers with {"replies": [{"text": "..."}]}.
+type Remote struct {
+	endpoint string
+	client   *http.Client
+	timeout  time.Duration
+}
+
+func NewRemote(endpoint string, client *http.Client, timeout time.Duration) *Remote {
+	return &Remote{
+		endpoint: endpoint,
+		client:   client,
+		timeout:  timeout,
+	}
+}
+
+func (r *Remote) Handle(ctx context.Context, inv Invocation) ([]Reply, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrCommandFailed.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, ErrCommandFailed.Wrap(err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnprocessableEntity:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, ErrUsage.Wrap(fmt.Errorf("%s", bytes.TrimSpace(msg)))
+	case res.StatusCode < 200 || res.StatusCode >= 300:
+		return nil, ErrCommandFailed.Wrap(fmt.Errorf("unexpected response status %d", res.StatusCode))
+	}
+
+	var out remoteResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxRemoteResponse)).Decode(&out); err != nil {
+		return nil, ErrCommandFailed.Wrap(err)
+	}
+
+	return out.Replies, nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 
@@ -17,7 +18,50 @@ const (
 
 type Config struct {
 	HTTP_PORT string `env:"HTTP_PORT"`
+	GRPC_PORT string `env:"GRPC_PORT" envDefault:"9090"`
 	PSQL      string `env:"POSTGRES_DSN"`
+
+	WEBHOOK_TIMEOUT         time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WEBHOOK_MAX_RETRIES     uint64        `env:"WEBHOOK_MAX_RETRIES" envDefault:"5"`
+	WEBHOOK_RESCAN_INTERVAL time.Duration `env:"WEBHOOK_RESCAN_INTERVAL" envDefault:"1m"`
+
+	OUTBOX_POLL_INTERVAL time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	OUTBOX_BATCH_SIZE    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100" validate:"min=1"`
+	OUTBOX_RETENTION     time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"`
+
+	COMMANDS_ENDPOINT string        `env:"COMMANDS_ENDPOINT" validate:"omitempty,url"`
+	COMMANDS_TIMEOUT  time.Duration `env:"COMMANDS_TIMEOUT" envDefault:"5s"`
+	COMMANDS_NAMES    []string      `env:"COMMANDS_NAMES" envSeparator:"," validate:"required_with=COMMANDS_ENDPOINT"`
+
+	IDEMPOTENCY_TTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+
+	SCHEDULER_POLL_INTERVAL time.Duration `env:"SCHEDULER_POLL_INTERVAL" envDefault:"1s"`
+
+	MESSAGE_SWEEP_INTERVAL time.Duration `env:"MESSAGE_SWEEP_INTERVAL" envDefault:"1m"`
+
+	RETENTION_DAYS     int64         `env:"RETENTION_DAYS" envDefault:"0" validate:"min=0,max=36500"`
+	RETENTION_INTERVAL time.Duration `env:"RETENTION_INTERVAL" envDefault:"1h"`
+
+	IMPORT_POLL_INTERVAL time.Duration `env:"IMPORT_POLL_INTERVAL" envDefault:"5s"`
+	IMPORT_MAX_SIZE      int64         `env:"IMPORT_MAX_SIZE" envDefault:"104857600" validate:"min=1"`
+
+	BLOB_BACKEND  string `env:"BLOB_BACKEND" envDefault:"local" validate:"oneof=local s3"`
+	BLOB_DIR      string `env:"BLOB_DIR" envDefault:"data/blobs"`
+	S3_ENDPOINT   string `env:"S3_ENDPOINT" validate:"required_if=BLOB_BACKEND s3,omitempty,url"`
+	S3_REGION     string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3_BUCKET     string `env:"S3_BUCKET" validate:"required_if=BLOB_BACKEND s3"`
+	S3_ACCESS_KEY string `env:"S3_ACCESS_KEY"`
+	S3_SECRET_KEY string `env:"S3_SECRET_KEY"`
+
+	ATTACHMENT_MAX_SIZE   int64         `env:"ATTACHMENT_MAX_SIZE" envDefault:"26214400" validate:"min=1"`
+	ATTACHMENT_URL_TTL    time.Duration `env:"ATTACHMENT_URL_TTL" envDefault:"15m"`
+	ATTACHMENT_URL_SECRET string        `env:"ATTACHMENT_URL_SECRET"`
+
+	ATTACHMENT_PROCESS_INTERVAL time.Duration `env:"ATTACHMENT_PROCESS_INTERVAL" envDefault:"5s"`
+
+	UNFURL_TIMEOUT       time.Duration `env:"UNFURL_TIMEOUT" envDefault:"5s"`
+	UNFURL_CACHE_TTL     time.Duration `env:"UNFURL_CACHE_TTL" envDefault:"24h"`
+	UNFURL_POLL_INTERVAL time.Duration `env:"UNFURL_POLL_INTERVAL" envDefault:"2s"`
 }
 
 func Load(ctx context.Context) (*Config, error) {
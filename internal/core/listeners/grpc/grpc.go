@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ErrListen is the error returned when the server fails to bind its port.
+	ErrListen = errors.Error("failed to listen")
+	// ErrServer is the error returned when the server stops due to an error.
+	ErrServer = errors.Error("serve stopped with error")
+)
+
+// Service represents a grpc service that registers its handlers with the listener.
+type Service interface {
+	Register(s *grpc.Server)
+}
+
+// Server represents a grpc server that listens on a port.
+type Server struct {
+	server *grpc.Server
+	port   string
+	logger *zap.Logger
+}
+
+// New instantiates a new instance of Server.
+func New(s Service, grpcPort string) *Server {
+	srv := &Server{
+		port:   grpcPort,
+		logger: logging.From(context.Background()),
+	}
+
+	srv.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(srv.loggerUnaryInterceptor, tracingUnaryInterceptor, logTracingUnaryInterceptor, requestLoggingUnaryInterceptor, recoveryUnaryInterceptor),
+		grpc.ChainStreamInterceptor(srv.loggerStreamInterceptor, tracingStreamInterceptor, logTracingStreamInterceptor, requestLoggingStreamInterceptor, recoveryStreamInterceptor),
+	)
+
+	s.Register(srv.server)
+
+	return srv
+}
+
+// Listen starts the server and listens on the configured port. Requests are
+// logged with the logger of ctx.
+func (s *Server) Listen(ctx context.Context) error {
+	s.logger = logging.From(ctx)
+
+	logging.From(ctx).Info(fmt.Sprintf("grpc server starting on port: %s", s.port))
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", s.port))
+	if err != nil {
+		return ErrListen.Wrap(err)
+	}
+
+	if err := s.server.Serve(lis); err != nil {
+		return ErrServer.Wrap(err)
+	}
+
+	logging.From(ctx).Info("grpc server stopped")
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	grpclistener "github.com/Polilo-User/test-task-hitalent/internal/core/listeners/grpc"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+
+	traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID  = "00f067aa0ba902b7"
+)
+
+// healthService answers health checks, failing or panicking for the services
+// named "fail" and "panic".
+type healthService struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (healthService) Register(s *grpc.Server) {
+	grpc_health_v1.RegisterHealthServer(s, healthService{})
+}
+
+func (healthService) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	logging.From(ctx).Info("checking")
+
+	switch req.GetService() {
+	case "fail":
+		return nil, status.Error(codes.NotFound, "unknown service")
+	case "panic":
+		panic("check exploded")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (healthService) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	logging.From(stream.Context()).Info("watching")
+
+	if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+
+	switch req.GetService() {
+	case "fail":
+		return status.Error(codes.NotFound, "unknown service")
+	case "panic":
+		panic("watch exploded")
+	}
+
+	return nil
+}
+
+// serve starts a listener and returns a client of it along with the logs and
+// spans it records.
+func serve(t *testing.T) (grpc_health_v1.HealthClient, *observer.ObservedLogs, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	spans := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, lis.Close())
+
+	s := grpclistener.New(healthService{}, port)
+	go func() {
+		_ = s.Listen(logging.With(context.Background(), zap.New(core)))
+	}()
+
+	conn, err := grpc.NewClient("localhost:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn), logs, spans
+}
+
+func withTraceParent(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "traceparent", "00-"+traceID+"-"+spanID+"-01")
+}
+
+func TestServer_Recovery(t *testing.T) {
+	client, logs, _ := serve(t)
+
+	ctx := context.Background()
+
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "panic"}, grpc.WaitForReady(true))
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, 1, logs.FilterMessage("panic in handler").Len())
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "panic"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// The server keeps serving after a panic.
+	res, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+}
+
+func TestServer_Logging(t *testing.T) {
+	client, logs, _ := serve(t)
+
+	ctx := withTraceParent(context.Background())
+
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "fail"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	// Handlers log with the method and trace of the request.
+	for _, msg := range []string{"checking", "watching"} {
+		for _, e := range logs.FilterMessage(msg).All() {
+			fields := e.ContextMap()
+			assert.Contains(t, []string{checkMethod, watchMethod}, fields["method"])
+			assert.Equal(t, traceID, fields["trace_id"])
+		}
+	}
+
+	assert.Equal(t, 2, logs.FilterMessage("request").FilterField(zap.String("method", checkMethod)).Len())
+	assert.Equal(t, 1, logs.FilterMessage("request failed").FilterField(zap.String("code", codes.NotFound.String())).Len())
+
+	require.Eventually(t, func() bool { return logs.FilterMessage("stream closed").Len() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, logs.FilterMessage("stream opened").FilterField(zap.String("method", watchMethod)).Len())
+}
+
+func TestServer_Tracing(t *testing.T) {
+	client, _, spans := serve(t)
+
+	ctx := withTraceParent(context.Background())
+
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "fail"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	got := spans.GetSpans()
+	require.Len(t, got, 2)
+
+	for _, s := range got {
+		assert.Equal(t, checkMethod, s.Name)
+		assert.Equal(t, trace.SpanKindServer, s.SpanKind)
+		// The span continues the trace of the caller.
+		assert.Equal(t, traceID, s.SpanContext.TraceID().String())
+		assert.Equal(t, spanID, s.Parent.SpanID().String())
+	}
+
+	assert.Equal(t, otelcodes.Unset, got[0].Status.Code)
+	assert.Equal(t, otelcodes.Error, got[1].Status.Code)
+	assert.Equal(t, codes.NotFound.String(), got[1].Status.Description)
+}
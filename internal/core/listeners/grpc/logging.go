@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) loggerUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(logging.With(ctx, s.logger), req)
+}
+
+func (s *Server) loggerStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: logging.With(ss.Context(), s.logger)})
+}
+
+func requestLoggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = logging.WithFields(ctx, zap.String("method", info.FullMethod))
+
+	logging.From(ctx).Info("request")
+
+	res, err := handler(ctx, req)
+	if err != nil {
+		logging.From(ctx).Error("request failed", zap.String("code", status.Code(err).String()), zap.Error(err))
+	}
+
+	return res, err
+}
+
+func requestLoggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := logging.WithFields(ss.Context(), zap.String("method", info.FullMethod))
+
+	logging.From(ctx).Info("stream opened")
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	if err != nil {
+		logging.From(ctx).Error("stream failed", zap.String("code", status.Code(err).String()), zap.Error(err))
+		return err
+	}
+
+	logging.From(ctx).Info("stream closed")
+
+	return nil
+}
+
+// serverStream overrides the context of a grpc.ServerStream so that values added
+// by interceptors reach the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
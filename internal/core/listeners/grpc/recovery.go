@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoveryUnaryInterceptor fails a request whose handler panics with Internal,
+// rather than letting the panic take the whole server down.
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ss.Context(), r)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func recovered(ctx context.Context, r interface{}) error {
+	logging.From(ctx).Error("panic in handler", zap.Any("panic", r), zap.Stack("stack"))
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+const subscriberBuffer = 64
+
+// Broker fans events out to in-process subscribers of a chat. It is fed by a
+// follower of the change log, which every replica runs, so subscribers see the
+// events of their chat whichever replica wrote them. Publish never blocks: a
+// subscriber that is not keeping up has its channel closed and is dropped rather
+// than holding back the follower, so that it knows it missed events instead of
+// silently skipping them.
+type Broker struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan Event]struct{}
+}
+
+// NewBroker instantiates a new instance of Broker.
+func NewBroker() *Broker {
+	return &Broker{
+		subs: map[string]map[chan Event]struct{}{},
+	}
+}
+
+// Publish hands e to every current subscriber of its chat. Subscribers whose
+// buffer is full are closed and released.
+func (b *Broker) Publish(ctx context.Context, e Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[e.ChatID] {
+		select {
+		case ch <- e:
+		default:
+			close(ch)
+			b.remove(e.ChatID, ch)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber for events of a chat. The returned function
+// must be called to release the subscription. The channel is closed when the
+// subscriber falls too far behind.
+func (b *Broker) Subscribe(chatID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[chatID] == nil {
+		b.subs[chatID] = map[chan Event]struct{}{}
+	}
+	b.subs[chatID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			b.remove(chatID, ch)
+		})
+	}
+}
+
+// Reset closes and releases every subscriber, telling them they missed events.
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for chatID, subs := range b.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(b.subs, chatID)
+	}
+}
+
+func (b *Broker) remove(chatID string, ch chan Event) {
+	delete(b.subs[chatID], ch)
+	if len(b.subs[chatID]) == 0 {
+		delete(b.subs, chatID)
+	}
+}
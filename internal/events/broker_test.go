@@ -0,0 +1,86 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	b := events.NewBroker()
+
+	sub, cancel := b.Subscribe("1")
+	other, cancelOther := b.Subscribe("2")
+	defer cancelOther()
+
+	require.NoError(t, b.Publish(context.Background(), events.Event{ID: "1", ChatID: "1"}))
+
+	select {
+	case e := <-sub:
+		assert.Equal(t, "1", e.ID)
+	default:
+		t.Fatal("expected event for chat 1")
+	}
+	assert.Empty(t, other)
+
+	cancel()
+	cancel()
+
+	require.NoError(t, b.Publish(context.Background(), events.Event{ID: "2", ChatID: "1"}))
+	assert.Empty(t, sub)
+}
+
+func TestBroker_Publish_SlowSubscriber(t *testing.T) {
+	b := events.NewBroker()
+
+	sub, cancel := b.Subscribe("1")
+	defer cancel()
+
+	fast, cancelFast := b.Subscribe("1")
+	defer cancelFast()
+
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, b.Publish(context.Background(), events.Event{ChatID: "1"}))
+		<-fast
+	}
+
+	// The slow subscriber gets what fit in its buffer, then its channel is closed.
+	var n int
+	for range sub {
+		n++
+	}
+	assert.Equal(t, 64, n)
+
+	// Other subscribers keep receiving.
+	require.NoError(t, b.Publish(context.Background(), events.Event{ID: "1", ChatID: "1"}))
+	assert.Equal(t, "1", (<-fast).ID)
+}
+
+func TestBroker_Reset(t *testing.T) {
+	b := events.NewBroker()
+
+	sub, cancel := b.Subscribe("1")
+	other, cancelOther := b.Subscribe("2")
+
+	b.Reset()
+
+	// Every subscriber is told it missed events.
+	_, ok := <-sub
+	assert.False(t, ok)
+	_, ok = <-other
+	assert.False(t, ok)
+
+	cancel()
+	cancelOther()
+
+	// New subscribers are served as usual.
+	sub, cancel = b.Subscribe("1")
+	defer cancel()
+
+	require.NoError(t, b.Publish(context.Background(), events.Event{ID: "1", ChatID: "1"}))
+	assert.Equal(t, "1", (<-sub).ID)
+}
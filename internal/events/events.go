@@ -0,0 +1,66 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type identifies the kind of domain event.
+type Type string
+
+const (
+	// ChatCreated is emitted after a chat has been created.
+	ChatCreated Type = "chat.created"
+	// ChatUpdated is emitted after the settings of a chat have been changed.
+	ChatUpdated Type = "chat.updated"
+	// ChatDeleted is emitted after a chat has been deleted.
+	ChatDeleted Type = "chat.deleted"
+	// MessageCreated is emitted after a message has been posted to a chat.
+	MessageCreated Type = "message.created"
+	// MessageUpdated is emitted after the text of a message has been edited.
+	MessageUpdated Type = "message.updated"
+	// MessageDeleted is emitted after a message has been deleted.
+	MessageDeleted Type = "message.deleted"
+	// MessagesImported is emitted once per batch of messages ingested in bulk, in
+	// place of a message.created event for every message. It carries the messages.
+	MessagesImported Type = "messages.imported"
+)
+
+// Types lists every event type that can be subscribed to.
+var Types = []Type{ChatCreated, ChatUpdated, ChatDeleted, MessageCreated, MessageUpdated, MessageDeleted, MessagesImported}
+
+// Valid reports whether t is a known event type.
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain event describing a change to a chat or one of its messages.
+// ID is assigned once the event has been recorded and can be used by consumers
+// to discard duplicates, since events are delivered at least once.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       Type            `json:"type"`
+	ChatID     string          `json:"chat_id"`
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// New builds an event of the given type for a chat, serializing data as the payload.
+func New(t Type, chatID string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:       t,
+		ChatID:     chatID,
+		Data:       raw,
+		OccurredAt: time.Now().UTC(),
+	}, nil
+}
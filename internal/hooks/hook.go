@@ -0,0 +1,201 @@
+package hooks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	messageModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrHookNotFound = errors.Error("hook_not_found: incoming webhook not found")
+	ErrHookRevoked  = errors.Error("hook_revoked: incoming webhook has been revoked")
+	ErrInvalidHook  = errors.Error("invalid_hook: invalid incoming webhook")
+	ErrRateLimited  = errors.Error("rate_limited: too many requests for this webhook")
+)
+
+type Store interface {
+	InsertHook(ctx context.Context, h *model.Hook) (*model.Hook, error)
+	GetHook(ctx context.Context, id string) (*model.Hook, error)
+	ListHooks(ctx context.Context, chatID string) ([]model.Hook, error)
+	RevokeHook(ctx context.Context, id string) error
+}
+
+type ChatService interface {
+	ChatExist(ctx context.Context, id string) error
+}
+
+type MessageService interface {
+	CreateMessage(ctx context.Context, m *messageModel.Message) (*messageModel.Posted, error)
+}
+
+// HookService manages incoming webhooks and posts the messages they receive.
+type HookService struct {
+	store    Store
+	chats    ChatService
+	messages MessageService
+	validate *validator.Validate
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func New(s Store, c ChatService, m MessageService) *HookService {
+	return &HookService{
+		store:    s,
+		chats:    c,
+		messages: m,
+		validate: validator.New(),
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// CreateHook registers an incoming webhook for a chat. The returned hook carries the
+// secret token and the URL to post to; the token is not retrievable afterwards.
+func (h *HookService) CreateHook(ctx context.Context, chatID string, hook *model.Hook) (*model.Hook, error) {
+	if err := h.validate.Struct(hook); err != nil {
+		return nil, ErrInvalidHook.Wrap(err)
+	}
+
+	if err := h.chats.ChatExist(ctx, chatID); err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hook.ChatID = &chatID
+	hook.TokenHash = pointer.ToString(hashToken(token))
+	if hook.RateLimit == nil {
+		hook.RateLimit = pointer.ToInt(model.DefaultRateLimit)
+	}
+
+	created, err := h.store.InsertHook(ctx, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	created.Token = &token
+	created.URL = pointer.ToString(fmt.Sprintf("/v1/hooks/%s/%s", *created.ID, token))
+
+	return created, nil
+}
+
+func (h *HookService) ListHooks(ctx context.Context, chatID string) ([]model.Hook, error) {
+	if err := h.chats.ChatExist(ctx, chatID); err != nil {
+		return nil, err
+	}
+
+	return h.store.ListHooks(ctx, chatID)
+}
+
+// RevokeHook disables a hook permanently. Revoking an already revoked hook is a no-op.
+func (h *HookService) RevokeHook(ctx context.Context, chatID, id string) error {
+	hook, err := h.getHook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if *hook.ChatID != chatID {
+		return ErrHookNotFound
+	}
+	if hook.RevokedAt != nil {
+		return nil
+	}
+
+	if err := h.store.RevokeHook(ctx, id); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	delete(h.limiters, id)
+	h.mu.Unlock()
+
+	return nil
+}
+
+// Post creates a message in the hook's chat authored by the hook's bot identity.
+func (h *HookService) Post(ctx context.Context, id, token string, p *model.Payload) (*messageModel.Posted, error) {
+	hook, err := h.getHook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	want, _ := hex.DecodeString(pointer.GetString(hook.TokenHash))
+	got := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(want, got[:]) != 1 {
+		return nil, ErrHookNotFound
+	}
+
+	if hook.RevokedAt != nil {
+		return nil, ErrHookRevoked
+	}
+
+	if err := h.validate.Struct(p); err != nil {
+		return nil, ErrInvalidHook.Wrap(err)
+	}
+
+	if !h.limiter(hook).Allow() {
+		return nil, ErrRateLimited
+	}
+
+	return h.messages.CreateMessage(ctx, &messageModel.Message{
+		ChatID: hook.ChatID,
+		Text:   p.Text,
+		Author: hook.Name,
+	})
+}
+
+// limiter returns the token bucket of a hook. Limits are kept in memory, so every
+// replica enforces the hook's rate limit on its own.
+func (h *HookService) limiter(hook *model.Hook) *rate.Limiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	l, ok := h.limiters[*hook.ID]
+	if !ok {
+		perMinute := pointer.GetInt(hook.RateLimit)
+		if perMinute <= 0 {
+			perMinute = model.DefaultRateLimit
+		}
+		l = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+		h.limiters[*hook.ID] = l
+	}
+
+	return l
+}
+
+func (h *HookService) getHook(ctx context.Context, id string) (*model.Hook, error) {
+	hook, err := h.store.GetHook(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrHookNotFound
+	}
+	return hook, err
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
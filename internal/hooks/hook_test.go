@@ -0,0 +1,261 @@
+package hooks_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	messageModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func hashOf(token string) *string {
+	sum := sha256.Sum256([]byte(token))
+	return pointer.ToString(hex.EncodeToString(sum[:]))
+}
+
+func TestHooks_CreateHook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	m := mocks.NewMockMessageService(ctrl)
+
+	h := hooks.New(s, c, m)
+	require.NotNil(t, h)
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+
+	var storedHash string
+	s.EXPECT().
+		InsertHook(gomock.Any(), gomock.AssignableToTypeOf(&model.Hook{})).
+		DoAndReturn(func(ctx context.Context, hook *model.Hook) (*model.Hook, error) {
+			assert.Equal(t, "1", *hook.ChatID)
+			assert.Equal(t, model.DefaultRateLimit, *hook.RateLimit)
+			storedHash = *hook.TokenHash
+			hook.ID = pointer.ToString("5")
+			return hook, nil
+		}).Times(1)
+
+	created, err := h.CreateHook(context.Background(), "1", &model.Hook{Name: pointer.ToString("ci")})
+	require.NoError(t, err)
+	require.NotNil(t, created.Token)
+	assert.Equal(t, storedHash, *hashOf(*created.Token))
+	assert.Equal(t, "/v1/hooks/5/"+*created.Token, *created.URL)
+}
+
+func TestHooks_CreateHook_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    *model.Hook
+		chatErr error
+		wantErr error
+	}{
+		{
+			name:    "missing name",
+			hook:    &model.Hook{},
+			wantErr: hooks.ErrInvalidHook,
+		},
+		{
+			name:    "rate limit out of range",
+			hook:    &model.Hook{Name: pointer.ToString("ci"), RateLimit: pointer.ToInt(0)},
+			wantErr: hooks.ErrInvalidHook,
+		},
+		{
+			name:    "chat not found",
+			hook:    &model.Hook{Name: pointer.ToString("ci")},
+			chatErr: chats.ErrChatNotFound,
+			wantErr: chats.ErrChatNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			m := mocks.NewMockMessageService(ctrl)
+
+			h := hooks.New(s, c, m)
+
+			if tt.chatErr != nil {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(tt.chatErr).Times(1)
+			}
+
+			created, err := h.CreateHook(context.Background(), "1", tt.hook)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, created)
+		})
+	}
+}
+
+func TestHooks_Post_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	m := mocks.NewMockMessageService(ctrl)
+
+	h := hooks.New(s, c, m)
+
+	s.EXPECT().GetHook(gomock.Any(), "5").Return(&model.Hook{
+		ID:        pointer.ToString("5"),
+		ChatID:    pointer.ToString("1"),
+		Name:      pointer.ToString("ci"),
+		RateLimit: pointer.ToInt(60),
+		TokenHash: hashOf("token"),
+	}, nil).Times(1)
+
+	want := &messageModel.Message{
+		ID:     pointer.ToString("9"),
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("build passed"),
+		Author: pointer.ToString("ci"),
+	}
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messageModel.Message{
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("build passed"),
+			Author: pointer.ToString("ci"),
+		}).
+		Return(&messageModel.Posted{Message: want}, nil).Times(1)
+
+	posted, err := h.Post(context.Background(), "5", "token", &model.Payload{Text: pointer.ToString("build passed")})
+	require.NoError(t, err)
+	assert.Equal(t, want, posted.Message)
+}
+
+func TestHooks_Post_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    *model.Hook
+		getErr  error
+		token   string
+		text    *string
+		wantErr error
+	}{
+		{
+			name:    "unknown hook",
+			getErr:  gorm.ErrRecordNotFound,
+			token:   "token",
+			text:    pointer.ToString("hi"),
+			wantErr: hooks.ErrHookNotFound,
+		},
+		{
+			name: "wrong token",
+			hook: &model.Hook{
+				ID:        pointer.ToString("5"),
+				ChatID:    pointer.ToString("1"),
+				TokenHash: hashOf("token"),
+			},
+			token:   "other",
+			text:    pointer.ToString("hi"),
+			wantErr: hooks.ErrHookNotFound,
+		},
+		{
+			name: "revoked",
+			hook: &model.Hook{
+				ID:        pointer.ToString("5"),
+				ChatID:    pointer.ToString("1"),
+				TokenHash: hashOf("token"),
+				RevokedAt: pointer.ToTime(time.Now()),
+			},
+			token:   "token",
+			text:    pointer.ToString("hi"),
+			wantErr: hooks.ErrHookRevoked,
+		},
+		{
+			name: "empty text",
+			hook: &model.Hook{
+				ID:        pointer.ToString("5"),
+				ChatID:    pointer.ToString("1"),
+				TokenHash: hashOf("token"),
+			},
+			token:   "token",
+			wantErr: hooks.ErrInvalidHook,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			m := mocks.NewMockMessageService(ctrl)
+
+			h := hooks.New(s, c, m)
+
+			s.EXPECT().GetHook(gomock.Any(), "5").Return(tt.hook, tt.getErr).Times(1)
+
+			msg, err := h.Post(context.Background(), "5", tt.token, &model.Payload{Text: tt.text})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, msg)
+		})
+	}
+}
+
+func TestHooks_Post_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	m := mocks.NewMockMessageService(ctrl)
+
+	h := hooks.New(s, c, m)
+
+	s.EXPECT().GetHook(gomock.Any(), "5").Return(&model.Hook{
+		ID:        pointer.ToString("5"),
+		ChatID:    pointer.ToString("1"),
+		Name:      pointer.ToString("ci"),
+		RateLimit: pointer.ToInt(2),
+		TokenHash: hashOf("token"),
+	}, nil).Times(3)
+
+	m.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(&messageModel.Posted{Message: &messageModel.Message{}}, nil).Times(2)
+
+	p := &model.Payload{Text: pointer.ToString("hi")}
+
+	for i := 0; i < 2; i++ {
+		_, err := h.Post(context.Background(), "5", "token", p)
+		require.NoError(t, err)
+	}
+
+	_, err := h.Post(context.Background(), "5", "token", p)
+	assert.ErrorIs(t, err, hooks.ErrRateLimited)
+}
+
+func TestHooks_RevokeHook_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	m := mocks.NewMockMessageService(ctrl)
+
+	h := hooks.New(s, c, m)
+
+	s.EXPECT().GetHook(gomock.Any(), "5").Return(&model.Hook{
+		ID:     pointer.ToString("5"),
+		ChatID: pointer.ToString("2"),
+	}, nil).Times(1)
+
+	err := h.RevokeHook(context.Background(), "1", "5")
+	assert.ErrorIs(t, err, hooks.ErrHookNotFound)
+}
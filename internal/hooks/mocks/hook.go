@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/hooks (interfaces: ChatService,MessageService,Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	model0 "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChatService is a mock of ChatService interface.
+type MockChatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatServiceMockRecorder
+}
+
+// MockChatServiceMockRecorder is the mock recorder for MockChatService.
+type MockChatServiceMockRecorder struct {
+	mock *MockChatService
+}
+
+// NewMockChatService creates a new mock instance.
+func NewMockChatService(ctrl *gomock.Controller) *MockChatService {
+	mock := &MockChatService{ctrl: ctrl}
+	mock.recorder = &MockChatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatService) EXPECT() *MockChatServiceMockRecorder {
+	return m.recorder
+}
+
+// ChatExist mocks base method.
+func (m *MockChatService) ChatExist(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChatExist", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChatExist indicates an expected call of ChatExist.
+func (mr *MockChatServiceMockRecorder) ChatExist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatExist", reflect.TypeOf((*MockChatService)(nil).ChatExist), arg0, arg1)
+}
+
+// MockMessageService is a mock of MessageService interface.
+type MockMessageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageServiceMockRecorder
+}
+
+// MockMessageServiceMockRecorder is the mock recorder for MockMessageService.
+type MockMessageServiceMockRecorder struct {
+	mock *MockMessageService
+}
+
+// NewMockMessageService creates a new mock instance.
+func NewMockMessageService(ctrl *gomock.Controller) *MockMessageService {
+	mock := &MockMessageService{ctrl: ctrl}
+	mock.recorder = &MockMessageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageService) EXPECT() *MockMessageServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockMessageService) CreateMessage(arg0 context.Context, arg1 *model0.Message) (*model0.Posted, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", arg0, arg1)
+	ret0, _ := ret[0].(*model0.Posted)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockMessageServiceMockRecorder) CreateMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessageService)(nil).CreateMessage), arg0, arg1)
+}
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// GetHook mocks base method.
+func (m *MockStore) GetHook(arg0 context.Context, arg1 string) (*model.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHook", arg0, arg1)
+	ret0, _ := ret[0].(*model.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHook indicates an expected call of GetHook.
+func (mr *MockStoreMockRecorder) GetHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHook", reflect.TypeOf((*MockStore)(nil).GetHook), arg0, arg1)
+}
+
+// InsertHook mocks base method.
+func (m *MockStore) InsertHook(arg0 context.Context, arg1 *model.Hook) (*model.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertHook", arg0, arg1)
+	ret0, _ := ret[0].(*model.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertHook indicates an expected call of InsertHook.
+func (mr *MockStoreMockRecorder) InsertHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertHook", reflect.TypeOf((*MockStore)(nil).InsertHook), arg0, arg1)
+}
+
+// ListHooks mocks base method.
+func (m *MockStore) ListHooks(arg0 context.Context, arg1 string) ([]model.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", arg0, arg1)
+	ret0, _ := ret[0].([]model.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHooks indicates an expected call of ListHooks.
+func (mr *MockStoreMockRecorder) ListHooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockStore)(nil).ListHooks), arg0, arg1)
+}
+
+// RevokeHook mocks base method.
+func (m *MockStore) RevokeHook(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeHook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeHook indicates an expected call of RevokeHook.
+func (mr *MockStoreMockRecorder) RevokeHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeHook", reflect.TypeOf((*MockStore)(nil).RevokeHook), arg0, arg1)
+}
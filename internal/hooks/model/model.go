@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const DefaultRateLimit = 60
+
+// Hook is an incoming webhook that lets an external system post messages into a chat
+// under a bot identity.
+type Hook struct {
+	ID        *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	ChatID    *string    `json:"chat_id" db:"chat_id"`
+	Name      *string    `json:"name" db:"name" validate:"required,max=100"`
+	RateLimit *int       `json:"rate_limit" db:"rate_limit" validate:"omitempty,min=1,max=6000"`
+	TokenHash *string    `json:"-" db:"token_hash"`
+	Token     *string    `json:"token,omitempty" gorm:"-"`
+	URL       *string    `json:"url,omitempty" gorm:"-"`
+	CreatedAt *time.Time `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+func (Hook) TableName() string {
+	return "hooks"
+}
+
+// Payload is the body accepted by an incoming webhook.
+type Payload struct {
+	Text *string `json:"text" validate:"required,min=1"`
+}
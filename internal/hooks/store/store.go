@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+func (s *Store) InsertHook(ctx context.Context, h *model.Hook) (*model.Hook, error) {
+	if err := s.db.WithContext(ctx).Create(h).Error; err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *Store) GetHook(ctx context.Context, id string) (*model.Hook, error) {
+	var h model.Hook
+
+	if err := s.db.WithContext(ctx).Where("id = ?", id).Take(&h).Error; err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+func (s *Store) ListHooks(ctx context.Context, chatID string) ([]model.Hook, error) {
+	var h []model.Hook
+
+	if err := s.db.WithContext(ctx).Where("chat_id = ?", chatID).Order("id").Find(&h).Error; err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (s *Store) RevokeHook(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Model(&model.Hook{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", gorm.Expr("now()")).Error
+}
@@ -0,0 +1,143 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrInvalidKey    = errors.Error("invalid_idempotency_key: idempotency key must be 1 to 255 printable ascii characters")
+	ErrKeyReused     = errors.Error("idempotency_key_reused: idempotency key was already used for a different request")
+	ErrKeyInProgress = errors.Error("idempotency_key_in_progress: a request with this idempotency key is still being processed")
+)
+
+const (
+	maxKeyLength = 255
+	// lockTimeout is how long a request may hold a key without completing before
+	// it is assumed lost, e.g. with the instance processing it, and the key can be
+	// used again.
+	lockTimeout = 5 * time.Minute
+	// sweepInterval is how often expired keys are deleted.
+	sweepInterval = time.Hour
+)
+
+type Store interface {
+	AcquireKey(ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration) (*model.Key, bool, error)
+	CompleteKey(ctx context.Context, k *model.Key) error
+	DeleteKey(ctx context.Context, key string) error
+	DeleteExpiredKeys(ctx context.Context) (int64, error)
+}
+
+// IdempotencyService remembers the responses to requests sent with an idempotency
+// key for a while, so that retried requests are answered with the original
+// response instead of being processed again.
+type IdempotencyService struct {
+	store Store
+	ttl   time.Duration
+}
+
+func New(s Store, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		store: s,
+		ttl:   ttl,
+	}
+}
+
+// Begin claims a key for a request identified by its fingerprint. It returns nil
+// when the request should be processed, and the stored key when it was processed
+// before and its response should be replayed.
+func (i *IdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*model.Key, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	k, acquired, err := i.store.AcquireKey(ctx, key, fingerprint, i.ttl, lockTimeout)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The key was released between the attempt to acquire it and reading it.
+		return nil, ErrKeyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case acquired:
+		return nil, nil
+	case pointer.GetString(k.Fingerprint) != fingerprint:
+		return nil, ErrKeyReused
+	case k.StatusCode == nil:
+		return nil, ErrKeyInProgress
+	default:
+		return k, nil
+	}
+}
+
+// Complete stores the response of the request a key was claimed for.
+func (i *IdempotencyService) Complete(ctx context.Context, key string, status int, header map[string][]string, body []byte) error {
+	raw, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	return i.store.CompleteKey(ctx, &model.Key{
+		Key:        pointer.ToString(key),
+		StatusCode: pointer.ToInt(status),
+		Header:     raw,
+		Body:       body,
+	})
+}
+
+// Release forgets a key, so a retry is processed as a new request. It is used
+// when a request failed in a way worth retrying.
+func (i *IdempotencyService) Release(ctx context.Context, key string) error {
+	return i.store.DeleteKey(ctx, key)
+}
+
+// Listen deletes expired keys until the context is cancelled.
+func (i *IdempotencyService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("idempotency key sweeper starting")
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := i.Sweep(ctx); err != nil {
+			logging.From(ctx).Error("failed to delete expired idempotency keys", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("idempotency key sweeper stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep deletes the expired keys, returning how many were deleted.
+func (i *IdempotencyService) Sweep(ctx context.Context) (int64, error) {
+	return i.store.DeleteExpiredKeys(ctx)
+}
+
+func validKey(key string) bool {
+	if key == "" || len(key) > maxKeyLength {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,178 @@
+package idempotency_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyService_Begin_Success(t *testing.T) {
+	completed := &model.Key{
+		Key:         pointer.ToString("key"),
+		Fingerprint: pointer.ToString("fp"),
+		StatusCode:  pointer.ToInt(200),
+		Body:        []byte(`{}`),
+	}
+
+	tests := []struct {
+		name     string
+		key      *model.Key
+		acquired bool
+		want     *model.Key
+	}{
+		{
+			name:     "new key",
+			key:      &model.Key{Key: pointer.ToString("key"), Fingerprint: pointer.ToString("fp")},
+			acquired: true,
+		},
+		{
+			name: "retry",
+			key:  completed,
+			want: completed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().AcquireKey(gomock.Any(), "key", "fp", time.Hour, gomock.Any()).Return(tt.key, tt.acquired, nil).Times(1)
+
+			i := idempotency.New(s, time.Hour)
+			require.NotNil(t, i)
+
+			k, err := i.Begin(context.Background(), "key", "fp")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, k)
+		})
+	}
+}
+
+func TestIdempotencyService_Begin_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		setup   func(s *mocks.MockStore)
+		wantErr error
+	}{
+		{
+			name:    "empty key",
+			key:     "",
+			wantErr: idempotency.ErrInvalidKey,
+		},
+		{
+			name:    "key too long",
+			key:     strings.Repeat("k", 256),
+			wantErr: idempotency.ErrInvalidKey,
+		},
+		{
+			name:    "non printable key",
+			key:     "key\n",
+			wantErr: idempotency.ErrInvalidKey,
+		},
+		{
+			name: "different request",
+			key:  "key",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().AcquireKey(gomock.Any(), "key", "fp", gomock.Any(), gomock.Any()).
+					Return(&model.Key{Fingerprint: pointer.ToString("other"), StatusCode: pointer.ToInt(200)}, false, nil).Times(1)
+			},
+			wantErr: idempotency.ErrKeyReused,
+		},
+		{
+			name: "in progress",
+			key:  "key",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().AcquireKey(gomock.Any(), "key", "fp", gomock.Any(), gomock.Any()).
+					Return(&model.Key{Fingerprint: pointer.ToString("fp")}, false, nil).Times(1)
+			},
+			wantErr: idempotency.ErrKeyInProgress,
+		},
+		{
+			name: "released meanwhile",
+			key:  "key",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().AcquireKey(gomock.Any(), "key", "fp", gomock.Any(), gomock.Any()).Return(nil, false, gorm.ErrRecordNotFound).Times(1)
+			},
+			wantErr: idempotency.ErrKeyInProgress,
+		},
+		{
+			name: "store failure",
+			key:  "key",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().AcquireKey(gomock.Any(), "key", "fp", gomock.Any(), gomock.Any()).Return(nil, false, errors.Error("test fail")).Times(1)
+			},
+			wantErr: errors.Error("test fail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			i := idempotency.New(s, time.Hour)
+
+			k, err := i.Begin(context.Background(), tt.key, "fp")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, k)
+		})
+	}
+}
+
+func TestIdempotencyService_Complete_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().CompleteKey(gomock.Any(), gomock.AssignableToTypeOf(&model.Key{})).
+		DoAndReturn(func(ctx context.Context, k *model.Key) error {
+			assert.Equal(t, "key", pointer.GetString(k.Key))
+			assert.Equal(t, 201, pointer.GetInt(k.StatusCode))
+			assert.Equal(t, []byte(`{"data":{}}`), k.Body)
+
+			var header map[string][]string
+			require.NoError(t, json.Unmarshal(k.Header, &header))
+			assert.Equal(t, map[string][]string{"Content-Type": {"application/json"}}, header)
+			return nil
+		}).Times(1)
+
+	i := idempotency.New(s, time.Hour)
+
+	err := i.Complete(context.Background(), "key", 201, map[string][]string{"Content-Type": {"application/json"}}, []byte(`{"data":{}}`))
+	assert.NoError(t, err)
+}
+
+func TestIdempotencyService_Sweep_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().DeleteExpiredKeys(gomock.Any()).Return(int64(3), nil).Times(1)
+
+	i := idempotency.New(s, time.Hour)
+
+	n, err := i.Sweep(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Key is an idempotency key together with the request it was first used for and,
+// once that request has completed, the response to replay. StatusCode is nil
+// while the request is still being processed.
+type Key struct {
+	Key         *string         `json:"key" db:"key" gorm:"primaryKey"`
+	Fingerprint *string         `json:"fingerprint" db:"fingerprint"`
+	StatusCode  *int            `json:"status_code" db:"status_code"`
+	Header      json.RawMessage `json:"header" db:"header" gorm:"type:jsonb"`
+	Body        []byte          `json:"body" db:"body"`
+	CreatedAt   *time.Time      `json:"created_at" db:"created_at"`
+	ExpiresAt   *time.Time      `json:"expires_at" db:"expires_at"`
+}
+
+func (Key) TableName() string {
+	return "idempotency_keys"
+}
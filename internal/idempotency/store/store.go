@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// AcquireKey records a key for the request with the given fingerprint and reports
+// whether it was acquired. An existing key is only taken over once it has expired,
+// or when its request has not completed within lockTimeout, which means whoever
+// processed it gave up. Otherwise the existing key is returned.
+func (s *Store) AcquireKey(ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration) (*model.Key, bool, error) {
+	var k model.Key
+
+	res := s.db.WithContext(ctx).Raw(`
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+		VALUES (?, ?, now() + make_interval(secs => ?))
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, header = NULL, body = NULL,
+			created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < now() - make_interval(secs => ?))
+		RETURNING *`,
+		key, fingerprint, ttl.Seconds(), lockTimeout.Seconds(),
+	).Scan(&k)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return &k, true, nil
+	}
+
+	if err := s.db.WithContext(ctx).Where("key = ?", key).Take(&k).Error; err != nil {
+		return nil, false, err
+	}
+
+	return &k, false, nil
+}
+
+// CompleteKey stores the response of the request a key was acquired for.
+func (s *Store) CompleteKey(ctx context.Context, k *model.Key) error {
+	return s.db.WithContext(ctx).Model(k).
+		Select("status_code", "header", "body").
+		Updates(k).Error
+}
+
+func (s *Store) DeleteKey(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Delete(&model.Key{}, "key = ?", key).Error
+}
+
+// DeleteExpiredKeys removes the keys past their expiry and returns how many there were.
+func (s *Store) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	res := s.db.WithContext(ctx).Delete(&model.Key{}, "expires_at < now()")
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,288 @@
+package imports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrJobNotFound     = errors.Error("import_job_not_found: import job not found")
+	ErrUnknownSource   = errors.Error("unknown_import_source: source must be one of slack or telegram")
+	ErrEmptyArchive    = errors.Error("empty_archive: no archive was uploaded")
+	ErrArchiveTooLarge = errors.Error("archive_too_large: the archive exceeds the maximum size")
+	ErrInvalidArchive  = errors.Error("invalid_archive: the archive could not be read")
+)
+
+const (
+	errNoConversations = errors.Error("no conversations found")
+	errMissingChatID   = errors.Error("chat without an id")
+)
+
+const (
+	// batchSize is the number of messages written, and progress reported, at a time.
+	batchSize = 1000
+	// staleAfter is how long a running job may go without reporting progress before
+	// it is considered abandoned by a worker that died and is taken over.
+	staleAfter = 5 * time.Minute
+
+	maxTitleLength  = 200
+	maxAuthorLength = 100
+	maxErrorLength  = 1024
+)
+
+// Parser reads the chats of an export archive.
+type Parser func(archive []byte) ([]model.Chat, error)
+
+var parsers = map[string]Parser{
+	model.SourceSlack:    ParseSlack,
+	model.SourceTelegram: ParseTelegram,
+}
+
+type Store interface {
+	InsertJob(ctx context.Context, j *model.Job) (*model.Job, error)
+	GetJob(ctx context.Context, id string) (*model.Job, error)
+	ClaimJob(ctx context.Context, staleAfter time.Duration) (*model.Job, error)
+	UpdateJob(ctx context.Context, j *model.Job) error
+	FindChat(ctx context.Context, source, sourceID string) (string, error)
+	ImportChat(ctx context.Context, source string, c *model.Chat) (string, bool, error)
+	ImportMessages(ctx context.Context, chatID string, ms []model.Message) (int, error)
+	CountMessages(ctx context.Context, chatID string, sourceIDs []string) (int, error)
+}
+
+// ImportService imports chats from the export archives of other chat services.
+// Imports run in the background as jobs, which any instance of the service may
+// pick up, and can be re-run: chats and messages are keyed by their ids in the
+// source, so those imported before are skipped.
+type ImportService struct {
+	store    Store
+	interval time.Duration
+}
+
+func New(s Store, interval time.Duration) *ImportService {
+	return &ImportService{
+		store:    s,
+		interval: interval,
+	}
+}
+
+// CreateJob queues the import of an archive. The archive is only read once the job
+// runs, and a dry run reports what the import would do without writing anything.
+func (i *ImportService) CreateJob(ctx context.Context, source string, dryRun bool, archive []byte) (*model.Job, error) {
+	if _, ok := parsers[source]; !ok {
+		return nil, ErrUnknownSource
+	}
+	if len(archive) == 0 {
+		return nil, ErrEmptyArchive
+	}
+
+	return i.store.InsertJob(ctx, &model.Job{
+		Source:  pointer.ToString(source),
+		Status:  pointer.ToString(model.JobPending),
+		DryRun:  pointer.ToBool(dryRun),
+		Archive: archive,
+	})
+}
+
+func (i *ImportService) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	j, err := i.store.GetJob(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return j, err
+}
+
+// Listen runs queued import jobs until the context is cancelled.
+func (i *ImportService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("import worker starting")
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := i.RunNext(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to run import job", zap.Error(err))
+		}
+
+		// Another job may be waiting right behind the one that ran.
+		if err == nil && ran {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("import worker stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNext claims the oldest queued job and runs it to completion, reporting
+// whether there was a job to run. A job that fails is marked as failed with the
+// reason; the returned error only reports failures to claim or record a job.
+//
+// A job interrupted by the context being cancelled is left running, so it is
+// taken over, and resumed thanks to the skipped duplicates, once it is stale.
+func (i *ImportService) RunNext(ctx context.Context) (bool, error) {
+	j, err := i.store.ClaimJob(ctx, staleAfter)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	runErr := i.run(ctx, j)
+	if runErr != nil && ctx.Err() != nil {
+		return true, runErr
+	}
+
+	j.Status = pointer.ToString(model.JobSucceeded)
+	j.Error = nil
+	if runErr != nil {
+		logging.From(ctx).Error("import job failed", zap.String("job_id", pointer.GetString(j.ID)), zap.Error(runErr))
+
+		j.Status = pointer.ToString(model.JobFailed)
+		j.Error = pointer.ToString(truncate(runErr.Error(), maxErrorLength))
+	}
+	j.FinishedAt = pointer.ToTime(time.Now().UTC())
+	// The archive is no longer needed once the job is finished.
+	j.Archive = nil
+
+	return true, i.store.UpdateJob(ctx, j)
+}
+
+func (i *ImportService) run(ctx context.Context, j *model.Job) error {
+	parse := parsers[pointer.GetString(j.Source)]
+	if parse == nil {
+		return ErrUnknownSource
+	}
+
+	chats, err := parse(j.Archive)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for k := range chats {
+		normalize(&chats[k])
+		total += len(chats[k].Messages)
+	}
+
+	j.ChatsTotal = pointer.ToInt(len(chats))
+	j.ChatsCreated = pointer.ToInt(0)
+	j.MessagesTotal = pointer.ToInt(total)
+	j.MessagesImported = pointer.ToInt(0)
+	j.MessagesSkipped = pointer.ToInt(0)
+	if err := i.store.UpdateJob(ctx, j); err != nil {
+		return err
+	}
+
+	for k := range chats {
+		if err := i.importChat(ctx, j, &chats[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importChat imports one chat in batches, recording the progress after each.
+func (i *ImportService) importChat(ctx context.Context, j *model.Job, c *model.Chat) error {
+	source := pointer.GetString(j.Source)
+	dryRun := pointer.GetBool(j.DryRun)
+
+	var (
+		chatID  string
+		created bool
+		err     error
+	)
+	if dryRun {
+		chatID, err = i.store.FindChat(ctx, source, c.SourceID)
+		created = errors.Is(err, gorm.ErrRecordNotFound)
+		if created {
+			err = nil
+		}
+	} else {
+		chatID, created, err = i.store.ImportChat(ctx, source, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	if created {
+		j.ChatsCreated = pointer.ToInt(pointer.GetInt(j.ChatsCreated) + 1)
+	}
+
+	for start := 0; start < len(c.Messages); start += batchSize {
+		batch := c.Messages[start:min(start+batchSize, len(c.Messages))]
+
+		var imported int
+		switch {
+		case dryRun && created:
+			imported = len(batch)
+		case dryRun:
+			existing, err := i.store.CountMessages(ctx, chatID, sourceIDs(batch))
+			if err != nil {
+				return err
+			}
+			imported = len(batch) - existing
+		default:
+			if imported, err = i.store.ImportMessages(ctx, chatID, batch); err != nil {
+				return err
+			}
+		}
+
+		j.MessagesImported = pointer.ToInt(pointer.GetInt(j.MessagesImported) + imported)
+		j.MessagesSkipped = pointer.ToInt(pointer.GetInt(j.MessagesSkipped) + len(batch) - imported)
+		if err := i.store.UpdateJob(ctx, j); err != nil {
+			return err
+		}
+	}
+
+	// Chats without messages still count towards the progress.
+	if len(c.Messages) == 0 {
+		return i.store.UpdateJob(ctx, j)
+	}
+
+	return nil
+}
+
+// normalize fits a chat and its messages into the limits of the chats and
+// messages tables.
+func normalize(c *model.Chat) {
+	c.Title = truncate(firstNonEmpty(c.Title, c.SourceID), maxTitleLength)
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	}
+
+	for k := range c.Messages {
+		c.Messages[k].Author = truncate(c.Messages[k].Author, maxAuthorLength)
+	}
+}
+
+func sourceIDs(ms []model.Message) []string {
+	ids := make([]string, len(ms))
+	for k, m := range ms {
+		ids[k] = m.SourceID
+	}
+	return ids
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
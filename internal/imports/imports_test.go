@@ -0,0 +1,458 @@
+package imports_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func slackArchive(t *testing.T, files map[string]interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(f).Encode(content))
+	}
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func testSlackArchive(t *testing.T) []byte {
+	return slackArchive(t, map[string]interface{}{
+		"users.json": []map[string]interface{}{
+			{"id": "U1", "name": "alice", "profile": map[string]string{"display_name": "Alice"}},
+			{"id": "U2", "name": "bob", "real_name": "Bob Smith"},
+		},
+		"channels.json": []map[string]interface{}{
+			{"id": "C1", "name": "general", "created": 1577836800},
+		},
+		"dms.json": []map[string]interface{}{
+			{"id": "D1", "created": 1577836800, "members": []string{"U1", "U2"}},
+		},
+		"general/2020-01-02.json": []map[string]interface{}{
+			{"type": "message", "user": "U2", "text": "later", "ts": "1577959200.000200"},
+		},
+		"general/2020-01-01.json": []map[string]interface{}{
+			{"type": "message", "subtype": "channel_join", "user": "U1", "text": "<@U1> has joined the channel", "ts": "1577872800.000100"},
+			{"type": "message", "user": "U1", "text": "hi <@U2|bob>, see <#C1> &amp; <https://example.com|the docs>", "ts": "1577872900.000100"},
+			{"type": "message", "user": "U1", "text": "", "ts": "1577873000.000100"},
+		},
+		"D1/2020-01-01.json": []map[string]interface{}{
+			{"type": "message", "user": "U3", "user_profile": map[string]string{"real_name": "Carol"}, "text": "psst", "ts": "1577873100.000000"},
+		},
+	})
+}
+
+func TestParseSlack_Success(t *testing.T) {
+	chats, err := imports.ParseSlack(testSlackArchive(t))
+	require.NoError(t, err)
+
+	day := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []model.Chat{
+		{
+			SourceID:  "C1",
+			Title:     "#general",
+			CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Messages: []model.Message{
+				{SourceID: "1577872800.000100", Author: "Alice", Text: "@Alice has joined the channel", System: true, CreatedAt: day.Add(100 * time.Microsecond)},
+				{SourceID: "1577872900.000100", Author: "Alice", Text: "hi @bob, see #general & the docs (https://example.com)", CreatedAt: day.Add(100*time.Second + 100*time.Microsecond)},
+				{SourceID: "1577959200.000200", Author: "Bob Smith", Text: "later", CreatedAt: day.Add(24*time.Hour + 200*time.Microsecond)},
+			},
+		},
+		{
+			SourceID:  "D1",
+			Title:     "Alice, Bob Smith",
+			CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Messages: []model.Message{
+				{SourceID: "1577873100.000000", Author: "Carol", Text: "psst", CreatedAt: day.Add(300 * time.Second)},
+			},
+		},
+	}, chats)
+}
+
+func TestParseSlack_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive func(t *testing.T) []byte
+	}{
+		{
+			name:    "not a zip",
+			archive: func(t *testing.T) []byte { return []byte("not a zip") },
+		},
+		{
+			name: "no conversations",
+			archive: func(t *testing.T) []byte {
+				return slackArchive(t, map[string]interface{}{"users.json": []interface{}{}})
+			},
+		},
+		{
+			name: "invalid timestamp",
+			archive: func(t *testing.T) []byte {
+				return slackArchive(t, map[string]interface{}{
+					"channels.json":           []map[string]interface{}{{"id": "C1", "name": "general"}},
+					"general/2020-01-01.json": []map[string]interface{}{{"type": "message", "text": "hi", "ts": "yesterday"}},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := imports.ParseSlack(tt.archive(t))
+			assert.ErrorIs(t, err, imports.ErrInvalidArchive)
+		})
+	}
+}
+
+const testTelegramChat = `{
+	"name": "Team",
+	"type": "private_group",
+	"id": 42,
+	"messages": [
+		{"id": 1, "type": "service", "date": "2021-03-04T05:06:07", "actor": "Alice", "action": "create_group", "text": ""},
+		{"id": 2, "type": "message", "date": "2021-03-04T05:07:00", "date_unixtime": "1614834420", "from": "Alice", "from_id": "user1",
+			"text": ["see ", {"type": "link", "text": "https://example.com"}, " and ", {"type": "bold", "text": "this"}]},
+		{"id": 3, "type": "message", "date": "2021-03-04T05:08:00", "from": null, "from_id": "user2", "text": "plain"},
+		{"id": 4, "type": "message", "date": "2021-03-04T05:09:00", "from": "Bob", "text": "", "media_type": "sticker"}
+	]
+}`
+
+func TestParseTelegram_Success(t *testing.T) {
+	chat := model.Chat{
+		SourceID:  "42",
+		Title:     "Team",
+		CreatedAt: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		Messages: []model.Message{
+			{SourceID: "1", Text: "Alice create group", System: true, CreatedAt: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+			{SourceID: "2", Author: "Alice", Text: "see https://example.com and this", CreatedAt: time.Date(2021, 3, 4, 5, 7, 0, 0, time.UTC)},
+			{SourceID: "3", Author: "user2", Text: "plain", CreatedAt: time.Date(2021, 3, 4, 5, 8, 0, 0, time.UTC)},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		archive string
+		want    []model.Chat
+	}{
+		{
+			name:    "single chat",
+			archive: testTelegramChat,
+			want:    []model.Chat{chat},
+		},
+		{
+			name:    "whole account",
+			archive: `{"chats": {"list": [` + testTelegramChat + `, {"type": "saved_messages", "id": 7, "messages": []}]}}`,
+			want:    []model.Chat{chat, {SourceID: "7", Title: "Saved Messages"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chats, err := imports.ParseTelegram([]byte(tt.archive))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, chats)
+		})
+	}
+}
+
+func TestParseTelegram_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+	}{
+		{name: "not json", archive: "PK\x03\x04"},
+		{name: "no chats", archive: `{"about": "nothing here"}`},
+		{name: "chat without id", archive: `{"chats": {"list": [{"name": "Team", "messages": []}]}}`},
+		{name: "invalid date", archive: `{"id": 1, "messages": [{"id": 1, "type": "message", "date": "yesterday", "text": "hi"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := imports.ParseTelegram([]byte(tt.archive))
+			assert.ErrorIs(t, err, imports.ErrInvalidArchive)
+		})
+	}
+}
+
+func TestImportService_CreateJob_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+
+	i := imports.New(s, time.Second)
+	require.NotNil(t, i)
+
+	archive := []byte(testTelegramChat)
+
+	s.EXPECT().InsertJob(gomock.Any(), gomock.AssignableToTypeOf(&model.Job{})).
+		DoAndReturn(func(ctx context.Context, j *model.Job) (*model.Job, error) {
+			assert.Equal(t, model.SourceTelegram, pointer.GetString(j.Source))
+			assert.Equal(t, model.JobPending, pointer.GetString(j.Status))
+			assert.True(t, pointer.GetBool(j.DryRun))
+			assert.Equal(t, archive, j.Archive)
+
+			j.ID = pointer.ToString("1")
+			return j, nil
+		}).Times(1)
+
+	j, err := i.CreateJob(context.Background(), model.SourceTelegram, true, archive)
+	require.NoError(t, err)
+	assert.Equal(t, "1", pointer.GetString(j.ID))
+}
+
+func TestImportService_CreateJob_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		archive []byte
+		setup   func(s *mocks.MockStore)
+		wantErr error
+	}{
+		{
+			name:    "unknown source",
+			source:  "discord",
+			archive: []byte("{}"),
+			wantErr: imports.ErrUnknownSource,
+		},
+		{
+			name:    "empty archive",
+			source:  model.SourceSlack,
+			wantErr: imports.ErrEmptyArchive,
+		},
+		{
+			name:    "store failure",
+			source:  model.SourceSlack,
+			archive: []byte("PK"),
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().InsertJob(gomock.Any(), gomock.Any()).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantErr: errors.Error("test fail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			i := imports.New(s, time.Second)
+
+			_, err := i.CreateJob(context.Background(), tt.source, false, tt.archive)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestImportService_GetJob_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetJob(gomock.Any(), "1").Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	i := imports.New(s, time.Second)
+
+	_, err := i.GetJob(context.Background(), "1")
+	assert.ErrorIs(t, err, imports.ErrJobNotFound)
+}
+
+// lastUpdate records the state of a job every time it is updated and returns
+// the latest one.
+func lastUpdate(s *mocks.MockStore) func() model.Job {
+	var last model.Job
+	s.EXPECT().UpdateJob(gomock.Any(), gomock.AssignableToTypeOf(&model.Job{})).
+		DoAndReturn(func(ctx context.Context, j *model.Job) error {
+			last = *j
+			return nil
+		}).AnyTimes()
+
+	return func() model.Job { return last }
+}
+
+func TestImportService_RunNext_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	last := lastUpdate(s)
+
+	i := imports.New(s, time.Second)
+
+	s.EXPECT().ClaimJob(gomock.Any(), gomock.Any()).Return(&model.Job{
+		ID:      pointer.ToString("1"),
+		Source:  pointer.ToString(model.SourceSlack),
+		Status:  pointer.ToString(model.JobRunning),
+		DryRun:  pointer.ToBool(false),
+		Archive: testSlackArchive(t),
+	}, nil).Times(1)
+
+	// #general is imported for the first time, the DM was imported before along
+	// with its only message.
+	s.EXPECT().ImportChat(gomock.Any(), model.SourceSlack, gomock.AssignableToTypeOf(&model.Chat{})).
+		DoAndReturn(func(ctx context.Context, source string, c *model.Chat) (string, bool, error) {
+			if c.SourceID == "C1" {
+				return "10", true, nil
+			}
+			return "11", false, nil
+		}).Times(2)
+	s.EXPECT().ImportMessages(gomock.Any(), "10", gomock.Len(3)).Return(3, nil).Times(1)
+	s.EXPECT().ImportMessages(gomock.Any(), "11", gomock.Len(1)).Return(0, nil).Times(1)
+
+	ran, err := i.RunNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	j := last()
+	assert.Equal(t, model.JobSucceeded, pointer.GetString(j.Status))
+	assert.Nil(t, j.Error)
+	assert.Nil(t, j.Archive)
+	assert.NotNil(t, j.FinishedAt)
+	assert.Equal(t, 2, pointer.GetInt(j.ChatsTotal))
+	assert.Equal(t, 1, pointer.GetInt(j.ChatsCreated))
+	assert.Equal(t, 4, pointer.GetInt(j.MessagesTotal))
+	assert.Equal(t, 3, pointer.GetInt(j.MessagesImported))
+	assert.Equal(t, 1, pointer.GetInt(j.MessagesSkipped))
+}
+
+func TestImportService_RunNext_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	last := lastUpdate(s)
+
+	i := imports.New(s, time.Second)
+
+	archive := `{"chats": {"list": [` + testTelegramChat + `, {"name": "New", "id": 43, "messages": [
+		{"id": 1, "type": "message", "date": "2021-03-04T05:06:07", "from": "Bob", "text": "hello"}
+	]}]}}`
+
+	s.EXPECT().ClaimJob(gomock.Any(), gomock.Any()).Return(&model.Job{
+		ID:      pointer.ToString("1"),
+		Source:  pointer.ToString(model.SourceTelegram),
+		Status:  pointer.ToString(model.JobRunning),
+		DryRun:  pointer.ToBool(true),
+		Archive: []byte(archive),
+	}, nil).Times(1)
+
+	s.EXPECT().FindChat(gomock.Any(), model.SourceTelegram, "42").Return("10", nil).Times(1)
+	s.EXPECT().FindChat(gomock.Any(), model.SourceTelegram, "43").Return("", gorm.ErrRecordNotFound).Times(1)
+	s.EXPECT().CountMessages(gomock.Any(), "10", []string{"1", "2", "3"}).Return(2, nil).Times(1)
+
+	ran, err := i.RunNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	j := last()
+	assert.Equal(t, model.JobSucceeded, pointer.GetString(j.Status))
+	assert.Equal(t, 2, pointer.GetInt(j.ChatsTotal))
+	assert.Equal(t, 1, pointer.GetInt(j.ChatsCreated))
+	assert.Equal(t, 4, pointer.GetInt(j.MessagesTotal))
+	assert.Equal(t, 2, pointer.GetInt(j.MessagesImported))
+	assert.Equal(t, 2, pointer.GetInt(j.MessagesSkipped))
+}
+
+func TestImportService_RunNext_Error(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       *model.Job
+		claimErr  error
+		setup     func(s *mocks.MockStore)
+		wantRan   bool
+		wantErr   error
+		wantError string
+	}{
+		{
+			name:     "no job",
+			claimErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:     "claim failure",
+			claimErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+		{
+			name: "invalid archive",
+			job: &model.Job{
+				ID:      pointer.ToString("1"),
+				Source:  pointer.ToString(model.SourceSlack),
+				DryRun:  pointer.ToBool(false),
+				Archive: []byte("not a zip"),
+			},
+			wantRan:   true,
+			wantError: "invalid_archive: the archive could not be read -- zip: not a valid zip file",
+		},
+		{
+			name: "import failure",
+			job: &model.Job{
+				ID:      pointer.ToString("1"),
+				Source:  pointer.ToString(model.SourceTelegram),
+				DryRun:  pointer.ToBool(false),
+				Archive: []byte(testTelegramChat),
+			},
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ImportChat(gomock.Any(), model.SourceTelegram, gomock.Any()).Return("10", true, nil).Times(1)
+				s.EXPECT().ImportMessages(gomock.Any(), "10", gomock.Any()).Return(0, errors.Error("test fail")).Times(1)
+			},
+			wantRan:   true,
+			wantError: "test fail",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			last := lastUpdate(s)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			s.EXPECT().ClaimJob(gomock.Any(), gomock.Any()).Return(tt.job, tt.claimErr).Times(1)
+
+			i := imports.New(s, time.Second)
+
+			ran, err := i.RunNext(context.Background())
+			assert.Equal(t, tt.wantRan, ran)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.wantError != "" {
+				j := last()
+				assert.Equal(t, model.JobFailed, pointer.GetString(j.Status))
+				assert.Equal(t, tt.wantError, pointer.GetString(j.Error))
+				assert.Nil(t, j.Archive)
+			}
+		})
+	}
+}
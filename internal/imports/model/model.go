@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+const (
+	SourceSlack    = "slack"
+	SourceTelegram = "telegram"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is an import of one export archive. The counters report its progress while
+// it runs; in a dry run they tell what an import would create and skip.
+type Job struct {
+	ID               *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	Source           *string    `json:"source" db:"source"`
+	Status           *string    `json:"status" db:"status"`
+	DryRun           *bool      `json:"dry_run" db:"dry_run"`
+	Archive          []byte     `json:"-" db:"archive"`
+	ChatsTotal       *int       `json:"chats_total" db:"chats_total"`
+	ChatsCreated     *int       `json:"chats_created" db:"chats_created"`
+	MessagesTotal    *int       `json:"messages_total" db:"messages_total"`
+	MessagesImported *int       `json:"messages_imported" db:"messages_imported"`
+	MessagesSkipped  *int       `json:"messages_skipped" db:"messages_skipped"`
+	Error            *string    `json:"error" db:"error"`
+	CreatedAt        *time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        *time.Time `json:"updated_at" db:"updated_at"`
+	StartedAt        *time.Time `json:"started_at" db:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at" db:"finished_at"`
+}
+
+func (Job) TableName() string {
+	return "import_jobs"
+}
+
+// Chat is a conversation read from an export archive, identified by its id in
+// the source system.
+type Chat struct {
+	SourceID  string
+	Title     string
+	CreatedAt time.Time
+	Messages  []Message
+}
+
+// Message is a message read from an export archive. SourceID is unique within
+// its chat in the source system.
+type Message struct {
+	SourceID  string
+	Author    string
+	Text      string
+	System    bool
+	CreatedAt time.Time
+}
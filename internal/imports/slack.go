@@ -0,0 +1,309 @@
+package imports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+)
+
+// maxEntrySize is the largest uncompressed size read from a single archive entry.
+const maxEntrySize = 512 << 20
+
+// slackSystemSubtypes are the message subtypes Slack uses for membership and
+// channel changes, which are imported as system messages.
+var slackSystemSubtypes = map[string]bool{
+	"channel_join":      true,
+	"channel_leave":     true,
+	"channel_topic":     true,
+	"channel_purpose":   true,
+	"channel_name":      true,
+	"channel_archive":   true,
+	"channel_unarchive": true,
+	"group_join":        true,
+	"group_leave":       true,
+	"group_topic":       true,
+	"group_purpose":     true,
+	"group_name":        true,
+	"group_archive":     true,
+	"group_unarchive":   true,
+	"pinned_item":       true,
+	"unpinned_item":     true,
+}
+
+// slackConversations are the conversation lists of an export. Channels, private
+// channels and group DMs keep their history in a directory named after the
+// conversation, DMs in one named after their id.
+var slackConversations = []struct {
+	file   string
+	byID   bool
+	prefix string
+}{
+	{file: "channels.json", prefix: "#"},
+	{file: "groups.json", prefix: "#"},
+	{file: "mpims.json"},
+	{file: "dms.json", byID: true},
+}
+
+type slackConversation struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Created int64    `json:"created"`
+	Members []string `json:"members"`
+}
+
+type slackUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Profile  struct {
+		DisplayName string `json:"display_name"`
+		RealName    string `json:"real_name"`
+	} `json:"profile"`
+}
+
+type slackMessage struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	User        string `json:"user"`
+	Username    string `json:"username"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	UserProfile *struct {
+		DisplayName string `json:"display_name"`
+		RealName    string `json:"real_name"`
+	} `json:"user_profile"`
+}
+
+// ParseSlack reads a Slack workspace export zip. Every public and private channel,
+// group DM and DM in the export becomes a chat keyed by its Slack id, and every
+// message with text becomes a message keyed by its timestamp, which Slack uses as
+// the message id within a conversation.
+func ParseSlack(archive []byte) ([]model.Chat, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, ErrInvalidArchive.Wrap(err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var users []slackUser
+	if f, ok := files["users.json"]; ok {
+		if err := readZipJSON(f, &users); err != nil {
+			return nil, err
+		}
+	}
+
+	names := map[string]string{}
+	for _, u := range users {
+		names[u.ID] = firstNonEmpty(u.Profile.DisplayName, u.Profile.RealName, u.RealName, u.Name)
+	}
+
+	channels := map[string]string{}
+	var chats []model.Chat
+	found := false
+
+	for _, kind := range slackConversations {
+		f, ok := files[kind.file]
+		if !ok {
+			continue
+		}
+		found = true
+
+		var convs []slackConversation
+		if err := readZipJSON(f, &convs); err != nil {
+			return nil, err
+		}
+
+		for _, conv := range convs {
+			if conv.Name != "" {
+				channels[conv.ID] = conv.Name
+			}
+
+			dir := conv.Name
+			if kind.byID {
+				dir = conv.ID
+			}
+
+			title := kind.prefix + conv.Name
+			if kind.prefix == "" {
+				title = memberNames(conv.Members, names)
+			}
+
+			ms, err := readSlackHistory(zr.File, dir, names)
+			if err != nil {
+				return nil, err
+			}
+
+			chats = append(chats, model.Chat{
+				SourceID:  conv.ID,
+				Title:     title,
+				CreatedAt: time.Unix(conv.Created, 0).UTC(),
+				Messages:  ms,
+			})
+		}
+	}
+
+	if !found {
+		return nil, ErrInvalidArchive.Wrap(errNoConversations)
+	}
+
+	// Mentions can refer to any channel, so they are resolved once every
+	// conversation is known.
+	r := slackRenderer{users: names, channels: channels}
+	for i := range chats {
+		for j := range chats[i].Messages {
+			chats[i].Messages[j].Text = r.render(chats[i].Messages[j].Text)
+		}
+	}
+
+	return chats, nil
+}
+
+// readSlackHistory reads the daily message files of a conversation in date order.
+func readSlackHistory(files []*zip.File, dir string, names map[string]string) ([]model.Message, error) {
+	var days []*zip.File
+	for _, f := range files {
+		if path.Dir(f.Name) == dir && path.Ext(f.Name) == ".json" {
+			days = append(days, f)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Name < days[j].Name })
+
+	var ms []model.Message
+	for _, f := range days {
+		var day []slackMessage
+		if err := readZipJSON(f, &day); err != nil {
+			return nil, err
+		}
+
+		for _, m := range day {
+			if m.Type != "message" || strings.TrimSpace(m.Text) == "" {
+				continue
+			}
+
+			at, err := parseSlackTS(m.TS)
+			if err != nil {
+				return nil, ErrInvalidArchive.Wrap(fmt.Errorf("%s: %w", f.Name, err))
+			}
+
+			// Bots post under a username of their own. The profile embedded in
+			// the message covers users missing from users.json.
+			author := firstNonEmpty(m.Username, names[m.User])
+			if author == "" && m.UserProfile != nil {
+				author = firstNonEmpty(m.UserProfile.DisplayName, m.UserProfile.RealName)
+			}
+			author = firstNonEmpty(author, m.User)
+
+			ms = append(ms, model.Message{
+				SourceID:  m.TS,
+				Author:    author,
+				Text:      m.Text,
+				System:    slackSystemSubtypes[m.Subtype],
+				CreatedAt: at,
+			})
+		}
+	}
+
+	return ms, nil
+}
+
+// parseSlackTS parses a Slack message timestamp, seconds since the epoch with a
+// six digit fraction.
+func parseSlackTS(ts string) (time.Time, error) {
+	secs, frac, _ := strings.Cut(ts, ".")
+
+	s, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", ts)
+	}
+
+	var us int64
+	if frac != "" {
+		frac = (frac + "000000")[:6]
+		if us, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", ts)
+		}
+	}
+
+	return time.Unix(s, us*int64(time.Microsecond)).UTC(), nil
+}
+
+func readZipJSON(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return ErrInvalidArchive.Wrap(err)
+	}
+	defer rc.Close()
+
+	// Bound what a single entry may expand to, so a small archive cannot
+	// exhaust memory.
+	if err := json.NewDecoder(io.LimitReader(rc, maxEntrySize)).Decode(v); err != nil {
+		return ErrInvalidArchive.Wrap(fmt.Errorf("%s: %w", f.Name, err))
+	}
+
+	return nil
+}
+
+func memberNames(members []string, names map[string]string) string {
+	res := make([]string, 0, len(members))
+	for _, id := range members {
+		res = append(res, firstNonEmpty(names[id], id))
+	}
+	return strings.Join(res, ", ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+var slackEntity = regexp.MustCompile(`<([^<>]+)>`)
+
+var slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// slackRenderer turns Slack message markup into plain text: mentions of users and
+// channels are replaced with their names and links with their labels.
+type slackRenderer struct {
+	users    map[string]string
+	channels map[string]string
+}
+
+func (r slackRenderer) render(text string) string {
+	text = slackEntity.ReplaceAllStringFunc(text, func(entity string) string {
+		ref, label, _ := strings.Cut(entity[1:len(entity)-1], "|")
+
+		switch {
+		case strings.HasPrefix(ref, "@"):
+			return "@" + firstNonEmpty(label, r.users[ref[1:]], ref[1:])
+		case strings.HasPrefix(ref, "#"):
+			return "#" + firstNonEmpty(label, r.channels[ref[1:]], ref[1:])
+		case strings.HasPrefix(ref, "!"):
+			// Special mentions such as <!here>, and user groups and dates, which
+			// carry their rendering as the label.
+			return firstNonEmpty(label, "@"+ref[1:])
+		case label != "" && label != ref:
+			return label + " (" + ref + ")"
+		default:
+			return ref
+		}
+	})
+
+	return slackUnescaper.Replace(text)
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	outboxStore "github.com/Polilo-User/test-task-hitalent/internal/outbox/store"
+
+	"github.com/AlekSi/pointer"
+	"gorm.io/gorm"
+)
+
+// jobColumns are the columns of a job returned to clients, which leave out the archive.
+var jobColumns = []string{
+	"id", "source", "status", "dry_run",
+	"chats_total", "chats_created", "messages_total", "messages_imported", "messages_skipped",
+	"error", "created_at", "updated_at", "started_at", "finished_at",
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+func (s *Store) InsertJob(ctx context.Context, j *model.Job) (*model.Job, error) {
+	if err := s.db.WithContext(ctx).Create(j).Error; err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (s *Store) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	var j model.Job
+
+	if err := s.db.WithContext(ctx).Select(jobColumns).Where("id = ?", id).Take(&j).Error; err != nil {
+		return nil, err
+	}
+
+	return &j, nil
+}
+
+// ClaimJob marks the oldest pending job as running and returns it with its archive.
+// Running jobs that have not reported progress for staleAfter are claimed again,
+// with their counters reset. It returns gorm.ErrRecordNotFound when there is no
+// job to run.
+func (s *Store) ClaimJob(ctx context.Context, staleAfter time.Duration) (*model.Job, error) {
+	var j model.Job
+
+	res := s.db.WithContext(ctx).Raw(`
+		UPDATE import_jobs
+		SET status = ?, started_at = COALESCE(started_at, now()), updated_at = now(),
+			chats_created = 0, messages_imported = 0, messages_skipped = 0, error = NULL
+		WHERE id = (
+			SELECT id FROM import_jobs
+			WHERE status = ? OR (status = ? AND updated_at < now() - make_interval(secs => ?))
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.JobRunning, model.JobPending, model.JobRunning, staleAfter.Seconds(),
+	).Scan(&j)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &j, nil
+}
+
+// UpdateJob records the status and progress of a job, which also marks it as alive.
+func (s *Store) UpdateJob(ctx context.Context, j *model.Job) error {
+	j.UpdatedAt = pointer.ToTime(time.Now().UTC())
+
+	return s.db.WithContext(ctx).Model(j).
+		Select("status", "archive", "chats_total", "chats_created", "messages_total",
+			"messages_imported", "messages_skipped", "error", "updated_at", "finished_at").
+		Updates(j).Error
+}
+
+// FindChat returns the id of the chat imported from the given source chat, or
+// gorm.ErrRecordNotFound when it has not been imported.
+func (s *Store) FindChat(ctx context.Context, source, sourceID string) (string, error) {
+	var c chmodel.Chat
+
+	err := s.db.WithContext(ctx).Table("chats").Select("id").
+		Where("source = ? AND source_id = ?", source, sourceID).
+		Take(&c).Error
+	if err != nil {
+		return "", err
+	}
+
+	return *c.ID, nil
+}
+
+// ImportChat creates the chat imported from a source chat unless it exists, and
+// returns its id and whether it was created.
+func (s *Store) ImportChat(ctx context.Context, source string, c *model.Chat) (string, bool, error) {
+	var (
+		chat    chmodel.Chat
+		created bool
+	)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Raw(`
+			INSERT INTO chats (title, created_at, source, source_id) VALUES (?, ?, ?, ?)
+			ON CONFLICT (source, source_id) WHERE source IS NOT NULL DO NOTHING
+			RETURNING id, title, created_at`,
+			c.Title, c.CreatedAt, source, c.SourceID,
+		).Scan(&chat)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return tx.Table("chats").Select("id").
+				Where("source = ? AND source_id = ?", source, c.SourceID).
+				Take(&chat).Error
+		}
+
+		created = true
+
+		e, err := events.New(events.ChatCreated, *chat.ID, chat)
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return *chat.ID, created, nil
+}
+
+type importedMessage struct {
+	Text      string    `json:"text"`
+	Author    *string   `json:"author"`
+	System    bool      `json:"is_system"`
+	CreatedAt time.Time `json:"created_at"`
+	SourceID  string    `json:"source_id"`
+}
+
+// ImportMessages adds messages to a chat, skipping those imported before, and
+// returns the number of messages added. The batch is passed as a single JSON
+// document so it is written with one statement, and one messages.imported event
+// carries the messages added. The chat is locked first so that messages
+// skipped as duplicates never take a seq.
+func (s *Store) ImportMessages(ctx context.Context, chatID string, ms []model.Message) (int, error) {
+	rows := make([]importedMessage, len(ms))
+	for i, m := range ms {
+		rows[i] = importedMessage{
+			Text:      m.Text,
+			System:    m.System,
+			CreatedAt: m.CreatedAt,
+			SourceID:  m.SourceID,
+		}
+		if m.Author != "" {
+			rows[i].Author = pointer.ToString(m.Author)
+		}
+	}
+
+	doc, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	var added []msmodel.Message
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM chats WHERE id = ? FOR UPDATE", chatID).Error; err != nil {
+			return err
+		}
+
+		err := tx.Raw(`
+			INSERT INTO messages (chat_id, text, author, is_system, created_at, source_id)
+			SELECT ?, m.text, m.author, m.is_system, m.created_at, m.source_id
+			FROM json_to_recordset(?::json) AS m(text text, author text, is_system boolean, created_at timestamptz, source_id text)
+			WHERE NOT EXISTS (SELECT 1 FROM messages e WHERE e.chat_id = ? AND e.source_id = m.source_id)
+			ON CONFLICT (chat_id, source_id) WHERE source_id IS NOT NULL DO NOTHING
+			RETURNING id, chat_id, seq, text, author, is_system, created_at`,
+			chatID, string(doc), chatID,
+		).Scan(&added).Error
+		if err != nil {
+			return err
+		}
+
+		if len(added) == 0 {
+			return nil
+		}
+
+		// Ids and seqs are taken in the same order.
+		sort.Slice(added, func(i, j int) bool {
+			return *added[i].Seq < *added[j].Seq
+		})
+		first, last := added[0], added[len(added)-1]
+
+		e, err := events.New(events.MessagesImported, chatID, map[string]interface{}{
+			"count":     len(added),
+			"first_id":  *first.ID,
+			"last_id":   *last.ID,
+			"first_seq": *first.Seq,
+			"last_seq":  *last.Seq,
+			"messages":  added,
+		})
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(added), nil
+}
+
+// CountMessages returns how many of the given source messages a chat already has.
+func (s *Store) CountMessages(ctx context.Context, chatID string, sourceIDs []string) (int, error) {
+	var n int64
+
+	err := s.db.WithContext(ctx).Table("messages").
+		Where("chat_id = ? AND source_id IN ?", chatID, sourceIDs).
+		Count(&n).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
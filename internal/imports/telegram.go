@@ -0,0 +1,201 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+)
+
+// telegramDateLayout is the layout of the date field, which Telegram writes in
+// the exporting user's local time without an offset.
+const telegramDateLayout = "2006-01-02T15:04:05"
+
+// telegramExport is either the export of a single chat or the export of a whole
+// account, which lists its chats under chats.list.
+type telegramExport struct {
+	telegramChat
+	Chats *struct {
+		List []telegramChat `json:"list"`
+	} `json:"chats"`
+}
+
+type telegramChat struct {
+	ID       *int64            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Messages []telegramMessage `json:"messages"`
+}
+
+type telegramMessage struct {
+	ID           int64        `json:"id"`
+	Type         string       `json:"type"`
+	Date         string       `json:"date"`
+	DateUnixtime string       `json:"date_unixtime"`
+	From         string       `json:"from"`
+	FromID       string       `json:"from_id"`
+	Actor        string       `json:"actor"`
+	Action       string       `json:"action"`
+	Text         telegramText `json:"text"`
+}
+
+// telegramText is the text of a message. Telegram writes plain text as a string
+// and formatted text as a list of strings and entities with a text of their own.
+type telegramText string
+
+func (t *telegramText) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var parts []json.RawMessage
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		for _, part := range parts {
+			var entity struct {
+				Text string `json:"text"`
+			}
+			var s string
+			if err := json.Unmarshal(part, &s); err == nil {
+				sb.WriteString(s)
+			} else if err := json.Unmarshal(part, &entity); err == nil {
+				sb.WriteString(entity.Text)
+			} else {
+				return err
+			}
+		}
+
+		*t = telegramText(sb.String())
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*t = telegramText(s)
+	return nil
+}
+
+// ParseTelegram reads the result.json of a Telegram Desktop export, either of a
+// single chat or of a whole account. Chats are keyed by their Telegram id and
+// messages by their id within the chat. Service messages, such as members joining,
+// are imported as system messages; messages without text, such as stickers and
+// media without a caption, are left out.
+func ParseTelegram(archive []byte) ([]model.Chat, error) {
+	var export telegramExport
+	if err := json.Unmarshal(archive, &export); err != nil {
+		return nil, ErrInvalidArchive.Wrap(err)
+	}
+
+	var list []telegramChat
+	switch {
+	case export.Chats != nil:
+		list = export.Chats.List
+	case export.ID != nil:
+		list = []telegramChat{export.telegramChat}
+	default:
+		return nil, ErrInvalidArchive.Wrap(errNoConversations)
+	}
+
+	chats := make([]model.Chat, 0, len(list))
+	for _, tc := range list {
+		if tc.ID == nil {
+			return nil, ErrInvalidArchive.Wrap(errMissingChatID)
+		}
+
+		c := model.Chat{
+			SourceID: strconv.FormatInt(*tc.ID, 10),
+			Title:    telegramTitle(tc),
+		}
+
+		for _, tm := range tc.Messages {
+			m, ok, err := telegramMessageOf(tm)
+			if err != nil {
+				return nil, ErrInvalidArchive.Wrap(fmt.Errorf("chat %d: %w", *tc.ID, err))
+			}
+			if ok {
+				c.Messages = append(c.Messages, m)
+			}
+		}
+
+		// Exports do not record when a chat was created, so its first message
+		// stands in for it.
+		if len(c.Messages) > 0 {
+			c.CreatedAt = c.Messages[0].CreatedAt
+		}
+
+		chats = append(chats, c)
+	}
+
+	return chats, nil
+}
+
+func telegramTitle(c telegramChat) string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Type == "saved_messages":
+		return "Saved Messages"
+	default:
+		return fmt.Sprintf("Telegram chat %d", *c.ID)
+	}
+}
+
+// telegramMessageOf converts a message, reporting false for messages that are
+// not imported.
+func telegramMessageOf(tm telegramMessage) (model.Message, bool, error) {
+	at, err := telegramTime(tm)
+	if err != nil {
+		return model.Message{}, false, err
+	}
+
+	m := model.Message{
+		SourceID:  strconv.FormatInt(tm.ID, 10),
+		Author:    firstNonEmpty(tm.From, tm.FromID),
+		Text:      string(tm.Text),
+		CreatedAt: at,
+	}
+
+	switch tm.Type {
+	case "message":
+	case "service":
+		m.System = true
+		m.Author = ""
+		if m.Text == "" && tm.Action != "" {
+			m.Text = strings.TrimSpace(tm.Actor + " " + strings.ReplaceAll(tm.Action, "_", " "))
+		}
+	default:
+		return model.Message{}, false, nil
+	}
+
+	if strings.TrimSpace(m.Text) == "" {
+		return model.Message{}, false, nil
+	}
+
+	return m, true, nil
+}
+
+// telegramTime prefers date_unixtime, which newer exports add, over the local
+// date, which is read as UTC.
+func telegramTime(tm telegramMessage) (time.Time, error) {
+	if tm.DateUnixtime != "" {
+		secs, err := strconv.ParseInt(tm.DateUnixtime, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("message %d: invalid date_unixtime %q", tm.ID, tm.DateUnixtime)
+		}
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	at, err := time.ParseInLocation(telegramDateLayout, tm.Date, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("message %d: invalid date %q", tm.ID, tm.Date)
+	}
+
+	return at, nil
+}
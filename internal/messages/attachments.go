@@ -0,0 +1,29 @@
+package messages
+
+import (
+	"context"
+
+	atmodel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+)
+
+type Attachments interface {
+	AttachmentsByMessages(ctx context.Context, messageIDs []string) (map[string][]atmodel.Attachment, error)
+}
+
+// WithAttachments wraps a store so that the messages of chats read from it carry
+// their attachments, with thumbnails for images.
+func WithAttachments(s Store, a Attachments) Store {
+	return enrich(s, func(ctx context.Context, messageIDs []string) (func(m *model.Message), error) {
+		byMessage, err := a.AttachmentsByMessages(ctx, messageIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(m *model.Message) {
+			m.Attachments = byMessage[pointer.GetString(m.ID)]
+		}, nil
+	})
+}
@@ -0,0 +1,73 @@
+package messages_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	atmodel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttachments_GetMessagesByChat_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetMessagesByChat(gomock.Any(), "1", int64(20)).Return([]model.Message{
+		{ID: pointer.ToString("10")},
+		{ID: pointer.ToString("11")},
+	}, nil).Times(1)
+
+	a := mocks.NewMockAttachments(ctrl)
+	a.EXPECT().AttachmentsByMessages(gomock.Any(), []string{"10", "11"}).Return(map[string][]atmodel.Attachment{
+		"11": {{ID: pointer.ToString("5")}},
+	}, nil).Times(1)
+
+	ms, err := messages.WithAttachments(s, a).GetMessagesByChat(context.Background(), "1", 20)
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	assert.Empty(t, ms[0].Attachments)
+	assert.Equal(t, []atmodel.Attachment{{ID: pointer.ToString("5")}}, ms[1].Attachments)
+}
+
+func TestWithAttachments_GetMessagesByChat_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *mocks.MockStore, a *mocks.MockAttachments)
+	}{
+		{
+			name: "messages",
+			setup: func(s *mocks.MockStore, a *mocks.MockAttachments) {
+				s.EXPECT().GetMessagesByChat(gomock.Any(), "1", int64(20)).Return(nil, errors.New("test fail")).Times(1)
+			},
+		},
+		{
+			name: "attachments",
+			setup: func(s *mocks.MockStore, a *mocks.MockAttachments) {
+				s.EXPECT().GetMessagesByChat(gomock.Any(), "1", int64(20)).Return([]model.Message{{ID: pointer.ToString("10")}}, nil).Times(1)
+				a.EXPECT().AttachmentsByMessages(gomock.Any(), []string{"10"}).Return(nil, errors.New("test fail")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			a := mocks.NewMockAttachments(ctrl)
+			tt.setup(s, a)
+
+			_, err := messages.WithAttachments(s, a).GetMessagesByChat(context.Background(), "1", 20)
+			assert.EqualError(t, err, "test fail")
+		})
+	}
+}
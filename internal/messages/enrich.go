@@ -0,0 +1,80 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+)
+
+// enrichment loads something messages carry besides their own columns, for all
+// the messages of a read at once, and returns the func setting it on each of them.
+type enrichment func(ctx context.Context, messageIDs []string) (func(m *model.Message), error)
+
+// enrich wraps a store so that the messages of chats read from it are enriched
+// by e. Every read path returning messages to clients goes through here.
+func enrich(s Store, e enrichment) Store {
+	return &enrichedStore{
+		Store:  s,
+		enrich: e,
+	}
+}
+
+type enrichedStore struct {
+	Store
+	enrich enrichment
+}
+
+func (s *enrichedStore) GetMessagesByChat(ctx context.Context, id string, limit int64) ([]model.Message, error) {
+	ms, err := s.Store.GetMessagesByChat(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (s *enrichedStore) GetMessagesByChats(ctx context.Context, ids []string, limit int64, beforeSeq string) ([]model.Message, error) {
+	ms, err := s.Store.GetMessagesByChats(ctx, ids, limit, beforeSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (s *enrichedStore) ListMessages(ctx context.Context, chatID string, afterSeq, beforeSeq *int64, limit int64) ([]model.Message, error) {
+	ms, err := s.Store.ListMessages(ctx, chatID, afterSeq, beforeSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func (s *enrichedStore) apply(ctx context.Context, ms []model.Message) error {
+	ids := make([]string, 0, len(ms))
+	for _, m := range ms {
+		ids = append(ids, pointer.GetString(m.ID))
+	}
+
+	set, err := s.enrich(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range ms {
+		set(&ms[i])
+	}
+	return nil
+}
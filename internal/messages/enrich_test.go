@@ -0,0 +1,43 @@
+package messages_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	atmodel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	unfurlmodel "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrich_ListMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListMessages(gomock.Any(), "1", nil, nil, int64(20)).Return([]model.Message{
+		{ID: pointer.ToString("10")},
+	}, nil).Times(1)
+
+	a := mocks.NewMockAttachments(ctrl)
+	a.EXPECT().AttachmentsByMessages(gomock.Any(), []string{"10"}).Return(map[string][]atmodel.Attachment{
+		"10": {{ID: pointer.ToString("3")}},
+	}, nil).Times(1)
+
+	p := mocks.NewMockPreviews(ctrl)
+	p.EXPECT().PreviewsByMessages(gomock.Any(), []string{"10"}).Return(map[string][]unfurlmodel.Preview{
+		"10": {{URL: pointer.ToString("https://example.com")}},
+	}, nil).Times(1)
+
+	// The decorators stack as they are wired, each enriching the same read.
+	ms, err := messages.WithPreviews(messages.WithAttachments(s, a), p).ListMessages(context.Background(), "1", nil, nil, 20)
+	require.NoError(t, err)
+	require.Len(t, ms, 1)
+	assert.Equal(t, []atmodel.Attachment{{ID: pointer.ToString("3")}}, ms[0].Attachments)
+	assert.Equal(t, []unfurlmodel.Preview{{URL: pointer.ToString("https://example.com")}}, ms[0].Previews)
+}
@@ -0,0 +1,34 @@
+package messages
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+)
+
+// maxMentions is the number of mentions recorded per message.
+const maxMentions = 50
+
+// mentionPattern matches @username where it is not part of a word, such as an
+// email address. Usernames may contain dots and dashes but not end with them, so
+// punctuation ending a sentence is left out.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\pL\pN_@])@([\pL\pN_](?:[\pL\pN_.-]{0,98}[\pL\pN_])?)`)
+
+// ParseMentions returns every @username in a text, in order.
+func ParseMentions(text string) model.Mentions {
+	var mentions model.Mentions
+
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(text, maxMentions) {
+		// The @ is right before the username.
+		start, end := loc[2]-1, loc[3]
+
+		mentions = append(mentions, model.Mention{
+			Username: text[loc[2]:loc[3]],
+			Offset:   utf8.RuneCountInString(text[:start]),
+			Length:   utf8.RuneCountInString(text[start:end]),
+		})
+	}
+
+	return mentions
+}
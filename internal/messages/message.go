@@ -2,39 +2,280 @@ package messages
 
 import (
 	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxBatchSize is the largest number of messages accepted by CreateMessages.
+	MaxBatchSize = 50000
+
+	maxAuthorLength = 100
+)
+
+const (
+	// ErrEmptyBatch is returned when a batch contains no messages.
+	ErrEmptyBatch = errors.Error("empty_batch: batch contains no messages")
+	// ErrBatchTooLarge is returned when a batch exceeds MaxBatchSize.
+	ErrBatchTooLarge = errors.Error("batch_too_large: batch contains too many messages")
+	// ErrInvalidRange is returned when an export range ends before it starts.
+	ErrInvalidRange = errors.Error("invalid_range: from must be before to")
+	// ErrBatchRejected is returned by an atomic batch with invalid messages, none of
+	// which have been stored.
+	ErrBatchRejected = errors.Error("batch_rejected: batch contains invalid messages")
+	// ErrMessageNotFound is returned when a message does not exist in its chat.
+	ErrMessageNotFound = errors.Error("message_not_found: message not found")
+	// ErrEmptyText is returned when a message is edited to have no text.
+	ErrEmptyText = errors.Error("empty_text: message text is required")
+	// ErrInvalidSeqRange is returned when messages are listed after a seq that is
+	// not below the seq they are listed before.
+	ErrInvalidSeqRange = errors.Error("invalid_seq_range: after_seq must be below before_seq")
+	// ErrInvalidExpiry is returned when a message is posted with an expiry that has passed.
+	ErrInvalidExpiry = errors.Error("invalid_expires_at: expires_at must be in the future")
 )
 
 type Store interface {
 	GetMessagesByChat(ctx context.Context, id string, limit int64) ([]model.Message, error)
+	GetMessagesByChats(ctx context.Context, ids []string, limit int64, beforeSeq string) ([]model.Message, error)
+	ListMessages(ctx context.Context, chatID string, afterSeq, beforeSeq *int64, limit int64) ([]model.Message, error)
 	InsertMessage(ctx context.Context, c *model.Message) (*model.Message, error)
+	InsertMessages(ctx context.Context, chatID string, ms []model.Message) ([]model.Message, error)
+	UpdateMessage(ctx context.Context, m *model.Message) (*model.Message, error)
+	DeleteMessage(ctx context.Context, chatID, id string) error
+	StreamMessages(ctx context.Context, chatID string, from, to *time.Time, fn func([]model.Message) error) error
+	DeleteExpiredMessages(ctx context.Context, limit int) (int, error)
 }
 
 type ChatService interface {
 	ChatExist(ctx context.Context, id string) error
+	CheckLegalHold(ctx context.Context, id string) error
+}
+
+type Commands interface {
+	Lookup(name string) (commands.Handler, bool)
 }
 
 type MessageService struct {
-	store Store
-	c     ChatService
+	store    Store
+	c        ChatService
+	commands Commands
 }
 
-func New(s Store, c ChatService) *MessageService {
+func New(s Store, c ChatService, cmd Commands) *MessageService {
 	return &MessageService{
-		store: s,
-		c:     c,
+		store:    s,
+		c:        c,
+		commands: cmd,
 	}
 }
 
-func (c *MessageService) CreateMessage(ctx context.Context, m *model.Message) (*model.Message, error) {
+// CreateMessage stores a message in its chat along with the users it mentions, who
+// are notified. Messages starting with a registered slash command are not stored;
+// the command runs instead and its replies are posted as system messages. Only the
+// replies of commands are system messages. A message without an expiry of its own
+// expires after the message TTL of its chat, if it has one.
+func (c *MessageService) CreateMessage(ctx context.Context, m *model.Message) (*model.Posted, error) {
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
 	err := c.c.ChatExist(ctx, *m.ChatID)
 	if err != nil {
 		return nil, err
 	}
-	return c.store.InsertMessage(ctx, m)
+
+	if inv, ok := commands.Parse(pointer.GetString(m.Text)); ok {
+		if h, ok := c.commands.Lookup(inv.Name); ok {
+			inv.ChatID = *m.ChatID
+			inv.Author = pointer.GetString(m.Author)
+			return c.runCommand(ctx, h, inv)
+		}
+	}
+
+	m.Mentions = ParseMentions(pointer.GetString(m.Text))
+	m.System = pointer.ToBool(false)
+	m.EditedAt = nil
+
+	created, err := c.store.InsertMessage(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Posted{Message: created}, nil
+}
+
+// UpdateMessage replaces the text of a message. Its mentions are parsed again, so
+// users mentioned in the new text are notified and those no longer mentioned lose
+// their notification.
+func (c *MessageService) UpdateMessage(ctx context.Context, chatID, id, text string) (*model.Message, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyText
+	}
+
+	updated, err := c.store.UpdateMessage(ctx, &model.Message{
+		ID:       pointer.ToString(id),
+		ChatID:   pointer.ToString(chatID),
+		Text:     pointer.ToString(text),
+		Mentions: ParseMentions(text),
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	return updated, err
+}
+
+// DeleteMessage deletes a message, retracting the notifications it caused. The
+// messages of a chat under legal hold cannot be deleted.
+func (c *MessageService) DeleteMessage(ctx context.Context, chatID, id string) error {
+	err := c.c.CheckLegalHold(ctx, chatID)
+	if errors.Is(err, chats.ErrChatNotFound) {
+		return ErrMessageNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = c.store.DeleteMessage(ctx, chatID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// CreateMessages stores a batch of messages in a chat, typically history migrated
+// from another system. Slash commands are not run. Invalid messages are reported in
+// the results and skipped, unless atomic is set, in which case a single invalid
+// message rejects the whole batch with ErrBatchRejected.
+func (c *MessageService) CreateMessages(ctx context.Context, chatID string, ms []model.Message, atomic bool) (*model.Batch, error) {
+	switch {
+	case len(ms) == 0:
+		return nil, ErrEmptyBatch
+	case len(ms) > MaxBatchSize:
+		return nil, ErrBatchTooLarge
+	}
+
+	if err := c.c.ChatExist(ctx, chatID); err != nil {
+		return nil, err
+	}
+
+	batch := &model.Batch{
+		Results: make([]model.BatchResult, len(ms)),
+	}
+
+	valid := make([]model.Message, 0, len(ms))
+	positions := make([]int, 0, len(ms))
+	for i, m := range ms {
+		batch.Results[i].Index = i
+
+		if err := validateImported(&m); err != nil {
+			batch.Results[i].Error = pointer.ToString(err.Error())
+			batch.Failed++
+			continue
+		}
+
+		m.ID = nil
+		valid = append(valid, m)
+		positions = append(positions, i)
+	}
+
+	if batch.Failed > 0 && atomic {
+		return batch, ErrBatchRejected
+	}
+	if len(valid) == 0 {
+		return batch, nil
+	}
+
+	created, err := c.store.InsertMessages(ctx, chatID, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range created {
+		batch.Results[positions[i]].ID = m.ID
+		batch.Results[positions[i]].Seq = m.Seq
+	}
+	batch.Inserted = len(created)
+
+	return batch, nil
+}
+
+func validateImported(m *model.Message) error {
+	switch {
+	case strings.TrimSpace(pointer.GetString(m.Text)) == "":
+		return errors.Error("text is required")
+	case utf8.RuneCountInString(pointer.GetString(m.Author)) > maxAuthorLength:
+		return errors.Error("author is longer than 100 characters")
+	}
+	return nil
+}
+
+// ExportMessages streams the messages of a chat created in [from, to), oldest first,
+// to fn in batches. Either bound may be nil. The chat is not checked for existence.
+func (c *MessageService) ExportMessages(ctx context.Context, chatID string, from, to *time.Time, fn func([]model.Message) error) error {
+	if from != nil && to != nil && !from.Before(*to) {
+		return ErrInvalidRange
+	}
+
+	return c.store.StreamMessages(ctx, chatID, from, to, fn)
 }
 
 func (c *MessageService) GetMessagesByChat(ctx context.Context, id string, limit int64) ([]model.Message, error) {
 	return c.store.GetMessagesByChat(ctx, id, limit)
 }
+
+// GetMessagesByChats returns up to limit of the newest messages of each chat in ids,
+// below beforeSeq when it is set.
+func (c *MessageService) GetMessagesByChats(ctx context.Context, ids []string, limit int64, beforeSeq string) ([]model.Message, error) {
+	return c.store.GetMessagesByChats(ctx, ids, limit, beforeSeq)
+}
+
+// ListMessages pages through the messages of a chat by seq. With afterSeq set it
+// returns the messages following it, oldest first, so clients can fetch what they
+// missed; otherwise the newest messages, below beforeSeq when it is set.
+func (c *MessageService) ListMessages(ctx context.Context, chatID string, afterSeq, beforeSeq *int64, limit int64) ([]model.Message, error) {
+	if afterSeq != nil && beforeSeq != nil && *afterSeq >= *beforeSeq {
+		return nil, ErrInvalidSeqRange
+	}
+
+	if err := c.c.ChatExist(ctx, chatID); err != nil {
+		return nil, err
+	}
+
+	return c.store.ListMessages(ctx, chatID, afterSeq, beforeSeq, limit)
+}
+
+func (c *MessageService) runCommand(ctx context.Context, h commands.Handler, inv commands.Invocation) (*model.Posted, error) {
+	replies, err := h.Handle(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	posted := &model.Posted{Command: inv.Name}
+	for _, r := range replies {
+		if strings.TrimSpace(r.Text) == "" {
+			continue
+		}
+
+		created, err := c.store.InsertMessage(ctx, &model.Message{
+			ChatID: pointer.ToString(inv.ChatID),
+			Text:   pointer.ToString(r.Text),
+			System: pointer.ToBool(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		posted.Replies = append(posted.Replies, *created)
+	}
+
+	return posted, nil
+}
@@ -3,16 +3,20 @@ package messages_test
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/mocks"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestMessages_CreateMessage_Success(t *testing.T) {
@@ -38,6 +42,22 @@ func TestMessages_CreateMessage_Success(t *testing.T) {
 				CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
 			},
 		},
+		{
+			name: "with expiry",
+			args: args{
+				message: &model.Message{
+					ChatID:    pointer.ToString("1"),
+					Text:      pointer.ToString("testMessage"),
+					ExpiresAt: pointer.ToTime(time.Now().Add(time.Hour)),
+				},
+			},
+			wantMessage: &model.Message{
+				ChatID:    pointer.ToString("1"),
+				Text:      pointer.ToString("testMessage"),
+				CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				ExpiresAt: pointer.ToTime(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,8 +66,9 @@ func TestMessages_CreateMessage_Success(t *testing.T) {
 
 			s := mocks.NewMockStore(ctrl)
 			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
 
-			m := messages.New(s, c)
+			m := messages.New(s, c, cmd)
 			require.NotNil(t, m)
 
 			ctx := context.Background()
@@ -56,13 +77,40 @@ func TestMessages_CreateMessage_Success(t *testing.T) {
 
 			c.EXPECT().ChatExist(gomock.Any(), *tt.args.message.ChatID).Return(nil).Times(1)
 
-			message, err := m.CreateMessage(ctx, tt.args.message)
+			posted, err := m.CreateMessage(ctx, tt.args.message)
 			assert.NoError(t, err)
-			assert.Equal(t, tt.wantMessage, message)
+			assert.Equal(t, &model.Posted{Message: tt.wantMessage}, posted)
 		})
 	}
 }
 
+func TestMessages_CreateMessage_System(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	cmd := mocks.NewMockCommands(ctrl)
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+	s.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, m *model.Message) (*model.Message, error) {
+			return m, nil
+		}).Times(1)
+
+	m := messages.New(s, c, cmd)
+
+	// Only the replies of commands are system messages.
+	posted, err := m.CreateMessage(context.Background(), &model.Message{
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("deploying"),
+		Author: pointer.ToString("bot"),
+		System: pointer.ToBool(true),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pointer.ToBool(false), posted.Message.System)
+}
+
 func TestMessages_CreateMessage_Error(t *testing.T) {
 	type args struct {
 		message *model.Message
@@ -90,8 +138,9 @@ func TestMessages_CreateMessage_Error(t *testing.T) {
 
 			s := mocks.NewMockStore(ctrl)
 			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
 
-			m := messages.New(s, c)
+			m := messages.New(s, c, cmd)
 
 			require.NotNil(t, m)
 
@@ -107,3 +156,569 @@ func TestMessages_CreateMessage_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestMessages_CreateMessage_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := messages.New(mocks.NewMockStore(ctrl), mocks.NewMockChatService(ctrl), mocks.NewMockCommands(ctrl))
+
+	message, err := m.CreateMessage(context.Background(), &model.Message{
+		ChatID:    pointer.ToString("1"),
+		Text:      pointer.ToString("testMessage"),
+		ExpiresAt: pointer.ToTime(time.Now().Add(-time.Second)),
+	})
+	assert.Equal(t, messages.ErrInvalidExpiry, err)
+	assert.Nil(t, message)
+}
+
+func TestMessages_CreateMessage_Command(t *testing.T) {
+	type args struct {
+		message *model.Message
+	}
+	tests := []struct {
+		name        string
+		args        args
+		replies     []commands.Reply
+		wantMessage *model.Message
+		wantPosted  *model.Posted
+	}{
+		{
+			name: "replies are stored as system messages",
+			args: args{
+				message: &model.Message{
+					ChatID: pointer.ToString("1"),
+					Text:   pointer.ToString("/poll Lunch? | Pizza | Sushi"),
+					Author: pointer.ToString("alice"),
+				},
+			},
+			replies: []commands.Reply{{Text: "Poll: Lunch?"}},
+			wantMessage: &model.Message{
+				ID:     pointer.ToString("2"),
+				ChatID: pointer.ToString("1"),
+				Text:   pointer.ToString("Poll: Lunch?"),
+				System: pointer.ToBool(true),
+			},
+			wantPosted: &model.Posted{
+				Command: "poll",
+				Replies: []model.Message{{
+					ID:     pointer.ToString("2"),
+					ChatID: pointer.ToString("1"),
+					Text:   pointer.ToString("Poll: Lunch?"),
+					System: pointer.ToBool(true),
+				}},
+			},
+		},
+		{
+			name: "no reply",
+			args: args{
+				message: &model.Message{
+					ChatID: pointer.ToString("1"),
+					Text:   pointer.ToString("/silent"),
+				},
+			},
+			wantPosted: &model.Posted{Command: "silent"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
+
+			m := messages.New(s, c, cmd)
+
+			ctx := context.Background()
+
+			inv, ok := commands.Parse(*tt.args.message.Text)
+			require.True(t, ok)
+
+			c.EXPECT().ChatExist(gomock.Any(), *tt.args.message.ChatID).Return(nil).Times(1)
+
+			cmd.EXPECT().Lookup(inv.Name).Return(commands.HandlerFunc(func(ctx context.Context, got commands.Invocation) ([]commands.Reply, error) {
+				assert.Equal(t, *tt.args.message.ChatID, got.ChatID)
+				assert.Equal(t, pointer.GetString(tt.args.message.Author), got.Author)
+				return tt.replies, nil
+			}), true).Times(1)
+
+			for _, r := range tt.replies {
+				s.EXPECT().
+					InsertMessage(gomock.Any(), &model.Message{
+						ChatID: tt.args.message.ChatID,
+						Text:   pointer.ToString(r.Text),
+						System: pointer.ToBool(true),
+					}).
+					Return(tt.wantMessage, nil).Times(1)
+			}
+
+			posted, err := m.CreateMessage(ctx, tt.args.message)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPosted, posted)
+		})
+	}
+}
+
+func TestMessages_CreateMessage_UnknownCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	cmd := mocks.NewMockCommands(ctrl)
+
+	m := messages.New(s, c, cmd)
+
+	msg := &model.Message{
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("/shrug"),
+	}
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+	cmd.EXPECT().Lookup("shrug").Return(nil, false).Times(1)
+	s.EXPECT().InsertMessage(gomock.Any(), msg).Return(msg, nil).Times(1)
+
+	posted, err := m.CreateMessage(context.Background(), msg)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Posted{Message: msg}, posted)
+}
+
+func TestMessages_CreateMessages_Success(t *testing.T) {
+	tests := []struct {
+		name      string
+		atomic    bool
+		batch     []model.Message
+		wantStore []model.Message
+		wantBatch *model.Batch
+	}{
+		{
+			name: "all valid",
+			batch: []model.Message{
+				{Text: pointer.ToString("first"), CreatedAt: pointer.ToTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))},
+				{Text: pointer.ToString("second"), Author: pointer.ToString("bob")},
+			},
+			wantStore: []model.Message{
+				{Text: pointer.ToString("first"), CreatedAt: pointer.ToTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))},
+				{Text: pointer.ToString("second"), Author: pointer.ToString("bob")},
+			},
+			wantBatch: &model.Batch{
+				Inserted: 2,
+				Results: []model.BatchResult{
+					{Index: 0, ID: pointer.ToString("10")},
+					{Index: 1, ID: pointer.ToString("11")},
+				},
+			},
+		},
+		{
+			name: "invalid messages are skipped",
+			batch: []model.Message{
+				{Text: pointer.ToString(" ")},
+				{Text: pointer.ToString("kept"), ID: pointer.ToString("999")},
+			},
+			wantStore: []model.Message{
+				{Text: pointer.ToString("kept")},
+			},
+			wantBatch: &model.Batch{
+				Inserted: 1,
+				Failed:   1,
+				Results: []model.BatchResult{
+					{Index: 0, Error: pointer.ToString("text is required")},
+					{Index: 1, ID: pointer.ToString("10")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
+
+			m := messages.New(s, c, cmd)
+
+			c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+			s.EXPECT().
+				InsertMessages(gomock.Any(), "1", tt.wantStore).
+				DoAndReturn(func(_ context.Context, _ string, ms []model.Message) ([]model.Message, error) {
+					for i := range ms {
+						ms[i].ID = pointer.ToString(strconv.Itoa(10 + i))
+					}
+					return ms, nil
+				}).Times(1)
+
+			batch, err := m.CreateMessages(context.Background(), "1", tt.batch, tt.atomic)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBatch, batch)
+		})
+	}
+}
+
+func TestMessages_CreateMessages_Error(t *testing.T) {
+	tooLarge := make([]model.Message, messages.MaxBatchSize+1)
+
+	tests := []struct {
+		name      string
+		batch     []model.Message
+		atomic    bool
+		setup     func(s *mocks.MockStore, c *mocks.MockChatService)
+		wantErr   error
+		wantBatch bool
+	}{
+		{
+			name:    "empty",
+			wantErr: messages.ErrEmptyBatch,
+		},
+		{
+			name:    "too large",
+			batch:   tooLarge,
+			wantErr: messages.ErrBatchTooLarge,
+		},
+		{
+			name:  "chat not found",
+			batch: []model.Message{{Text: pointer.ToString("hi")}},
+			setup: func(s *mocks.MockStore, c *mocks.MockChatService) {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(errors.New("chat not found")).Times(1)
+			},
+			wantErr: errors.New("chat not found"),
+		},
+		{
+			name:   "atomic batch with an invalid message",
+			batch:  []model.Message{{Text: pointer.ToString("hi")}, {Text: pointer.ToString("")}},
+			atomic: true,
+			setup: func(s *mocks.MockStore, c *mocks.MockChatService) {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+				s.EXPECT().InsertMessages(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantErr:   messages.ErrBatchRejected,
+			wantBatch: true,
+		},
+		{
+			name:  "store fails",
+			batch: []model.Message{{Text: pointer.ToString("hi")}},
+			setup: func(s *mocks.MockStore, c *mocks.MockChatService) {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+				s.EXPECT().InsertMessages(gomock.Any(), "1", gomock.Any()).Return(nil, errors.New("test fail")).Times(1)
+			},
+			wantErr: errors.New("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
+
+			m := messages.New(s, c, cmd)
+
+			if tt.setup != nil {
+				tt.setup(s, c)
+			}
+
+			batch, err := m.CreateMessages(context.Background(), "1", tt.batch, tt.atomic)
+			assert.EqualError(t, err, tt.wantErr.Error())
+			assert.Equal(t, tt.wantBatch, batch != nil)
+		})
+	}
+}
+
+func TestMessages_ExportMessages_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	cmd := mocks.NewMockCommands(ctrl)
+
+	m := messages.New(s, c, cmd)
+
+	from := pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	batch := []model.Message{{ID: pointer.ToString("1"), Text: pointer.ToString("hi")}}
+
+	s.EXPECT().StreamMessages(gomock.Any(), "1", from, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _, _ *time.Time, fn func([]model.Message) error) error {
+			return fn(batch)
+		}).Times(1)
+
+	var got []model.Message
+	err := m.ExportMessages(context.Background(), "1", from, nil, func(ms []model.Message) error {
+		got = append(got, ms...)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, batch, got)
+}
+
+func TestMessages_ExportMessages_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	c := mocks.NewMockChatService(ctrl)
+	cmd := mocks.NewMockCommands(ctrl)
+
+	m := messages.New(s, c, cmd)
+
+	from := pointer.ToTime(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))
+	to := pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	s.EXPECT().StreamMessages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := m.ExportMessages(context.Background(), "1", from, to, func([]model.Message) error { return nil })
+
+	assert.ErrorIs(t, err, messages.ErrInvalidRange)
+}
+
+func TestMessages_CreateMessage_Mentions(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantMentions model.Mentions
+	}{
+		{
+			name: "mentions",
+			text: "hi @alice and @Bob.Smith, ping (@carol_1)!",
+			wantMentions: model.Mentions{
+				{Username: "alice", Offset: 3, Length: 6},
+				{Username: "Bob.Smith", Offset: 14, Length: 10},
+				{Username: "carol_1", Offset: 32, Length: 8},
+			},
+		},
+		{
+			name: "offsets in characters",
+			text: "привет @дима",
+			wantMentions: model.Mentions{
+				{Username: "дима", Offset: 7, Length: 5},
+			},
+		},
+		{
+			name: "email addresses and lone signs",
+			text: "mail me at bob@example.com @ noon, @@alice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			c := mocks.NewMockChatService(ctrl)
+			cmd := mocks.NewMockCommands(ctrl)
+
+			c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+			s.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, m *model.Message) (*model.Message, error) {
+					return m, nil
+				}).Times(1)
+
+			m := messages.New(s, c, cmd)
+
+			posted, err := m.CreateMessage(context.Background(), &model.Message{
+				ChatID:   pointer.ToString("1"),
+				Text:     pointer.ToString(tt.text),
+				Mentions: model.Mentions{{Username: "forged"}},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMentions, posted.Message.Mentions)
+		})
+	}
+}
+
+func TestMessages_UpdateMessage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	want := &model.Message{ID: pointer.ToString("2"), ChatID: pointer.ToString("1"), Text: pointer.ToString("cc @bob")}
+
+	s.EXPECT().UpdateMessage(gomock.Any(), &model.Message{
+		ID:       pointer.ToString("2"),
+		ChatID:   pointer.ToString("1"),
+		Text:     pointer.ToString("cc @bob"),
+		Mentions: model.Mentions{{Username: "bob", Offset: 3, Length: 4}},
+	}).Return(want, nil).Times(1)
+
+	m := messages.New(s, mocks.NewMockChatService(ctrl), mocks.NewMockCommands(ctrl))
+
+	message, err := m.UpdateMessage(context.Background(), "1", "2", "cc @bob")
+	require.NoError(t, err)
+	assert.Equal(t, want, message)
+}
+
+func TestMessages_UpdateMessage_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:    "empty text",
+			text:    "  ",
+			wantErr: messages.ErrEmptyText,
+		},
+		{
+			name:     "not found",
+			text:     "hello",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  messages.ErrMessageNotFound,
+		},
+		{
+			name:     "fails",
+			text:     "hello",
+			storeErr: errors.New("test fail"),
+			wantErr:  errors.New("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.storeErr != nil {
+				s.EXPECT().UpdateMessage(gomock.Any(), gomock.Any()).Return(nil, tt.storeErr).Times(1)
+			}
+
+			m := messages.New(s, mocks.NewMockChatService(ctrl), mocks.NewMockCommands(ctrl))
+
+			message, err := m.UpdateMessage(context.Background(), "1", "2", tt.text)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, message)
+		})
+	}
+}
+
+func TestMessages_DeleteMessage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().DeleteMessage(gomock.Any(), "1", "2").Return(nil).Times(1)
+
+	c := mocks.NewMockChatService(ctrl)
+	c.EXPECT().CheckLegalHold(gomock.Any(), "1").Return(nil).Times(1)
+
+	m := messages.New(s, c, mocks.NewMockCommands(ctrl))
+
+	assert.NoError(t, m.DeleteMessage(context.Background(), "1", "2"))
+}
+
+func TestMessages_DeleteMessage_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		holdErr  error
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:     "not found",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  messages.ErrMessageNotFound,
+		},
+		{
+			name:     "fails",
+			storeErr: errors.New("test fail"),
+			wantErr:  errors.New("test fail"),
+		},
+		{
+			name:    "chat not found",
+			holdErr: chats.ErrChatNotFound,
+			wantErr: messages.ErrMessageNotFound,
+		},
+		{
+			name:    "legal hold",
+			holdErr: chats.ErrLegalHold,
+			wantErr: chats.ErrLegalHold,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChatService(ctrl)
+			c.EXPECT().CheckLegalHold(gomock.Any(), "1").Return(tt.holdErr).Times(1)
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.holdErr == nil {
+				s.EXPECT().DeleteMessage(gomock.Any(), "1", "2").Return(tt.storeErr).Times(1)
+			}
+
+			m := messages.New(s, c, mocks.NewMockCommands(ctrl))
+
+			assert.Equal(t, tt.wantErr, m.DeleteMessage(context.Background(), "1", "2"))
+		})
+	}
+}
+
+func TestMessages_ListMessages_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := []model.Message{
+		{ID: pointer.ToString("12"), Seq: pointer.ToInt64(4)},
+		{ID: pointer.ToString("11"), Seq: pointer.ToInt64(5)},
+	}
+
+	c := mocks.NewMockChatService(ctrl)
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListMessages(gomock.Any(), "1", pointer.ToInt64(3), nil, int64(20)).Return(want, nil).Times(1)
+
+	m := messages.New(s, c, mocks.NewMockCommands(ctrl))
+
+	ms, err := m.ListMessages(context.Background(), "1", pointer.ToInt64(3), nil, 20)
+	require.NoError(t, err)
+	assert.Equal(t, want, ms)
+}
+
+func TestMessages_ListMessages_Error(t *testing.T) {
+	tests := []struct {
+		name      string
+		afterSeq  *int64
+		beforeSeq *int64
+		setup     func(c *mocks.MockChatService)
+		wantErr   error
+	}{
+		{
+			name:      "empty range",
+			afterSeq:  pointer.ToInt64(5),
+			beforeSeq: pointer.ToInt64(5),
+			wantErr:   messages.ErrInvalidSeqRange,
+		},
+		{
+			name: "chat not found",
+			setup: func(c *mocks.MockChatService) {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(errors.New("chat_not_found: chat not found")).Times(1)
+			},
+			wantErr: errors.New("chat_not_found: chat not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChatService(ctrl)
+			if tt.setup != nil {
+				tt.setup(c)
+			}
+
+			m := messages.New(mocks.NewMockStore(ctrl), c, mocks.NewMockCommands(ctrl))
+
+			ms, err := m.ListMessages(context.Background(), "1", tt.afterSeq, tt.beforeSeq, 20)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, ms)
+		})
+	}
+}
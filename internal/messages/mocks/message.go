@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/Polilo-User/test-task-hitalent/internal/messages (interfaces: ChatService,Store)
+// Source: github.com/Polilo-User/test-task-hitalent/internal/messages (interfaces: Attachments,ChatService,Commands,Previews,Store)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -7,11 +7,53 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
-	model "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	commands "github.com/Polilo-User/test-task-hitalent/internal/commands"
+	model0 "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model1 "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockAttachments is a mock of Attachments interface.
+type MockAttachments struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentsMockRecorder
+}
+
+// MockAttachmentsMockRecorder is the mock recorder for MockAttachments.
+type MockAttachmentsMockRecorder struct {
+	mock *MockAttachments
+}
+
+// NewMockAttachments creates a new mock instance.
+func NewMockAttachments(ctrl *gomock.Controller) *MockAttachments {
+	mock := &MockAttachments{ctrl: ctrl}
+	mock.recorder = &MockAttachmentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachments) EXPECT() *MockAttachmentsMockRecorder {
+	return m.recorder
+}
+
+// AttachmentsByMessages mocks base method.
+func (m *MockAttachments) AttachmentsByMessages(arg0 context.Context, arg1 []string) (map[string][]model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachmentsByMessages", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachmentsByMessages indicates an expected call of AttachmentsByMessages.
+func (mr *MockAttachmentsMockRecorder) AttachmentsByMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachmentsByMessages", reflect.TypeOf((*MockAttachments)(nil).AttachmentsByMessages), arg0, arg1)
+}
+
 // MockChatService is a mock of ChatService interface.
 type MockChatService struct {
 	ctrl     *gomock.Controller
@@ -49,6 +91,96 @@ func (mr *MockChatServiceMockRecorder) ChatExist(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatExist", reflect.TypeOf((*MockChatService)(nil).ChatExist), arg0, arg1)
 }
 
+// CheckLegalHold mocks base method.
+func (m *MockChatService) CheckLegalHold(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLegalHold", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckLegalHold indicates an expected call of CheckLegalHold.
+func (mr *MockChatServiceMockRecorder) CheckLegalHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLegalHold", reflect.TypeOf((*MockChatService)(nil).CheckLegalHold), arg0, arg1)
+}
+
+// MockCommands is a mock of Commands interface.
+type MockCommands struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommandsMockRecorder
+}
+
+// MockCommandsMockRecorder is the mock recorder for MockCommands.
+type MockCommandsMockRecorder struct {
+	mock *MockCommands
+}
+
+// NewMockCommands creates a new mock instance.
+func NewMockCommands(ctrl *gomock.Controller) *MockCommands {
+	mock := &MockCommands{ctrl: ctrl}
+	mock.recorder = &MockCommandsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommands) EXPECT() *MockCommandsMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockCommands) Lookup(arg0 string) (commands.Handler, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", arg0)
+	ret0, _ := ret[0].(commands.Handler)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockCommandsMockRecorder) Lookup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockCommands)(nil).Lookup), arg0)
+}
+
+// MockPreviews is a mock of Previews interface.
+type MockPreviews struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreviewsMockRecorder
+}
+
+// MockPreviewsMockRecorder is the mock recorder for MockPreviews.
+type MockPreviewsMockRecorder struct {
+	mock *MockPreviews
+}
+
+// NewMockPreviews creates a new mock instance.
+func NewMockPreviews(ctrl *gomock.Controller) *MockPreviews {
+	mock := &MockPreviews{ctrl: ctrl}
+	mock.recorder = &MockPreviewsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreviews) EXPECT() *MockPreviewsMockRecorder {
+	return m.recorder
+}
+
+// PreviewsByMessages mocks base method.
+func (m *MockPreviews) PreviewsByMessages(arg0 context.Context, arg1 []string) (map[string][]model1.Preview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewsByMessages", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]model1.Preview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewsByMessages indicates an expected call of PreviewsByMessages.
+func (mr *MockPreviewsMockRecorder) PreviewsByMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewsByMessages", reflect.TypeOf((*MockPreviews)(nil).PreviewsByMessages), arg0, arg1)
+}
+
 // MockStore is a mock of Store interface.
 type MockStore struct {
 	ctrl     *gomock.Controller
@@ -72,11 +204,40 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// DeleteExpiredMessages mocks base method.
+func (m *MockStore) DeleteExpiredMessages(arg0 context.Context, arg1 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredMessages", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredMessages indicates an expected call of DeleteExpiredMessages.
+func (mr *MockStoreMockRecorder) DeleteExpiredMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredMessages", reflect.TypeOf((*MockStore)(nil).DeleteExpiredMessages), arg0, arg1)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockStore) DeleteMessage(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockStoreMockRecorder) DeleteMessage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockStore)(nil).DeleteMessage), arg0, arg1, arg2)
+}
+
 // GetMessagesByChat mocks base method.
-func (m *MockStore) GetMessagesByChat(arg0 context.Context, arg1 string, arg2 int64) ([]model.Message, error) {
+func (m *MockStore) GetMessagesByChat(arg0 context.Context, arg1 string, arg2 int64) ([]model0.Message, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetMessagesByChat", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]model.Message)
+	ret0, _ := ret[0].([]model0.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -87,11 +248,26 @@ func (mr *MockStoreMockRecorder) GetMessagesByChat(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByChat", reflect.TypeOf((*MockStore)(nil).GetMessagesByChat), arg0, arg1, arg2)
 }
 
+// GetMessagesByChats mocks base method.
+func (m *MockStore) GetMessagesByChats(arg0 context.Context, arg1 []string, arg2 int64, arg3 string) ([]model0.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByChats", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model0.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByChats indicates an expected call of GetMessagesByChats.
+func (mr *MockStoreMockRecorder) GetMessagesByChats(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByChats", reflect.TypeOf((*MockStore)(nil).GetMessagesByChats), arg0, arg1, arg2, arg3)
+}
+
 // InsertMessage mocks base method.
-func (m *MockStore) InsertMessage(arg0 context.Context, arg1 *model.Message) (*model.Message, error) {
+func (m *MockStore) InsertMessage(arg0 context.Context, arg1 *model0.Message) (*model0.Message, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InsertMessage", arg0, arg1)
-	ret0, _ := ret[0].(*model.Message)
+	ret0, _ := ret[0].(*model0.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -101,3 +277,62 @@ func (mr *MockStoreMockRecorder) InsertMessage(arg0, arg1 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMessage", reflect.TypeOf((*MockStore)(nil).InsertMessage), arg0, arg1)
 }
+
+// InsertMessages mocks base method.
+func (m *MockStore) InsertMessages(arg0 context.Context, arg1 string, arg2 []model0.Message) ([]model0.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMessages", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model0.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMessages indicates an expected call of InsertMessages.
+func (mr *MockStoreMockRecorder) InsertMessages(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMessages", reflect.TypeOf((*MockStore)(nil).InsertMessages), arg0, arg1, arg2)
+}
+
+// ListMessages mocks base method.
+func (m *MockStore) ListMessages(arg0 context.Context, arg1 string, arg2, arg3 *int64, arg4 int64) ([]model0.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]model0.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockStoreMockRecorder) ListMessages(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockStore)(nil).ListMessages), arg0, arg1, arg2, arg3, arg4)
+}
+
+// StreamMessages mocks base method.
+func (m *MockStore) StreamMessages(arg0 context.Context, arg1 string, arg2, arg3 *time.Time, arg4 func([]model0.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMessages", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMessages indicates an expected call of StreamMessages.
+func (mr *MockStoreMockRecorder) StreamMessages(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMessages", reflect.TypeOf((*MockStore)(nil).StreamMessages), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockStore) UpdateMessage(arg0 context.Context, arg1 *model0.Message) (*model0.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", arg0, arg1)
+	ret0, _ := ret[0].(*model0.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockStoreMockRecorder) UpdateMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockStore)(nil).UpdateMessage), arg0, arg1)
+}
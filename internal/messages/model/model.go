@@ -1,10 +1,106 @@
 package model
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	atmodel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	unfurlmodel "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+)
+
+// Message is a message of a chat. Seq numbers the messages of a chat densely in
+// the order they were stored, so that clients can tell when they miss some; it is
+// assigned by the database. A message is no longer returned once it expires, and
+// is deleted soon after.
 type Message struct {
 	ID        *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
 	Text      *string    `json:"text" db:"text"`
+	Author    *string    `json:"author" db:"author"`
+	System    *bool      `json:"system" db:"is_system" gorm:"column:is_system;default:false"`
 	CreatedAt *time.Time `json:"created_at" db:"created_at"`
 	ChatID    *string    `json:"chat_id" db:"chat_id"`
+	Seq       *int64     `json:"seq,omitempty" db:"seq" gorm:"->;default:null"`
+	Mentions  Mentions   `json:"mentions,omitempty" db:"mentions"`
+	EditedAt  *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+
+	Attachments []atmodel.Attachment  `json:"attachments,omitempty" gorm:"-"`
+	Previews    []unfurlmodel.Preview `json:"previews,omitempty" gorm:"-"`
+}
+
+// Mention is a user mentioned in the text of a message as @username. Offset and
+// Length locate the mention, @ included, in the text, counted in characters.
+type Mention struct {
+	Username string `json:"username"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
+
+// Mentions are stored along with their message as JSON.
+type Mentions []Mention
+
+func (m Mentions) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *Mentions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return fmt.Errorf("cannot scan %T into mentions", src)
+	}
+}
+
+// Posted is what posting a message produced. Message is the message stored.
+// A message starting with a slash command is not stored: the command runs
+// instead, Command names it and Replies holds the system messages it posted,
+// which may be none.
+type Posted struct {
+	Message *Message
+	Command string
+	Replies []Message
+}
+
+// First returns the message stored, or the first reply of the command run
+// instead. It is nil when the command did not reply.
+func (p *Posted) First() *Message {
+	if p.Message != nil {
+		return p.Message
+	}
+	if len(p.Replies) > 0 {
+		return &p.Replies[0]
+	}
+	return nil
+}
+
+// BatchResult is the outcome of one message of a batch, identified by its
+// position in the batch. ID and Seq are set once the message is stored, Error
+// when it was invalid.
+type BatchResult struct {
+	Index int     `json:"index"`
+	ID    *string `json:"id"`
+	Seq   *int64  `json:"seq,omitempty"`
+	Error *string `json:"error"`
+}
+
+// Batch reports how a batch of messages was ingested.
+type Batch struct {
+	Inserted int           `json:"inserted"`
+	Failed   int           `json:"failed"`
+	Results  []BatchResult `json:"results"`
 }
@@ -0,0 +1,29 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	unfurlmodel "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+
+	"github.com/AlekSi/pointer"
+)
+
+type Previews interface {
+	PreviewsByMessages(ctx context.Context, messageIDs []string) (map[string][]unfurlmodel.Preview, error)
+}
+
+// WithPreviews wraps a store so that the messages of chats read from it carry the
+// previews of the links they contain.
+func WithPreviews(s Store, p Previews) Store {
+	return enrich(s, func(ctx context.Context, messageIDs []string) (func(m *model.Message), error) {
+		byMessage, err := p.PreviewsByMessages(ctx, messageIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(m *model.Message) {
+			m.Previews = byMessage[pointer.GetString(m.ID)]
+		}, nil
+	})
+}
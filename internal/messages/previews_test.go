@@ -0,0 +1,53 @@
+package messages_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	unfurlmodel "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPreviews_GetMessagesByChats_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetMessagesByChats(gomock.Any(), []string{"1", "2"}, int64(20), "").Return([]model.Message{
+		{ID: pointer.ToString("10")},
+		{ID: pointer.ToString("11")},
+	}, nil).Times(1)
+
+	p := mocks.NewMockPreviews(ctrl)
+	p.EXPECT().PreviewsByMessages(gomock.Any(), []string{"10", "11"}).Return(map[string][]unfurlmodel.Preview{
+		"10": {{URL: pointer.ToString("https://example.com")}},
+	}, nil).Times(1)
+
+	ms, err := messages.WithPreviews(s, p).GetMessagesByChats(context.Background(), []string{"1", "2"}, 20, "")
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	assert.Equal(t, []unfurlmodel.Preview{{URL: pointer.ToString("https://example.com")}}, ms[0].Previews)
+	assert.Empty(t, ms[1].Previews)
+}
+
+func TestWithPreviews_GetMessagesByChats_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().GetMessagesByChats(gomock.Any(), []string{"1"}, int64(20), "").
+		Return([]model.Message{{ID: pointer.ToString("10")}}, nil).Times(1)
+
+	p := mocks.NewMockPreviews(ctrl)
+	p.EXPECT().PreviewsByMessages(gomock.Any(), []string{"10"}).Return(nil, errors.New("test fail")).Times(1)
+
+	_, err := messages.WithPreviews(s, p).GetMessagesByChats(context.Background(), []string{"1"}, 20, "")
+	assert.EqualError(t, err, "test fail")
+}
@@ -2,11 +2,37 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	notificationStore "github.com/Polilo-User/test-task-hitalent/internal/notifications/store"
+	outboxStore "github.com/Polilo-User/test-task-hitalent/internal/outbox/store"
+
+	"github.com/AlekSi/pointer"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/gorm"
 )
 
+const errNotPgx = errors.Error("bulk inserts require the pgx driver")
+
+// exportFetchSize is the number of rows fetched from the export cursor at a time.
+const exportFetchSize = 1000
+
+// messageColumns are the messages columns read into a model.Message.
+const messageColumns = "id, chat_id, seq, text, author, is_system, created_at, mentions, edited_at, expires_at"
+
+// notExpired filters out the messages that expired but have not been deleted yet.
+const notExpired = "(expires_at IS NULL OR expires_at > now())"
+
+// copyColumns are the messages columns written by InsertMessages.
+var copyColumns = []string{"id", "chat_id", "seq", "text", "author", "is_system", "created_at", "expires_at"}
+
 type Store struct {
 	db *gorm.DB
 }
@@ -18,18 +44,329 @@ func New(db *gorm.DB) *Store {
 }
 
 func (s *Store) InsertMessage(ctx context.Context, c *model.Message) (*model.Message, error) {
-	if err := s.db.Table("messages").Create(c).Error; err != nil {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return Insert(tx, c)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+// Insert stores a message along with the notifications of the users it mentions
+// and its message.created event. It must be called within a transaction.
+func Insert(tx *gorm.DB, c *model.Message) error {
+	if err := tx.Table("messages").Create(c).Error; err != nil {
+		return err
+	}
+
+	if len(c.Mentions) > 0 {
+		if err := notificationStore.SyncMentions(tx, c); err != nil {
+			return err
+		}
+	}
+
+	e, err := events.New(events.MessageCreated, *c.ChatID, c)
+	if err != nil {
+		return err
+	}
+
+	return outboxStore.Append(tx, e)
+}
+
+// UpdateMessage replaces the text and mentions of a message, syncing the
+// notifications of the users it mentions. It returns gorm.ErrRecordNotFound when
+// the chat has no such message.
+func (s *Store) UpdateMessage(ctx context.Context, m *model.Message) (*model.Message, error) {
+	var updated model.Message
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Raw(
+			"UPDATE messages SET text = ?, mentions = ?, edited_at = now() WHERE id = ? AND chat_id = ? AND "+notExpired+" RETURNING "+messageColumns,
+			m.Text, m.Mentions, m.ID, m.ChatID,
+		).Scan(&updated)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := notificationStore.SyncMentions(tx, &updated); err != nil {
+			return err
+		}
+
+		e, err := events.New(events.MessageUpdated, *updated.ChatID, &updated)
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteMessage deletes a message of a chat; its notifications go with it. It
+// returns gorm.ErrRecordNotFound when the chat has no such message. The database
+// refuses to delete the messages of a chat under legal hold.
+func (s *Store) DeleteMessage(ctx context.Context, chatID, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("DELETE FROM messages WHERE id = ? AND chat_id = ?", id, chatID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		e, err := events.New(events.MessageDeleted, chatID, map[string]string{
+			"id":      id,
+			"chat_id": chatID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return outboxStore.Append(tx, e)
+	})
+}
+
+// InsertMessages stores messages in a chat with a single COPY. Their ids are taken
+// from the messages sequence and their seqs reserved from the chat beforehand so
+// they can be returned in order, and one messages.imported event carries the
+// whole batch. It returns gorm.ErrRecordNotFound when the chat does not exist.
+func (s *Store) InsertMessages(ctx context.Context, chatID string, ms []model.Message) ([]model.Message, error) {
+	// COPY has to run on the connection holding the transaction, so pin one.
+	err := s.db.WithContext(ctx).Connection(func(db *gorm.DB) error {
+		conn, ok := db.Statement.ConnPool.(*sql.Conn)
+		if !ok {
+			return errNotPgx
+		}
+
+		return db.Transaction(func(tx *gorm.DB) error {
+			chat, err := strconv.ParseInt(chatID, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			var ids []int64
+			err = tx.Raw("SELECT nextval(pg_get_serial_sequence('messages', 'id')) FROM generate_series(1, ?)", len(ms)).
+				Scan(&ids).Error
+			if err != nil {
+				return err
+			}
+
+			// Reserving the seqs locks the chat until the batch is committed, like
+			// the inserts of single messages do.
+			var lastSeq int64
+			res := tx.Raw("UPDATE chats SET last_seq = last_seq + ? WHERE id = ? RETURNING last_seq", len(ms), chat).
+				Scan(&lastSeq)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			firstSeq := lastSeq - int64(len(ms)) + 1
+
+			now := time.Now().UTC()
+			rows := make([][]interface{}, len(ms))
+			for i := range ms {
+				ms[i].ID = pointer.ToString(strconv.FormatInt(ids[i], 10))
+				ms[i].ChatID = pointer.ToString(chatID)
+				ms[i].Seq = pointer.ToInt64(firstSeq + int64(i))
+				if ms[i].System == nil {
+					ms[i].System = pointer.ToBool(false)
+				}
+				if ms[i].CreatedAt == nil {
+					ms[i].CreatedAt = pointer.ToTime(now)
+				}
+
+				rows[i] = []interface{}{ids[i], chat, *ms[i].Seq, *ms[i].Text, ms[i].Author, *ms[i].System, *ms[i].CreatedAt, ms[i].ExpiresAt}
+			}
+
+			err = conn.Raw(func(driverConn interface{}) error {
+				c, ok := driverConn.(*stdlib.Conn)
+				if !ok {
+					return errNotPgx
+				}
+
+				_, err := c.Conn().CopyFrom(ctx, pgx.Identifier{"messages"}, copyColumns, pgx.CopyFromRows(rows))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			e, err := events.New(events.MessagesImported, chatID, map[string]interface{}{
+				"count":     len(ms),
+				"first_id":  *ms[0].ID,
+				"last_id":   *ms[len(ms)-1].ID,
+				"first_seq": firstSeq,
+				"last_seq":  lastSeq,
+				"messages":  ms,
+			})
+			if err != nil {
+				return err
+			}
+
+			return outboxStore.Append(tx, e)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// StreamMessages passes every message of a chat created in [from, to) to fn, oldest
+// first, in batches read from a server-side cursor so the history is never held in
+// memory at once. Either bound may be nil.
+func (s *Store) StreamMessages(ctx context.Context, chatID string, from, to *time.Time, fn func([]model.Message) error) error {
+	query := "SELECT " + messageColumns + " FROM messages WHERE chat_id = ? AND " + notExpired
+	args := []interface{}{chatID}
+	if from != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		query += " AND created_at < ?"
+		args = append(args, *to)
+	}
+	query += " ORDER BY created_at, id"
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DECLARE messages_export NO SCROLL CURSOR FOR "+query, args...).Error; err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH %d FROM messages_export", exportFetchSize)
+		for {
+			var batch []model.Message
+			if err := tx.Raw(fetch).Scan(&batch).Error; err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}, &sql.TxOptions{ReadOnly: true})
+}
+
 func (s *Store) GetMessagesByChat(ctx context.Context, id string, limit int64) ([]model.Message, error) {
 	var c []model.Message
 
-	if err := s.db.Table("messages").Where("chat_id = ?", id).Limit(int(limit)).Find(&c).Error; err != nil {
+	if err := s.db.Table("messages").Where("chat_id = ?", id).Where(notExpired).Limit(int(limit)).Find(&c).Error; err != nil {
 		return nil, err
 	}
 
 	return c, nil
 }
+
+// GetMessagesByChats returns up to limit of the newest messages of every chat in ids
+// with a single query, ordered by chat and then from newest to oldest. When beforeSeq
+// is set only messages with a lower seq are considered.
+func (s *Store) GetMessagesByChats(ctx context.Context, ids []string, limit int64, beforeSeq string) ([]model.Message, error) {
+	var c []model.Message
+
+	ranked := s.db.Table("messages").
+		Select(messageColumns+", row_number() OVER (PARTITION BY chat_id ORDER BY seq DESC) AS rn").
+		Where("chat_id IN ?", ids).
+		Where(notExpired)
+	if beforeSeq != "" {
+		ranked = ranked.Where("seq < ?", beforeSeq)
+	}
+
+	err := s.db.WithContext(ctx).
+		Table("(?) AS m", ranked).
+		Select(messageColumns).
+		Where("rn <= ?", limit).
+		Order("chat_id, seq DESC").
+		Find(&c).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ListMessages returns up to limit messages of a chat by seq. With afterSeq set they
+// are the messages following it, oldest first, which is how clients catch up.
+// Otherwise they are the newest messages, before beforeSeq when it is set.
+func (s *Store) ListMessages(ctx context.Context, chatID string, afterSeq, beforeSeq *int64, limit int64) ([]model.Message, error) {
+	var ms []model.Message
+
+	q := s.db.WithContext(ctx).Table("messages").Select(messageColumns).Where("chat_id = ?", chatID).Where(notExpired)
+	if afterSeq != nil {
+		q = q.Where("seq > ?", *afterSeq)
+	}
+	if beforeSeq != nil {
+		q = q.Where("seq < ?", *beforeSeq)
+	}
+	if afterSeq != nil {
+		q = q.Order("seq")
+	} else {
+		q = q.Order("seq DESC")
+	}
+
+	if err := q.Limit(int(limit)).Find(&ms).Error; err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// DeleteExpiredMessages deletes up to limit of the messages past their expiry,
+// recording a message.deleted event for each, and returns how many it deleted.
+// Messages locked by other transactions are left for the next batch, so a sweep
+// never waits on writers, and batches are small so it holds its locks briefly.
+// The messages of chats under legal hold are kept until the hold is lifted.
+func (s *Store) DeleteExpiredMessages(ctx context.Context, limit int) (int, error) {
+	var deleted []model.Message
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`
+			DELETE FROM messages
+			WHERE id IN (
+				SELECT id FROM messages
+				WHERE expires_at <= now()
+					AND NOT EXISTS (SELECT 1 FROM chats c WHERE c.id = messages.chat_id AND c.legal_hold)
+				ORDER BY expires_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, chat_id`, limit).
+			Scan(&deleted).Error
+		if err != nil {
+			return err
+		}
+
+		for _, m := range deleted {
+			e, err := events.New(events.MessageDeleted, *m.ChatID, map[string]string{
+				"id":      *m.ID,
+				"chat_id": *m.ChatID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := outboxStore.Append(tx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(deleted), nil
+}
@@ -0,0 +1,61 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"go.uber.org/zap"
+)
+
+// sweepBatch bounds the number of expired messages deleted in one transaction.
+const sweepBatch = 500
+
+// Sweeper deletes expired messages in the background. Expired messages are hidden
+// from reads right away; the sweeper removes them for good, along with their
+// notifications and attachments, except in chats under legal hold. Any instance of
+// the service may sweep.
+type Sweeper struct {
+	store    Store
+	interval time.Duration
+}
+
+func NewSweeper(s Store, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		store:    s,
+		interval: interval,
+	}
+}
+
+// Listen deletes expired messages until the context is cancelled.
+func (s *Sweeper) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("expired message sweeper starting")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.Sweep(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to delete expired messages", zap.Error(err))
+		}
+
+		// A full batch means more messages have probably expired, so sweep again right away.
+		if err == nil && n == sweepBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("expired message sweeper stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep deletes one batch of expired messages, returning how many were deleted.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	return s.store.DeleteExpiredMessages(ctx, sweepBatch)
+}
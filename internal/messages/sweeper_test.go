@@ -0,0 +1,44 @@
+package messages_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweeper_Sweep(t *testing.T) {
+	tests := []struct {
+		name     string
+		deleted  int
+		storeErr error
+	}{
+		{
+			name:    "success",
+			deleted: 12,
+		},
+		{
+			name:     "fails",
+			storeErr: errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().DeleteExpiredMessages(gomock.Any(), 500).Return(tt.deleted, tt.storeErr).Times(1)
+
+			n, err := messages.NewSweeper(s, time.Minute).Sweep(context.Background())
+			assert.Equal(t, tt.storeErr, err)
+			assert.Equal(t, tt.deleted, n)
+		})
+	}
+}
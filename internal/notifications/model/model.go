@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	// TypeMention is the type of the notifications of being mentioned in a message.
+	TypeMention = "mention"
+)
+
+// Notification tells a user about a message concerning them, such as one that
+// mentions them. Excerpt is the beginning of the message text, kept up to date
+// when the message is edited. ReadAt is nil until the user has read it.
+type Notification struct {
+	ID        *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	Username  *string    `json:"-" db:"username"`
+	Type      *string    `json:"type" db:"type"`
+	ChatID    *string    `json:"chat_id" db:"chat_id"`
+	MessageID *string    `json:"message_id" db:"message_id"`
+	Author    *string    `json:"author" db:"author"`
+	Excerpt   *string    `json:"excerpt" db:"excerpt"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt *time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ErrNotificationNotFound = errors.Error("notification_not_found: notification not found")
+)
+
+type Store interface {
+	ListNotifications(ctx context.Context, username string, limit int64, before string, unread bool) ([]model.Notification, error)
+	MarkRead(ctx context.Context, username, id string) (*model.Notification, error)
+	MarkAllRead(ctx context.Context, username string) (int64, error)
+}
+
+// NotificationService serves the notification inboxes of users. Notifications are
+// written along with the messages they are about, by the message store.
+type NotificationService struct {
+	store Store
+}
+
+func New(s Store) *NotificationService {
+	return &NotificationService{
+		store: s,
+	}
+}
+
+// ListNotifications returns the newest notifications of a user, newest first.
+// Older ones are paged through with before, the id of the last one returned.
+func (n *NotificationService) ListNotifications(ctx context.Context, username string, limit int64, before string, unread bool) ([]model.Notification, error) {
+	return n.store.ListNotifications(ctx, normalize(username), limit, before, unread)
+}
+
+func (n *NotificationService) MarkRead(ctx context.Context, username, id string) (*model.Notification, error) {
+	notification, err := n.store.MarkRead(ctx, normalize(username), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotificationNotFound
+	}
+	return notification, err
+}
+
+// MarkAllRead marks every notification of a user as read, returning how many
+// were unread.
+func (n *NotificationService) MarkAllRead(ctx context.Context, username string) (int64, error) {
+	return n.store.MarkAllRead(ctx, normalize(username))
+}
+
+// normalize returns the form usernames are stored in, since they are case insensitive.
+func normalize(username string) string {
+	return strings.ToLower(username)
+}
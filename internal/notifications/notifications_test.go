@@ -0,0 +1,91 @@
+package notifications_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNotificationService_ListNotifications_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := []model.Notification{{ID: pointer.ToString("1"), Type: pointer.ToString(model.TypeMention)}}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListNotifications(gomock.Any(), "alice", int64(20), "10", true).Return(want, nil).Times(1)
+
+	n := notifications.New(s)
+
+	ns, err := n.ListNotifications(context.Background(), "Alice", 20, "10", true)
+	require.NoError(t, err)
+	assert.Equal(t, want, ns)
+}
+
+func TestNotificationService_MarkRead_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := &model.Notification{ID: pointer.ToString("1")}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().MarkRead(gomock.Any(), "alice", "1").Return(want, nil).Times(1)
+
+	n, err := notifications.New(s).MarkRead(context.Background(), "ALICE", "1")
+	require.NoError(t, err)
+	assert.Equal(t, want, n)
+}
+
+func TestNotificationService_MarkRead_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:     "not found",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  notifications.ErrNotificationNotFound,
+		},
+		{
+			name:     "fails",
+			storeErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().MarkRead(gomock.Any(), "alice", "1").Return(nil, tt.storeErr).Times(1)
+
+			n, err := notifications.New(s).MarkRead(context.Background(), "alice", "1")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, n)
+		})
+	}
+}
+
+func TestNotificationService_MarkAllRead_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().MarkAllRead(gomock.Any(), "alice").Return(int64(3), nil).Times(1)
+
+	marked, err := notifications.New(s).MarkAllRead(context.Background(), "Alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), marked)
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+
+	"github.com/AlekSi/pointer"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxExcerptLength is the number of characters of a message kept in its notifications.
+const maxExcerptLength = 200
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// ListNotifications returns up to limit of the newest notifications of a user.
+// When before is set only older notifications are returned, and with unread only
+// those not read yet.
+func (s *Store) ListNotifications(ctx context.Context, username string, limit int64, before string, unread bool) ([]model.Notification, error) {
+	var ns []model.Notification
+
+	q := s.db.WithContext(ctx).Where("username = ?", username)
+	if before != "" {
+		q = q.Where("id < ?", before)
+	}
+	if unread {
+		q = q.Where("read_at IS NULL")
+	}
+
+	if err := q.Order("id DESC").Limit(int(limit)).Find(&ns).Error; err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// MarkRead marks a notification of a user as read, keeping the time it was first
+// read. It returns gorm.ErrRecordNotFound when the user has no such notification.
+func (s *Store) MarkRead(ctx context.Context, username, id string) (*model.Notification, error) {
+	var n model.Notification
+
+	res := s.db.WithContext(ctx).Raw(
+		"UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = ? AND username = ? RETURNING *",
+		id, username,
+	).Scan(&n)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &n, nil
+}
+
+// MarkAllRead marks every unread notification of a user as read, returning how
+// many there were.
+func (s *Store) MarkAllRead(ctx context.Context, username string) (int64, error) {
+	res := s.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("username = ? AND read_at IS NULL", username).
+		Update("read_at", time.Now().UTC())
+	return res.RowsAffected, res.Error
+}
+
+// SyncMentions brings the mention notifications of a message in line with its
+// mentions within the transaction storing the message. Users no longer mentioned
+// lose their notification, and the notifications of users still mentioned are
+// updated but keep whether they were read. Authors are not notified of mentioning
+// themselves.
+func SyncMentions(tx *gorm.DB, m *msmodel.Message) error {
+	recipients := mentioned(m)
+
+	retract := tx.Where("message_id = ? AND type = ?", *m.ID, model.TypeMention)
+	if len(recipients) > 0 {
+		retract = retract.Where("username NOT IN ?", recipients)
+	}
+	if err := retract.Delete(&model.Notification{}).Error; err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	excerpt := truncate(pointer.GetString(m.Text), maxExcerptLength)
+	ns := make([]model.Notification, 0, len(recipients))
+	for _, username := range recipients {
+		ns = append(ns, model.Notification{
+			Username:  pointer.ToString(username),
+			Type:      pointer.ToString(model.TypeMention),
+			ChatID:    m.ChatID,
+			MessageID: m.ID,
+			Author:    m.Author,
+			Excerpt:   pointer.ToString(excerpt),
+		})
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "type"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"author", "excerpt", "updated_at"}),
+	}).Create(&ns).Error
+}
+
+// mentioned returns the distinct users mentioned in a message other than its
+// author. Usernames are case insensitive and kept in lower case.
+func mentioned(m *msmodel.Message) []string {
+	author := strings.ToLower(pointer.GetString(m.Author))
+
+	var users []string
+	seen := map[string]bool{}
+	for _, mention := range m.Mentions {
+		username := strings.ToLower(mention.Username)
+		if username == author || seen[username] {
+			continue
+		}
+		seen[username] = true
+		users = append(users, username)
+	}
+	return users
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+)
+
+type Record struct {
+	ID            int64           `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	ChatID        string          `json:"chat_id" db:"chat_id"`
+	Event         string          `json:"event" db:"event"`
+	Payload       json.RawMessage `json:"payload" db:"payload" gorm:"type:jsonb"`
+	CreatedAt     *time.Time      `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at" db:"published_at"`
+	Attempts      int             `json:"attempts" db:"attempts" gorm:"default:0"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+}
+
+func (Record) TableName() string {
+	return "outbox"
+}
+
+// Outcome is what the relay made of a batch of claimed records: the ids of the
+// records it published and the records it failed to publish.
+type Outcome struct {
+	Published []int64
+	Failed    []Failure
+}
+
+// Failure is a record that failed to be published and when to try it again.
+type Failure struct {
+	ID      int64
+	RetryAt time.Time
+}
+
+// FromEvent builds the outbox record persisted for an event.
+func FromEvent(e events.Event) Record {
+	return Record{
+		ChatID:    e.ChatID,
+		Event:     string(e.Type),
+		Payload:   e.Data,
+		CreatedAt: &e.OccurredAt,
+	}
+}
+
+// ToEvent restores the event stored in the record.
+func (r Record) ToEvent() events.Event {
+	e := events.Event{
+		ID:     strconv.FormatInt(r.ID, 10),
+		Type:   events.Type(r.Event),
+		ChatID: r.ChatID,
+		Data:   r.Payload,
+	}
+	if r.CreatedAt != nil {
+		e.OccurredAt = *r.CreatedAt
+	}
+	return e
+}
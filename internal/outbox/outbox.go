@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox/model"
+
+	"go.uber.org/zap"
+)
+
+type Store interface {
+	Claim(ctx context.Context, limit int, fn func(ctx context.Context, records []model.Record) (model.Outcome, error)) (int, error)
+}
+
+// maxRetryDelay caps the backoff between attempts to publish a failing record.
+const maxRetryDelay = time.Hour
+
+// Sink receives the events relayed from the outbox. A sink may see the same event
+// more than once and should use the event id to discard duplicates.
+type Sink interface {
+	Publish(ctx context.Context, e events.Event) error
+}
+
+// Relay publishes outbox records to the registered sinks. Every record is published
+// at least once, and records of the same chat are published in the order they were written.
+type Relay struct {
+	store    Store
+	sinks    []Sink
+	interval time.Duration
+	batch    int
+}
+
+func New(s Store, interval time.Duration, batch int, sinks ...Sink) *Relay {
+	return &Relay{
+		store:    s,
+		sinks:    sinks,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Listen polls the outbox until the context is cancelled.
+func (r *Relay) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("outbox relay starting")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.RelayBatch(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to relay outbox batch", zap.Error(err))
+		}
+
+		// A full batch means more records are probably waiting, so poll again right away.
+		if err == nil && n == r.batch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("outbox relay stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch claims one batch of records and publishes it, returning the number of records claimed.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	return r.store.Claim(ctx, r.batch, r.publish)
+}
+
+// publish hands the records to every sink in order. Once a record of a chat fails,
+// the remaining records of that chat are held back so they are retried in order,
+// and the failed record is retried with an exponential backoff.
+func (r *Relay) publish(ctx context.Context, records []model.Record) (model.Outcome, error) {
+	out := model.Outcome{Published: make([]int64, 0, len(records))}
+	blocked := make(map[string]bool)
+
+	for _, rec := range records {
+		if blocked[rec.ChatID] {
+			continue
+		}
+
+		e := rec.ToEvent()
+
+		for _, sink := range r.sinks {
+			if err := sink.Publish(ctx, e); err != nil {
+				delay := r.retryDelay(rec.Attempts + 1)
+
+				logging.From(ctx).Error("failed to publish outbox event",
+					zap.Int64("outbox_id", rec.ID),
+					zap.String("chat_id", rec.ChatID),
+					zap.String("type", rec.Event),
+					zap.Int("attempts", rec.Attempts+1),
+					zap.Duration("retry_in", delay),
+					zap.Error(err),
+				)
+				blocked[rec.ChatID] = true
+				out.Failed = append(out.Failed, model.Failure{ID: rec.ID, RetryAt: time.Now().Add(delay)})
+				break
+			}
+		}
+
+		if !blocked[rec.ChatID] {
+			out.Published = append(out.Published, rec.ID)
+		}
+	}
+
+	return out, nil
+}
+
+// retryDelay returns how long to wait before the next attempt to publish a record
+// that failed attempts times: the poll interval, doubled with every attempt.
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	d := r.interval
+	for i := 1; i < attempts && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
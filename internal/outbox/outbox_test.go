@@ -0,0 +1,156 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func claimWith(t *testing.T, records []model.Record, wantPublished []int64, wantFailed map[int64]time.Duration) func(ctx context.Context, limit int, fn func(context.Context, []model.Record) (model.Outcome, error)) (int, error) {
+	return func(ctx context.Context, limit int, fn func(context.Context, []model.Record) (model.Outcome, error)) (int, error) {
+		start := time.Now()
+		out, err := fn(ctx, records)
+		if err != nil {
+			return 0, err
+		}
+		assert.Equal(t, wantPublished, out.Published)
+
+		// Failed records are retried after the wanted delay.
+		assert.Len(t, out.Failed, len(wantFailed))
+		for _, f := range out.Failed {
+			delay, ok := wantFailed[f.ID]
+			if assert.True(t, ok, "unexpected failure of %d", f.ID) {
+				assert.WithinRange(t, f.RetryAt, start.Add(delay), time.Now().Add(delay))
+			}
+		}
+		return len(records), nil
+	}
+}
+
+func TestRelay_RelayBatch_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	sink := mocks.NewMockSink(ctrl)
+
+	r := outbox.New(s, time.Second, 10, sink)
+	require.NotNil(t, r)
+
+	records := []model.Record{
+		{ID: 1, ChatID: "1", Event: string(events.ChatCreated), Payload: []byte(`{}`)},
+		{ID: 2, ChatID: "1", Event: string(events.MessageCreated), Payload: []byte(`{}`)},
+	}
+
+	s.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).DoAndReturn(claimWith(t, records, []int64{1, 2}, nil)).Times(1)
+
+	gomock.InOrder(
+		sink.EXPECT().Publish(gomock.Any(), gomock.AssignableToTypeOf(events.Event{})).
+			DoAndReturn(func(ctx context.Context, e events.Event) error {
+				assert.Equal(t, "1", e.ID)
+				assert.Equal(t, events.ChatCreated, e.Type)
+				return nil
+			}),
+		sink.EXPECT().Publish(gomock.Any(), gomock.AssignableToTypeOf(events.Event{})).
+			DoAndReturn(func(ctx context.Context, e events.Event) error {
+				assert.Equal(t, "2", e.ID)
+				assert.Equal(t, events.MessageCreated, e.Type)
+				return nil
+			}),
+	)
+
+	n, err := r.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestRelay_RelayBatch_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	sink := mocks.NewMockSink(ctrl)
+
+	r := outbox.New(s, time.Second, 10, sink)
+
+	records := []model.Record{
+		{ID: 1, ChatID: "1", Event: string(events.MessageCreated)},
+		{ID: 2, ChatID: "2", Event: string(events.MessageCreated)},
+		{ID: 3, ChatID: "1", Event: string(events.MessageCreated)},
+		{ID: 4, ChatID: "2", Event: string(events.MessageCreated)},
+	}
+
+	// The first event of chat 1 fails, so the later event of chat 1 must be held
+	// back while chat 2 keeps flowing.
+	s.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).DoAndReturn(claimWith(t, records, []int64{2, 4}, map[int64]time.Duration{1: time.Second})).Times(1)
+
+	sink.EXPECT().Publish(gomock.Any(), gomock.AssignableToTypeOf(events.Event{})).
+		DoAndReturn(func(ctx context.Context, e events.Event) error {
+			if e.ID == "1" {
+				return errors.New("test fail")
+			}
+			assert.Equal(t, "2", e.ChatID)
+			return nil
+		}).Times(3)
+
+	n, err := r.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+}
+
+func TestRelay_RelayBatch_Backoff(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		wantDelay time.Duration
+	}{
+		{
+			name:      "first failure",
+			attempts:  0,
+			wantDelay: time.Second,
+		},
+		{
+			name:      "doubled with every attempt",
+			attempts:  3,
+			wantDelay: 8 * time.Second,
+		},
+		{
+			name:      "capped",
+			attempts:  40,
+			wantDelay: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			sink := mocks.NewMockSink(ctrl)
+
+			r := outbox.New(s, time.Second, 10, sink)
+
+			records := []model.Record{
+				{ID: 1, ChatID: "1", Event: string(events.MessageCreated), Attempts: tt.attempts},
+			}
+
+			s.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).
+				DoAndReturn(claimWith(t, records, []int64{}, map[int64]time.Duration{1: tt.wantDelay})).Times(1)
+			sink.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("test fail")).Times(1)
+
+			n, err := r.RelayBatch(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+		})
+	}
+}
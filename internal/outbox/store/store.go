@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/outbox/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockNamespace scopes the per-chat advisory locks taken by the relay.
+const lockNamespace = 0x6f7574
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Append records an event in the outbox. It must be called with the transaction
+// that writes the change the event describes, so both are committed together.
+func Append(tx *gorm.DB, e events.Event) error {
+	r := model.FromEvent(e)
+	return tx.Create(&r).Error
+}
+
+// Claim locks up to limit unpublished records and passes them to fn in id order.
+// Records that fn reports published are marked as such, and failed ones are put
+// off until their retry time, when the transaction commits.
+//
+// Whole chats are claimed through an advisory lock before their rows are locked
+// with FOR UPDATE SKIP LOCKED, so concurrent relays never publish events of the
+// same chat out of order. Chats are tried oldest first until limit of them are
+// locked, so relays running side by side each get chats of their own. A chat
+// whose oldest record is waiting for a retry is skipped altogether.
+func (s *Store) Claim(ctx context.Context, limit int, fn func(ctx context.Context, records []model.Record) (model.Outcome, error)) (int, error) {
+	var claimed int
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var chatIDs []string
+		err := tx.Raw(`
+			SELECT chat_id FROM (
+				SELECT chat_id, id FROM (
+					SELECT DISTINCT ON (chat_id) chat_id, id, next_attempt_at
+					FROM outbox
+					WHERE published_at IS NULL
+					ORDER BY chat_id, id
+				) firsts
+				WHERE next_attempt_at IS NULL OR next_attempt_at <= now()
+				ORDER BY id
+			) heads
+			WHERE pg_try_advisory_xact_lock(?, chat_id)
+			LIMIT ?`, lockNamespace, limit).
+			Scan(&chatIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(chatIDs) == 0 {
+			return nil
+		}
+
+		var records []model.Record
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND chat_id IN ?", chatIDs).
+			Order("id").
+			Limit(limit).
+			Find(&records).Error
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		claimed = len(records)
+
+		out, err := fn(ctx, records)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range out.Failed {
+			err := tx.Model(&model.Record{}).
+				Where("id = ?", f.ID).
+				Updates(map[string]interface{}{
+					"attempts":        gorm.Expr("attempts + 1"),
+					"next_attempt_at": f.RetryAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(out.Published) == 0 {
+			return nil
+		}
+
+		return tx.Model(&model.Record{}).
+			Where("id IN ?", out.Published).
+			Update("published_at", gorm.Expr("now()")).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return claimed, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/reads (interfaces: Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// GetUnread mocks base method.
+func (m *MockStore) GetUnread(arg0 context.Context, arg1 string, arg2 []string) ([]model.Unread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnread", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Unread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnread indicates an expected call of GetUnread.
+func (mr *MockStoreMockRecorder) GetUnread(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnread", reflect.TypeOf((*MockStore)(nil).GetUnread), arg0, arg1, arg2)
+}
+
+// SetCursor mocks base method.
+func (m *MockStore) SetCursor(arg0 context.Context, arg1, arg2, arg3 string) (*model.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCursor", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Cursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCursor indicates an expected call of SetCursor.
+func (mr *MockStoreMockRecorder) SetCursor(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCursor", reflect.TypeOf((*MockStore)(nil).SetCursor), arg0, arg1, arg2, arg3)
+}
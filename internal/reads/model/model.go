@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Cursor is how far a user has read a chat: every message up to and including
+// LastReadMessageID, whose seq is LastReadSeq, is read.
+type Cursor struct {
+	Username          *string    `json:"-" db:"username"`
+	ChatID            *string    `json:"chat_id" db:"chat_id"`
+	LastReadMessageID *string    `json:"last_read_message_id" db:"last_read_message_id"`
+	LastReadSeq       *int64     `json:"last_read_seq" db:"last_read_seq"`
+	UpdatedAt         *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (Cursor) TableName() string {
+	return "chat_reads"
+}
+
+// Unread tells how many messages of a chat a user has not read yet, and which is
+// the first of them. FirstUnreadID is nil when every message is read. The cursor
+// it was computed from is nil when the user never read the chat.
+type Unread struct {
+	ChatID            *string    `json:"chat_id" db:"chat_id"`
+	UnreadCount       *int64     `json:"unread_count" db:"unread_count"`
+	FirstUnreadID     *string    `json:"first_unread_id" db:"first_unread_id"`
+	LastReadMessageID *string    `json:"last_read_message_id" db:"last_read_message_id"`
+	ReadAt            *time.Time `json:"read_at" db:"read_at"`
+}
@@ -0,0 +1,55 @@
+package reads
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ErrMessageNotFound = errors.Error("message_not_found: message not found")
+)
+
+type Store interface {
+	SetCursor(ctx context.Context, username, chatID, messageID string) (*model.Cursor, error)
+	GetUnread(ctx context.Context, username string, chatIDs []string) ([]model.Unread, error)
+}
+
+// ReadService keeps track of how far each user has read each chat.
+type ReadService struct {
+	store Store
+}
+
+func New(s Store) *ReadService {
+	return &ReadService{
+		store: s,
+	}
+}
+
+// MarkRead sets the read cursor of a user in a chat to one of its messages. The
+// cursor may move back, which makes the messages after it unread again.
+func (r *ReadService) MarkRead(ctx context.Context, username, chatID, messageID string) (*model.Cursor, error) {
+	c, err := r.store.SetCursor(ctx, normalize(username), chatID, messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	return c, err
+}
+
+// Unread returns how much of each of the given chats a user has not read, in no
+// particular order. Unknown chats are skipped.
+func (r *ReadService) Unread(ctx context.Context, username string, chatIDs []string) ([]model.Unread, error) {
+	if len(chatIDs) == 0 {
+		return nil, nil
+	}
+	return r.store.GetUnread(ctx, normalize(username), chatIDs)
+}
+
+// normalize returns the form usernames are stored in, since they are case insensitive.
+func normalize(username string) string {
+	return strings.ToLower(username)
+}
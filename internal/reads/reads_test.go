@@ -0,0 +1,105 @@
+package reads_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestReadService_MarkRead_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := &model.Cursor{ChatID: pointer.ToString("1"), LastReadMessageID: pointer.ToString("7")}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().SetCursor(gomock.Any(), "alice", "1", "7").Return(want, nil).Times(1)
+
+	c, err := reads.New(s).MarkRead(context.Background(), "Alice", "1", "7")
+	require.NoError(t, err)
+	assert.Equal(t, want, c)
+}
+
+func TestReadService_MarkRead_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:     "message not in chat",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  reads.ErrMessageNotFound,
+		},
+		{
+			name:     "fails",
+			storeErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().SetCursor(gomock.Any(), "alice", "1", "7").Return(nil, tt.storeErr).Times(1)
+
+			c, err := reads.New(s).MarkRead(context.Background(), "alice", "1", "7")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, c)
+		})
+	}
+}
+
+func TestReadService_Unread_Success(t *testing.T) {
+	tests := []struct {
+		name    string
+		chatIDs []string
+		setup   func(s *mocks.MockStore)
+		want    []model.Unread
+	}{
+		{
+			name:    "many chats with one query",
+			chatIDs: []string{"1", "2"},
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().GetUnread(gomock.Any(), "alice", []string{"1", "2"}).Return([]model.Unread{
+					{ChatID: pointer.ToString("1"), UnreadCount: pointer.ToInt64(3), FirstUnreadID: pointer.ToString("8")},
+					{ChatID: pointer.ToString("2"), UnreadCount: pointer.ToInt64(0)},
+				}, nil).Times(1)
+			},
+			want: []model.Unread{
+				{ChatID: pointer.ToString("1"), UnreadCount: pointer.ToInt64(3), FirstUnreadID: pointer.ToString("8")},
+				{ChatID: pointer.ToString("2"), UnreadCount: pointer.ToInt64(0)},
+			},
+		},
+		{
+			name: "no chats",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			us, err := reads.New(s).Unread(context.Background(), "ALICE", tt.chatIDs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, us)
+		})
+	}
+}
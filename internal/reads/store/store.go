@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"gorm.io/gorm"
+)
+
+// unreadWindow bounds how many messages after a read cursor are looked at to
+// correct an unread count.
+const unreadWindow = 1000
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// SetCursor moves the read cursor of a user in a chat to one of its messages. It
+// returns gorm.ErrRecordNotFound when the chat has no such message.
+func (s *Store) SetCursor(ctx context.Context, username, chatID, messageID string) (*model.Cursor, error) {
+	var c model.Cursor
+
+	res := s.db.WithContext(ctx).Raw(`
+		INSERT INTO chat_reads (username, chat_id, last_read_message_id, last_read_seq, updated_at)
+		SELECT ?, chat_id, id, seq, now() FROM messages WHERE chat_id = ? AND id = ?
+		ON CONFLICT (username, chat_id) DO UPDATE
+		SET last_read_message_id = EXCLUDED.last_read_message_id,
+			last_read_seq = EXCLUDED.last_read_seq,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`,
+		username, chatID, messageID,
+	).Scan(&c)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &c, nil
+}
+
+// GetUnread tells how many messages a user has not read in each of the given
+// chats with a single query. The count is the distance between the last seq of
+// the chat and the read seq of the user, corrected for the messages that do not
+// count: those by the user themselves, expired ones, and deleted ones, whose seqs
+// are missing. The correction only walks the first unreadWindow seqs after the
+// cursor through the (chat_id, seq) index, so beyond them the count is an upper
+// bound. Unknown chats are skipped.
+func (s *Store) GetUnread(ctx context.Context, username string, chatIDs []string) ([]model.Unread, error) {
+	var us []model.Unread
+
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT c.id AS chat_id,
+			u.unread_count + GREATEST(c.last_seq - COALESCE(r.last_read_seq, 0) - ?, 0) AS unread_count,
+			u.first_unread_id, r.last_read_message_id, r.updated_at AS read_at
+		FROM chats c
+		LEFT JOIN chat_reads r ON r.chat_id = c.id AND r.username = ?
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS unread_count, MIN(m.id) AS first_unread_id
+			FROM messages m
+			WHERE m.chat_id = c.id
+				AND m.seq > COALESCE(r.last_read_seq, 0)
+				AND m.seq <= COALESCE(r.last_read_seq, 0) + ?
+				AND (m.author IS NULL OR lower(m.author) <> ?)
+				AND (m.expires_at IS NULL OR m.expires_at > now())
+		) u
+		WHERE c.id IN ?`,
+		unreadWindow, username, unreadWindow, username, chatIDs,
+	).Scan(&us).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return us, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/retention (interfaces: Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ListPurges mocks base method.
+func (m *MockStore) ListPurges(arg0 context.Context, arg1 string, arg2 int64, arg3 string) ([]model.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurges indicates an expected call of ListPurges.
+func (mr *MockStoreMockRecorder) ListPurges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurges", reflect.TypeOf((*MockStore)(nil).ListPurges), arg0, arg1, arg2, arg3)
+}
+
+// Purge mocks base method.
+func (m *MockStore) Purge(arg0 context.Context, arg1 *int64, arg2 int) ([]model.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockStoreMockRecorder) Purge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockStore)(nil).Purge), arg0, arg1, arg2)
+}
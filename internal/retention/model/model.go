@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Purge reports the messages of a chat deleted in one batch by the retention
+// job: how many there were and when the oldest and newest of them were posted.
+type Purge struct {
+	ID       *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	ChatID   *string    `json:"chat_id" db:"chat_id"`
+	Messages *int64     `json:"messages" db:"messages"`
+	OldestAt *time.Time `json:"oldest_at" db:"oldest_at"`
+	NewestAt *time.Time `json:"newest_at" db:"newest_at"`
+	PurgedAt *time.Time `json:"purged_at" db:"purged_at"`
+}
+
+func (Purge) TableName() string {
+	return "retention_purges"
+}
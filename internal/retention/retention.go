@@ -0,0 +1,100 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+)
+
+// purgeBatch bounds the number of messages purged in one transaction.
+const purgeBatch = 1000
+
+type Store interface {
+	Purge(ctx context.Context, days *int64, limit int) ([]model.Purge, error)
+	ListPurges(ctx context.Context, chatID string, limit int64, before string) ([]model.Purge, error)
+}
+
+// RetentionService enforces the retention policy: messages older than the
+// retention of their chat, or than the global retention for chats without one of
+// their own, are purged in the background. Chats under legal hold are skipped.
+// Every purge is reported, so that what was deleted can be accounted for.
+type RetentionService struct {
+	store    Store
+	days     *int64
+	interval time.Duration
+}
+
+// New returns a RetentionService purging messages every interval. With days set
+// to 0 there is no global retention, and only chats with a retention of their own
+// are purged.
+func New(s Store, days int64, interval time.Duration) *RetentionService {
+	r := &RetentionService{
+		store:    s,
+		interval: interval,
+	}
+	if days > 0 {
+		r.days = pointer.ToInt64(days)
+	}
+	return r
+}
+
+// Listen purges messages past their retention until the context is cancelled.
+func (r *RetentionService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("retention job starting")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Purge(ctx); err != nil {
+			logging.From(ctx).Error("failed to purge messages past their retention", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("retention job stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the messages past their retention, batch by batch, and returns
+// the reports of the purges it made.
+func (r *RetentionService) Purge(ctx context.Context) ([]model.Purge, error) {
+	var purges []model.Purge
+	for {
+		ps, err := r.store.Purge(ctx, r.days, purgeBatch)
+		if err != nil {
+			return purges, err
+		}
+
+		var n int64
+		for _, p := range ps {
+			logging.From(ctx).Info("purged messages past their retention",
+				zap.String("chat_id", pointer.GetString(p.ChatID)),
+				zap.Int64("messages", pointer.GetInt64(p.Messages)),
+				zap.Timep("oldest_at", p.OldestAt),
+				zap.Timep("newest_at", p.NewestAt),
+			)
+			n += pointer.GetInt64(p.Messages)
+		}
+		purges = append(purges, ps...)
+
+		if n < purgeBatch {
+			return purges, nil
+		}
+	}
+}
+
+// ListPurges returns the newest purge reports, of a single chat when chatID is
+// set, newest first. Older ones are paged through with before, the id of the last
+// one returned.
+func (r *RetentionService) ListPurges(ctx context.Context, chatID string, limit int64, before string) ([]model.Purge, error) {
+	return r.store.ListPurges(ctx, chatID, limit, before)
+}
@@ -0,0 +1,110 @@
+package retention_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func purge(chatID string, messages int64) model.Purge {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return model.Purge{
+		ChatID:   pointer.ToString(chatID),
+		Messages: pointer.ToInt64(messages),
+		OldestAt: &at,
+		NewestAt: &at,
+	}
+}
+
+func TestRetentionService_Purge_Success(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     int64
+		wantDays *int64
+		batches  [][]model.Purge
+	}{
+		{
+			name:     "global retention",
+			days:     365,
+			wantDays: pointer.ToInt64(365),
+			batches:  [][]model.Purge{{purge("1", 3), purge("2", 1)}},
+		},
+		{
+			name:    "chat retention only",
+			batches: [][]model.Purge{{purge("1", 2)}},
+		},
+		{
+			name:     "several batches",
+			days:     30,
+			wantDays: pointer.ToInt64(30),
+			batches:  [][]model.Purge{{purge("1", 600), purge("2", 400)}, {purge("2", 7)}},
+		},
+		{
+			name:     "nothing to purge",
+			days:     30,
+			wantDays: pointer.ToInt64(30),
+			batches:  [][]model.Purge{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+
+			var want []model.Purge
+			var calls []*gomock.Call
+			for _, b := range tt.batches {
+				calls = append(calls, s.EXPECT().Purge(gomock.Any(), tt.wantDays, 1000).Return(b, nil).Times(1))
+				want = append(want, b...)
+			}
+			gomock.InOrder(calls...)
+
+			purges, err := retention.New(s, tt.days, time.Hour).Purge(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, want, purges)
+		})
+	}
+}
+
+func TestRetentionService_Purge_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := []model.Purge{purge("1", 1000)}
+
+	s := mocks.NewMockStore(ctrl)
+	gomock.InOrder(
+		s.EXPECT().Purge(gomock.Any(), pointer.ToInt64(30), 1000).Return(first, nil).Times(1),
+		s.EXPECT().Purge(gomock.Any(), pointer.ToInt64(30), 1000).Return(nil, errors.Error("test fail")).Times(1),
+	)
+
+	purges, err := retention.New(s, 30, time.Hour).Purge(context.Background())
+	assert.ErrorIs(t, err, errors.Error("test fail"))
+	assert.Equal(t, first, purges)
+}
+
+func TestRetentionService_ListPurges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := []model.Purge{purge("1", 3)}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListPurges(gomock.Any(), "1", int64(20), "9").Return(want, nil).Times(1)
+
+	purges, err := retention.New(s, 0, time.Hour).ListPurges(context.Background(), "1", 20, "9")
+	require.NoError(t, err)
+	assert.Equal(t, want, purges)
+}
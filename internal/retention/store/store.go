@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	outboxStore "github.com/Polilo-User/test-task-hitalent/internal/outbox/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+
+	"github.com/AlekSi/pointer"
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Purge deletes up to limit of the messages older than the retention of their
+// chat, or than days when the chat has none of its own, and reports them per
+// chat. A message.deleted event is recorded for each. Chats without a retention
+// keep their messages when days is nil, and chats under legal hold always do.
+// Messages locked by other transactions are left for the next batch.
+func (s *Store) Purge(ctx context.Context, days *int64, limit int) ([]model.Purge, error) {
+	var purges []model.Purge
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deleted []msmodel.Message
+
+		err := tx.Raw(`
+			DELETE FROM messages
+			WHERE id IN (
+				SELECT m.id
+				FROM chats c
+				CROSS JOIN LATERAL (
+					SELECT id FROM messages
+					WHERE chat_id = c.id
+						AND created_at < now() - make_interval(days => COALESCE(c.retention_days, CAST(? AS int)))
+					ORDER BY created_at, id
+					LIMIT ?
+					FOR UPDATE SKIP LOCKED
+				) m
+				WHERE NOT c.legal_hold AND COALESCE(c.retention_days, CAST(? AS int)) IS NOT NULL
+				LIMIT ?
+			)
+			RETURNING id, chat_id, created_at`, days, limit, days, limit).
+			Scan(&deleted).Error
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+
+		// Purges are reported in the order their chats first appear.
+		byChat := make(map[string]int)
+		for _, m := range deleted {
+			i, ok := byChat[*m.ChatID]
+			if !ok {
+				i = len(purges)
+				byChat[*m.ChatID] = i
+				purges = append(purges, model.Purge{
+					ChatID:   m.ChatID,
+					Messages: pointer.ToInt64(0),
+					OldestAt: m.CreatedAt,
+					NewestAt: m.CreatedAt,
+				})
+			}
+
+			p := &purges[i]
+			*p.Messages++
+			if m.CreatedAt.Before(*p.OldestAt) {
+				p.OldestAt = m.CreatedAt
+			}
+			if m.CreatedAt.After(*p.NewestAt) {
+				p.NewestAt = m.CreatedAt
+			}
+
+			e, err := events.New(events.MessageDeleted, *m.ChatID, map[string]string{
+				"id":      *m.ID,
+				"chat_id": *m.ChatID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := outboxStore.Append(tx, e); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&purges).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purges, nil
+}
+
+// ListPurges returns up to limit of the newest purge reports, of a single chat
+// when chatID is set. When before is set only older reports are returned.
+func (s *Store) ListPurges(ctx context.Context, chatID string, limit int64, before string) ([]model.Purge, error) {
+	var ps []model.Purge
+
+	q := s.db.WithContext(ctx)
+	if chatID != "" {
+		q = q.Where("chat_id = ?", chatID)
+	}
+	if before != "" {
+		q = q.Where("id < ?", before)
+	}
+
+	if err := q.Order("id DESC").Limit(int(limit)).Find(&ps).Error; err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
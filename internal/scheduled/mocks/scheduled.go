@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/scheduled (interfaces: ChatService,Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model0 "github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChatService is a mock of ChatService interface.
+type MockChatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatServiceMockRecorder
+}
+
+// MockChatServiceMockRecorder is the mock recorder for MockChatService.
+type MockChatServiceMockRecorder struct {
+	mock *MockChatService
+}
+
+// NewMockChatService creates a new mock instance.
+func NewMockChatService(ctrl *gomock.Controller) *MockChatService {
+	mock := &MockChatService{ctrl: ctrl}
+	mock.recorder = &MockChatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatService) EXPECT() *MockChatServiceMockRecorder {
+	return m.recorder
+}
+
+// ChatExist mocks base method.
+func (m *MockChatService) ChatExist(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChatExist", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChatExist indicates an expected call of ChatExist.
+func (mr *MockChatServiceMockRecorder) ChatExist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatExist", reflect.TypeOf((*MockChatService)(nil).ChatExist), arg0, arg1)
+}
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteScheduled mocks base method.
+func (m *MockStore) DeleteScheduled(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduled", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduled indicates an expected call of DeleteScheduled.
+func (mr *MockStoreMockRecorder) DeleteScheduled(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduled", reflect.TypeOf((*MockStore)(nil).DeleteScheduled), arg0, arg1, arg2)
+}
+
+// Dispatch mocks base method.
+func (m *MockStore) Dispatch(arg0 context.Context, arg1 int, arg2 func(model0.ScheduledMessage) *model.Message) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockStoreMockRecorder) Dispatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockStore)(nil).Dispatch), arg0, arg1, arg2)
+}
+
+// InsertScheduled mocks base method.
+func (m *MockStore) InsertScheduled(arg0 context.Context, arg1 *model0.ScheduledMessage) (*model0.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertScheduled", arg0, arg1)
+	ret0, _ := ret[0].(*model0.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertScheduled indicates an expected call of InsertScheduled.
+func (mr *MockStoreMockRecorder) InsertScheduled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertScheduled", reflect.TypeOf((*MockStore)(nil).InsertScheduled), arg0, arg1)
+}
+
+// ListScheduled mocks base method.
+func (m *MockStore) ListScheduled(arg0 context.Context, arg1 string) ([]model0.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", arg0, arg1)
+	ret0, _ := ret[0].([]model0.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockStoreMockRecorder) ListScheduled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockStore)(nil).ListScheduled), arg0, arg1)
+}
+
+// UpdateScheduled mocks base method.
+func (m *MockStore) UpdateScheduled(arg0 context.Context, arg1, arg2 string, arg3 *string, arg4 *time.Time) (*model0.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduled", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model0.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduled indicates an expected call of UpdateScheduled.
+func (mr *MockStoreMockRecorder) UpdateScheduled(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduled", reflect.TypeOf((*MockStore)(nil).UpdateScheduled), arg0, arg1, arg2, arg3, arg4)
+}
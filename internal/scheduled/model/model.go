@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ScheduledMessage is a message waiting to be posted to its chat at SendAt. It is
+// deleted once posted.
+type ScheduledMessage struct {
+	ID     *string    `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	ChatID *string    `json:"chat_id" db:"chat_id"`
+	Text   *string    `json:"text" db:"text"`
+	Author *string    `json:"author" db:"author"`
+	SendAt *time.Time `json:"send_at" db:"send_at"`
+	// ExpiresAt is when the message expires once posted.
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt *time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
@@ -0,0 +1,158 @@
+package scheduled
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrScheduledNotFound = errors.Error("scheduled_message_not_found: scheduled message not found")
+	ErrInvalidSendAt     = errors.Error("invalid_send_at: send_at must be in the future")
+	ErrEmptyText         = errors.Error("empty_text: message text is required")
+	ErrInvalidExpiresAt  = errors.Error("invalid_expires_at: expires_at must be after send_at")
+)
+
+// dispatchBatch bounds the number of messages posted in one transaction.
+const dispatchBatch = 100
+
+type Store interface {
+	InsertScheduled(ctx context.Context, m *model.ScheduledMessage) (*model.ScheduledMessage, error)
+	ListScheduled(ctx context.Context, chatID string) ([]model.ScheduledMessage, error)
+	UpdateScheduled(ctx context.Context, chatID, id string, text *string, sendAt *time.Time) (*model.ScheduledMessage, error)
+	DeleteScheduled(ctx context.Context, chatID, id string) error
+	Dispatch(ctx context.Context, limit int, build func(model.ScheduledMessage) *msmodel.Message) (int, error)
+}
+
+type ChatService interface {
+	ChatExist(ctx context.Context, id string) error
+}
+
+// ScheduledService keeps messages written ahead of time until they are due, and
+// then posts them to their chat.
+type ScheduledService struct {
+	store    Store
+	c        ChatService
+	interval time.Duration
+}
+
+func New(s Store, c ChatService, interval time.Duration) *ScheduledService {
+	return &ScheduledService{
+		store:    s,
+		c:        c,
+		interval: interval,
+	}
+}
+
+// Schedule stores a message to be posted to its chat at its send time. Its
+// expiry, if it has one, is kept for when it is posted.
+func (s *ScheduledService) Schedule(ctx context.Context, m *model.ScheduledMessage) (*model.ScheduledMessage, error) {
+	// Both are required here, and fail validation when missing.
+	text, sendAt := pointer.GetString(m.Text), pointer.GetTime(m.SendAt)
+	if err := validate(&text, &sendAt); err != nil {
+		return nil, err
+	}
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(sendAt) {
+		return nil, ErrInvalidExpiresAt
+	}
+
+	if err := s.c.ChatExist(ctx, *m.ChatID); err != nil {
+		return nil, err
+	}
+
+	m.ID = nil
+	return s.store.InsertScheduled(ctx, m)
+}
+
+// ListScheduled returns the messages of a chat waiting to be posted, soonest first.
+func (s *ScheduledService) ListScheduled(ctx context.Context, chatID string) ([]model.ScheduledMessage, error) {
+	if err := s.c.ChatExist(ctx, chatID); err != nil {
+		return nil, err
+	}
+	return s.store.ListScheduled(ctx, chatID)
+}
+
+// UpdateScheduled changes the text or the send time of a message that has not been
+// posted yet. Fields left nil are unchanged.
+func (s *ScheduledService) UpdateScheduled(ctx context.Context, chatID, id string, text *string, sendAt *time.Time) (*model.ScheduledMessage, error) {
+	if err := validate(text, sendAt); err != nil {
+		return nil, err
+	}
+
+	m, err := s.store.UpdateScheduled(ctx, chatID, id, text, sendAt)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScheduledNotFound
+	}
+	return m, err
+}
+
+// CancelScheduled deletes a message that has not been posted yet.
+func (s *ScheduledService) CancelScheduled(ctx context.Context, chatID, id string) error {
+	err := s.store.DeleteScheduled(ctx, chatID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrScheduledNotFound
+	}
+	return err
+}
+
+// Listen posts due messages until the context is cancelled.
+func (s *ScheduledService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("message scheduler starting")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.DispatchDue(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to post scheduled messages", zap.Error(err))
+		}
+
+		// A full batch means more messages are probably due, so dispatch again right away.
+		if err == nil && n == dispatchBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("message scheduler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue posts one batch of due messages, returning how many were posted.
+// Their mentions are parsed as they are posted; slash commands are not run.
+func (s *ScheduledService) DispatchDue(ctx context.Context) (int, error) {
+	return s.store.Dispatch(ctx, dispatchBatch, func(m model.ScheduledMessage) *msmodel.Message {
+		return &msmodel.Message{
+			ChatID:    m.ChatID,
+			Text:      m.Text,
+			Author:    m.Author,
+			ExpiresAt: m.ExpiresAt,
+			Mentions:  messages.ParseMentions(pointer.GetString(m.Text)),
+		}
+	})
+}
+
+// validate checks the text and send time of a scheduled message, when they are set.
+func validate(text *string, sendAt *time.Time) error {
+	if text != nil && strings.TrimSpace(*text) == "" {
+		return ErrEmptyText
+	}
+	if sendAt != nil && !sendAt.After(time.Now()) {
+		return ErrInvalidSendAt
+	}
+	return nil
+}
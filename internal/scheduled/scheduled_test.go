@@ -0,0 +1,232 @@
+package scheduled_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestScheduledService_Schedule_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sendAt := time.Now().Add(time.Hour)
+	in := &model.ScheduledMessage{
+		ID:     pointer.ToString("ignored"),
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("see you @bob"),
+		SendAt: pointer.ToTime(sendAt),
+	}
+
+	c := mocks.NewMockChatService(ctrl)
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().InsertScheduled(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, m *model.ScheduledMessage) (*model.ScheduledMessage, error) {
+			assert.Nil(t, m.ID)
+			m.ID = pointer.ToString("4")
+			return m, nil
+		}).Times(1)
+
+	m, err := scheduled.New(s, c, time.Second).Schedule(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, "4", *m.ID)
+	assert.Equal(t, sendAt, *m.SendAt)
+}
+
+func TestScheduledService_Schedule_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *model.ScheduledMessage
+		chatErr error
+		wantErr error
+	}{
+		{
+			name:    "no text",
+			message: &model.ScheduledMessage{ChatID: pointer.ToString("1"), SendAt: pointer.ToTime(time.Now().Add(time.Hour))},
+			wantErr: scheduled.ErrEmptyText,
+		},
+		{
+			name:    "blank text",
+			message: &model.ScheduledMessage{ChatID: pointer.ToString("1"), Text: pointer.ToString("  "), SendAt: pointer.ToTime(time.Now().Add(time.Hour))},
+			wantErr: scheduled.ErrEmptyText,
+		},
+		{
+			name:    "no send time",
+			message: &model.ScheduledMessage{ChatID: pointer.ToString("1"), Text: pointer.ToString("hi")},
+			wantErr: scheduled.ErrInvalidSendAt,
+		},
+		{
+			name:    "send time in the past",
+			message: &model.ScheduledMessage{ChatID: pointer.ToString("1"), Text: pointer.ToString("hi"), SendAt: pointer.ToTime(time.Now().Add(-time.Minute))},
+			wantErr: scheduled.ErrInvalidSendAt,
+		},
+		{
+			name: "expiry before send time",
+			message: &model.ScheduledMessage{
+				ChatID:    pointer.ToString("1"),
+				Text:      pointer.ToString("hi"),
+				SendAt:    pointer.ToTime(time.Now().Add(time.Hour)),
+				ExpiresAt: pointer.ToTime(time.Now().Add(time.Minute)),
+			},
+			wantErr: scheduled.ErrInvalidExpiresAt,
+		},
+		{
+			name:    "chat not found",
+			message: &model.ScheduledMessage{ChatID: pointer.ToString("1"), Text: pointer.ToString("hi"), SendAt: pointer.ToTime(time.Now().Add(time.Hour))},
+			chatErr: chats.ErrChatNotFound,
+			wantErr: chats.ErrChatNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChatService(ctrl)
+			if tt.chatErr != nil {
+				c.EXPECT().ChatExist(gomock.Any(), "1").Return(tt.chatErr).Times(1)
+			}
+
+			m, err := scheduled.New(mocks.NewMockStore(ctrl), c, time.Second).Schedule(context.Background(), tt.message)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, m)
+		})
+	}
+}
+
+func TestScheduledService_UpdateScheduled_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	want := &model.ScheduledMessage{ID: pointer.ToString("4"), Text: pointer.ToString("later")}
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().UpdateScheduled(gomock.Any(), "1", "4", pointer.ToString("later"), (*time.Time)(nil)).Return(want, nil).Times(1)
+
+	m, err := scheduled.New(s, mocks.NewMockChatService(ctrl), time.Second).
+		UpdateScheduled(context.Background(), "1", "4", pointer.ToString("later"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, m)
+}
+
+func TestScheduledService_UpdateScheduled_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     *string
+		sendAt   *time.Time
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name:    "blank text",
+			text:    pointer.ToString(""),
+			wantErr: scheduled.ErrEmptyText,
+		},
+		{
+			name:    "send time in the past",
+			sendAt:  pointer.ToTime(time.Now().Add(-time.Minute)),
+			wantErr: scheduled.ErrInvalidSendAt,
+		},
+		{
+			name:     "already posted",
+			text:     pointer.ToString("later"),
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  scheduled.ErrScheduledNotFound,
+		},
+		{
+			name:     "fails",
+			text:     pointer.ToString("later"),
+			storeErr: errors.Error("test fail"),
+			wantErr:  errors.Error("test fail"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.storeErr != nil {
+				s.EXPECT().UpdateScheduled(gomock.Any(), "1", "4", tt.text, tt.sendAt).Return(nil, tt.storeErr).Times(1)
+			}
+
+			m, err := scheduled.New(s, mocks.NewMockChatService(ctrl), time.Second).
+				UpdateScheduled(context.Background(), "1", "4", tt.text, tt.sendAt)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, m)
+		})
+	}
+}
+
+func TestScheduledService_CancelScheduled(t *testing.T) {
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "already posted",
+			storeErr: gorm.ErrRecordNotFound,
+			wantErr:  scheduled.ErrScheduledNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().DeleteScheduled(gomock.Any(), "1", "4").Return(tt.storeErr).Times(1)
+
+			err := scheduled.New(s, mocks.NewMockChatService(ctrl), time.Second).CancelScheduled(context.Background(), "1", "4")
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestScheduledService_DispatchDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().Dispatch(gomock.Any(), 100, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, build func(model.ScheduledMessage) *msmodel.Message) (int, error) {
+			m := build(model.ScheduledMessage{
+				ID:        pointer.ToString("4"),
+				ChatID:    pointer.ToString("1"),
+				Text:      pointer.ToString("ping @bob"),
+				Author:    pointer.ToString("alice"),
+				ExpiresAt: pointer.ToTime(time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)),
+			})
+
+			assert.Nil(t, m.ID)
+			assert.Equal(t, "1", *m.ChatID)
+			assert.Equal(t, "ping @bob", *m.Text)
+			assert.Equal(t, "alice", *m.Author)
+			assert.Equal(t, time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC), *m.ExpiresAt)
+			assert.Equal(t, msmodel.Mentions{{Username: "bob", Offset: 5, Length: 4}}, m.Mentions)
+			return 1, nil
+		}).Times(1)
+
+	n, err := scheduled.New(s, mocks.NewMockChatService(ctrl), time.Second).DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	messageStore "github.com/Polilo-User/test-task-hitalent/internal/messages/store"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+func (s *Store) InsertScheduled(ctx context.Context, m *model.ScheduledMessage) (*model.ScheduledMessage, error) {
+	if err := s.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ListScheduled returns the messages of a chat waiting to be posted, in the order
+// they will be.
+func (s *Store) ListScheduled(ctx context.Context, chatID string) ([]model.ScheduledMessage, error) {
+	var ms []model.ScheduledMessage
+	err := s.db.WithContext(ctx).
+		Where("chat_id = ?", chatID).
+		Order("send_at, id").
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// UpdateScheduled replaces the text and send time of a scheduled message, leaving
+// those that are nil unchanged. It returns gorm.ErrRecordNotFound when the chat
+// has no such scheduled message, which includes one that was posted already.
+func (s *Store) UpdateScheduled(ctx context.Context, chatID, id string, text *string, sendAt *time.Time) (*model.ScheduledMessage, error) {
+	var m model.ScheduledMessage
+
+	res := s.db.WithContext(ctx).Raw(`
+		UPDATE scheduled_messages
+		SET text = COALESCE(?, text), send_at = COALESCE(?, send_at), updated_at = now()
+		WHERE id = ? AND chat_id = ?
+		RETURNING *`, text, sendAt, id, chatID).
+		Scan(&m)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &m, nil
+}
+
+// DeleteScheduled deletes a scheduled message. It returns gorm.ErrRecordNotFound
+// when the chat has no such scheduled message.
+func (s *Store) DeleteScheduled(ctx context.Context, chatID, id string) error {
+	res := s.db.WithContext(ctx).Delete(&model.ScheduledMessage{}, "id = ? AND chat_id = ?", id, chatID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Dispatch posts up to limit of the scheduled messages that are due, as the
+// messages build returns for them, and returns how many it posted. They are
+// claimed with FOR UPDATE SKIP LOCKED and deleted in the transaction posting
+// them, so each is posted exactly once however many dispatchers run, and cannot
+// be edited or cancelled while it is being posted.
+func (s *Store) Dispatch(ctx context.Context, limit int, build func(model.ScheduledMessage) *msmodel.Message) (int, error) {
+	var dispatched int
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var due []model.ScheduledMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("send_at <= now()").
+			Order("send_at, id").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(due))
+		for _, m := range due {
+			if err := messageStore.Insert(tx, build(m)); err != nil {
+				return err
+			}
+			ids = append(ids, *m.ID)
+		}
+		dispatched = len(due)
+
+		return tx.Delete(&model.ScheduledMessage{}, "id IN ?", ids).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return dispatched, nil
+}
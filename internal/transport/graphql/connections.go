@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/AlekSi/pointer"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxPageSize = 100
+
+	chatCursor    = "chat"
+	messageCursor = "message"
+)
+
+type pageInfoResolver struct {
+	hasNext   bool
+	endCursor *string
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.hasNext
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	return r.endCursor
+}
+
+type chatConnectionResolver struct {
+	edges   []*chatEdgeResolver
+	hasNext bool
+}
+
+func (r *chatConnectionResolver) Edges() []*chatEdgeResolver {
+	return r.edges
+}
+
+func (r *chatConnectionResolver) PageInfo() *pageInfoResolver {
+	res := &pageInfoResolver{hasNext: r.hasNext}
+	if len(r.edges) > 0 {
+		c := r.edges[len(r.edges)-1].Cursor()
+		res.endCursor = &c
+	}
+	return res
+}
+
+type chatEdgeResolver struct {
+	chat *chatResolver
+}
+
+func (r *chatEdgeResolver) Cursor() string {
+	return encodeCursor(chatCursor, string(r.chat.ID()))
+}
+
+func (r *chatEdgeResolver) Node() *chatResolver {
+	return r.chat
+}
+
+type messageConnectionResolver struct {
+	edges   []*messageEdgeResolver
+	hasNext bool
+}
+
+func (r *messageConnectionResolver) Edges() []*messageEdgeResolver {
+	return r.edges
+}
+
+func (r *messageConnectionResolver) PageInfo() *pageInfoResolver {
+	res := &pageInfoResolver{hasNext: r.hasNext}
+	if len(r.edges) > 0 {
+		c := r.edges[len(r.edges)-1].Cursor()
+		res.endCursor = &c
+	}
+	return res
+}
+
+type messageEdgeResolver struct {
+	message *messageResolver
+}
+
+// Cursor points at the seq of the message rather than its id, which is the order
+// messages are paged in.
+func (r *messageEdgeResolver) Cursor() string {
+	return encodeCursor(messageCursor, strconv.FormatInt(pointer.GetInt64(r.message.message.Seq), 10))
+}
+
+func (r *messageEdgeResolver) Node() *messageResolver {
+	return r.message
+}
+
+// parsePage validates the page size and decodes the cursor into the key of the
+// last item of the previous page.
+func parsePage(args pageArgs, kind string) (int64, string, error) {
+	if args.First < 1 || args.First > maxPageSize {
+		return 0, "", errors.ErrInvalidRequest.Wrap(errors.Error("first must be between 1 and 100"))
+	}
+
+	if args.After == nil {
+		return int64(args.First), "", nil
+	}
+
+	id, err := decodeCursor(kind, *args.After)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return int64(args.First), id, nil
+}
+
+// Cursors are opaque to clients; they only wrap the key of the item they point
+// at, the id of a chat or the seq of a message.
+func encodeCursor(kind, key string) string {
+	return base64.URLEncoding.EncodeToString([]byte(kind + ":" + key))
+}
+
+func decodeCursor(kind, cursor string) (string, error) {
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", errors.ErrInvalidRequest.Wrap(err)
+	}
+
+	id, ok := strings.CutPrefix(string(raw), kind+":")
+	if !ok || id == "" {
+		return "", errors.ErrInvalidRequest.Wrap(errors.Error("malformed cursor"))
+	}
+
+	return id, nil
+}
+
+func toTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	codeBadUserInput = "BAD_USER_INPUT"
+	codeNotFound     = "NOT_FOUND"
+	codeConflict     = "CONFLICT"
+	codeUpstream     = "UPSTREAM_FAILED"
+	codeInternal     = "INTERNAL_SERVER_ERROR"
+)
+
+// resolverError exposes a domain error to clients with a machine readable code
+// in the error extensions, mirroring the status codes used by the REST API.
+type resolverError struct {
+	code string
+	err  error
+}
+
+func (e *resolverError) Error() string {
+	return strings.Split(e.err.Error(), errors.ErrSeperator)[0]
+}
+
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func toError(ctx context.Context, err error) error {
+	logging.From(ctx).Error("error occurred in graphql resolver", zap.Error(err))
+
+	var code string
+
+	switch {
+	case errors.Is(err, errors.ErrInvalidRequest):
+		fallthrough
+	case errors.Is(err, commands.ErrUsage):
+		fallthrough
+	case errors.Is(err, errors.ErrValidation):
+		code = codeBadUserInput
+	case errors.Is(err, chats.ErrChatNotFound):
+		fallthrough
+	case errors.Is(err, errors.ErrNotFound):
+		code = codeNotFound
+	case errors.Is(err, chats.ErrLegalHold):
+		code = codeConflict
+	case errors.Is(err, commands.ErrCommandFailed):
+		code = codeUpstream
+	default:
+		code = codeInternal
+	}
+
+	return &resolverError{code: code, err: err}
+}
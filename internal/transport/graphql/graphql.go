@@ -0,0 +1,87 @@
+//go:generate mockgen -destination=./mocks/graphql_mock.go -package mocks github.com/Polilo-User/test-task-hitalent/internal/transport/graphql Chat,Message,Read
+
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"strings"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var schema string
+
+type Chat interface {
+	CreateChat(ctx context.Context, chat *chmodel.Chat) (*chmodel.Chat, error)
+	DeleteChat(ctx context.Context, id string) error
+	ListChats(ctx context.Context, limit int64, after string) ([]chmodel.Chat, error)
+	GetChatsByIDs(ctx context.Context, ids []string) ([]chmodel.Chat, error)
+}
+
+type Message interface {
+	CreateMessage(ctx context.Context, message *msmodel.Message) (*msmodel.Posted, error)
+	GetMessagesByChats(ctx context.Context, ids []string, limit int64, beforeSeq string) ([]msmodel.Message, error)
+}
+
+type Read interface {
+	Unread(ctx context.Context, username string, chatIDs []string) ([]rdmodel.Unread, error)
+}
+
+// UserHeader identifies the user making a request, as in the REST API.
+const UserHeader = "X-Username"
+
+// Handler serves GraphQL queries over chats and their messages.
+type Handler struct {
+	chat    Chat
+	message Message
+	read    Read
+	relay   *relay.Handler
+}
+
+// Option configures optional parts of the schema served by Handler.
+type Option func(*Handler)
+
+// WithReads tells identified users how much of each chat they have not read.
+func WithReads(r Read) Option {
+	return func(h *Handler) {
+		h.read = r
+	}
+}
+
+// New instantiates a new instance of Handler, failing if the schema does not
+// match the resolvers.
+func New(c Chat, m Message, opts ...Option) (*Handler, error) {
+	h := &Handler{
+		chat:    c,
+		message: m,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	s, err := graphql.ParseSchema(schema, &resolver{chat: c, message: m})
+	if err != nil {
+		return nil, err
+	}
+	h.relay = &relay.Handler{Schema: s}
+
+	return h, nil
+}
+
+// ServeHTTP executes a GraphQL request. Loaders are created per request so that
+// batching and caching never leak between requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimSpace(r.Header.Get(UserHeader))
+	ctx := withLoaders(r.Context(), newLoaders(h.chat, h.message, h.read, username))
+
+	h.relay.ServeHTTP(w, r.WithContext(ctx))
+}
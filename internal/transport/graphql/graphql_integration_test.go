@@ -0,0 +1,295 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatsModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	readsModel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	graphqltransport "github.com/Polilo-User/test-task-hitalent/internal/transport/graphql"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/graphql/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, h http.Handler, query string, variables map[string]interface{}) response {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+// message returns a message whose seq is its id.
+func message(id, chatID, text string) messagesModel.Message {
+	seq, _ := strconv.ParseInt(id, 10, 64)
+	return messagesModel.Message{
+		ID:     pointer.ToString(id),
+		ChatID: pointer.ToString(chatID),
+		Seq:    pointer.ToInt64(seq),
+		Text:   pointer.ToString(text),
+	}
+}
+
+func TestHandler_Chats_BatchesMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	h, err := graphqltransport.New(c, m)
+	require.NoError(t, err)
+
+	c.EXPECT().ListChats(gomock.Any(), int64(3), "").Return([]chatsModel.Chat{
+		{ID: pointer.ToString("2"), Title: pointer.ToString("second")},
+		{ID: pointer.ToString("1"), Title: pointer.ToString("first")},
+	}, nil).Times(1)
+
+	// Messages of both chats are loaded with one call, asking for one extra
+	// message per chat to tell whether there is a next page.
+	m.EXPECT().
+		GetMessagesByChats(gomock.Any(), gomock.InAnyOrder([]string{"1", "2"}), int64(2), "").
+		Return([]messagesModel.Message{
+			message("5", "1", "c"),
+			message("4", "1", "b"),
+			message("3", "2", "a"),
+		}, nil).Times(1)
+
+	res := execute(t, h, `{
+		chats(first: 2) {
+			edges { node { id title messages(first: 1) { edges { node { text } } pageInfo { hasNextPage } } } }
+			pageInfo { hasNextPage }
+		}
+	}`, nil)
+	require.Empty(t, res.Errors)
+
+	assert.JSONEq(t, `{"chats":{
+		"edges":[
+			{"node":{"id":"2","title":"second","messages":{"edges":[{"node":{"text":"a"}}],"pageInfo":{"hasNextPage":false}}}},
+			{"node":{"id":"1","title":"first","messages":{"edges":[{"node":{"text":"c"}}],"pageInfo":{"hasNextPage":true}}}}
+		],
+		"pageInfo":{"hasNextPage":false}
+	}}`, string(res.Data))
+}
+
+func TestHandler_Chats_BatchesUnread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	rd := mocks.NewMockRead(ctrl)
+
+	h, err := graphqltransport.New(c, mocks.NewMockMessage(ctrl), graphqltransport.WithReads(rd))
+	require.NoError(t, err)
+
+	c.EXPECT().ListChats(gomock.Any(), int64(21), "").Return([]chatsModel.Chat{
+		{ID: pointer.ToString("2"), Title: pointer.ToString("second")},
+		{ID: pointer.ToString("1"), Title: pointer.ToString("first")},
+	}, nil).Times(2)
+
+	// The unread messages of every chat are counted with one call.
+	rd.EXPECT().Unread(gomock.Any(), "alice", gomock.InAnyOrder([]string{"1", "2"})).Return([]readsModel.Unread{
+		{ChatID: pointer.ToString("1"), UnreadCount: pointer.ToInt64(3), FirstUnreadID: pointer.ToString("7")},
+		{ChatID: pointer.ToString("2"), UnreadCount: pointer.ToInt64(0)},
+	}, nil).Times(1)
+
+	body, err := json.Marshal(map[string]interface{}{"query": `{ chats { edges { node { id unreadCount firstUnreadId } } } }`})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(graphqltransport.UserHeader, "alice")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Empty(t, res.Errors)
+
+	assert.JSONEq(t, `{"chats":{"edges":[
+		{"node":{"id":"2","unreadCount":0,"firstUnreadId":null}},
+		{"node":{"id":"1","unreadCount":3,"firstUnreadId":"7"}}
+	]}}`, string(res.Data))
+
+	// Without a user there is nothing to count.
+	res = execute(t, h, `{ chats { edges { node { id unreadCount } } } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"chats":{"edges":[{"node":{"id":"2","unreadCount":null}},{"node":{"id":"1","unreadCount":null}}]}}`, string(res.Data))
+}
+
+func TestHandler_Chat_MessagesAfterCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	h, err := graphqltransport.New(c, m)
+	require.NoError(t, err)
+
+	c.EXPECT().GetChatsByIDs(gomock.Any(), []string{"1"}).Return([]chatsModel.Chat{
+		{ID: pointer.ToString("1"), Title: pointer.ToString("first")},
+	}, nil).Times(1)
+
+	m.EXPECT().GetMessagesByChats(gomock.Any(), []string{"1"}, int64(3), "").Return([]messagesModel.Message{
+		message("5", "1", "c"),
+		message("4", "1", "b"),
+	}, nil).Times(1)
+
+	const query = `query($after: String) {
+		chat(id: "1") { messages(first: 2, after: $after) { edges { cursor node { text } } pageInfo { endCursor } } }
+	}`
+
+	res := execute(t, h, query, nil)
+	require.Empty(t, res.Errors)
+
+	var first struct {
+		Chat struct {
+			Messages struct {
+				PageInfo struct {
+					EndCursor string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"messages"`
+		} `json:"chat"`
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &first))
+	require.NotEmpty(t, first.Chat.Messages.PageInfo.EndCursor)
+
+	c.EXPECT().GetChatsByIDs(gomock.Any(), []string{"1"}).Return([]chatsModel.Chat{
+		{ID: pointer.ToString("1"), Title: pointer.ToString("first")},
+	}, nil).Times(1)
+
+	m.EXPECT().GetMessagesByChats(gomock.Any(), []string{"1"}, int64(3), "4").Return(nil, nil).Times(1)
+
+	res = execute(t, h, query, map[string]interface{}{"after": first.Chat.Messages.PageInfo.EndCursor})
+	require.Empty(t, res.Errors)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(c *mocks.MockChat, m *mocks.MockMessage)
+		wantCode string
+	}{
+		{
+			name:     "page too large",
+			query:    `{ chats(first: 1000) { edges { cursor } } }`,
+			wantCode: "BAD_USER_INPUT",
+		},
+		{
+			name:     "malformed cursor",
+			query:    `{ chats(after: "nope") { edges { cursor } } }`,
+			wantCode: "BAD_USER_INPUT",
+		},
+		{
+			name:  "message in unknown chat",
+			query: `mutation { createMessage(chatId: "1", text: "hi") { message { id } } }`,
+			setup: func(c *mocks.MockChat, m *mocks.MockMessage) {
+				m.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil, chats.ErrChatNotFound).Times(1)
+			},
+			wantCode: "NOT_FOUND",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+
+			h, err := graphqltransport.New(c, m)
+			require.NoError(t, err)
+
+			if tt.setup != nil {
+				tt.setup(c, m)
+			}
+
+			res := execute(t, h, tt.query, nil)
+			require.Len(t, res.Errors, 1)
+			assert.Equal(t, tt.wantCode, res.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestHandler_CreateMessage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	h, err := graphqltransport.New(c, m)
+	require.NoError(t, err)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("hi"),
+			Author: pointer.ToString("bob"),
+		}).
+		Return(&messagesModel.Posted{Message: &messagesModel.Message{
+			ID:     pointer.ToString("7"),
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("hi"),
+			Author: pointer.ToString("bob"),
+		}}, nil).Times(1)
+
+	c.EXPECT().GetChatsByIDs(gomock.Any(), []string{"1"}).Return([]chatsModel.Chat{
+		{ID: pointer.ToString("1"), Title: pointer.ToString("first")},
+	}, nil).Times(1)
+
+	res := execute(t, h, `mutation { createMessage(chatId: "1", text: "hi", author: "bob") { message { id author chat { title } } command } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"createMessage":{"message":{"id":"7","author":"bob","chat":{"title":"first"}},"command":null}}`, string(res.Data))
+}
+
+func TestHandler_CreateMessage_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	h, err := graphqltransport.New(c, m)
+	require.NoError(t, err)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), gomock.Any()).
+		Return(&messagesModel.Posted{Command: "mute"}, nil).Times(1)
+
+	res := execute(t, h, `mutation { createMessage(chatId: "1", text: "/mute") { message { id } command replies { id } } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"createMessage":{"message":null,"command":"mute","replies":[]}}`, string(res.Data))
+}
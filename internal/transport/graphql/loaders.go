@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"context"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type contextKey int
+
+const loadersKey contextKey = iota
+
+// messagesKey identifies one page of messages of a chat. Keys sharing a page size
+// and cursor are resolved together with a single query.
+type messagesKey struct {
+	ChatID string
+	Limit  int64
+	Before string
+}
+
+type loaders struct {
+	chats    *dataloader.Loader[string, *chmodel.Chat]
+	messages *dataloader.Loader[messagesKey, []msmodel.Message]
+	// unread is nil when reads are not tracked or the user is not identified.
+	unread *dataloader.Loader[string, *rdmodel.Unread]
+}
+
+func newLoaders(c Chat, m Message, r Read, username string) *loaders {
+	l := &loaders{
+		chats:    dataloader.NewBatchedLoader(chatsBatch(c)),
+		messages: dataloader.NewBatchedLoader(messagesBatch(m)),
+	}
+	if r != nil && username != "" {
+		l.unread = dataloader.NewBatchedLoader(unreadBatch(r, username))
+	}
+	return l
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+func chatsBatch(c Chat) dataloader.BatchFunc[string, *chmodel.Chat] {
+	return func(ctx context.Context, ids []string) []*dataloader.Result[*chmodel.Chat] {
+		results := make([]*dataloader.Result[*chmodel.Chat], len(ids))
+
+		found, err := c.GetChatsByIDs(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*chmodel.Chat]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[string]*chmodel.Chat, len(found))
+		for i := range found {
+			byID[pointer.GetString(found[i].ID)] = &found[i]
+		}
+
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*chmodel.Chat]{Data: byID[id]}
+		}
+
+		return results
+	}
+}
+
+func messagesBatch(m Message) dataloader.BatchFunc[messagesKey, []msmodel.Message] {
+	return func(ctx context.Context, keys []messagesKey) []*dataloader.Result[[]msmodel.Message] {
+		results := make([]*dataloader.Result[[]msmodel.Message], len(keys))
+
+		type page struct {
+			limit  int64
+			before string
+		}
+
+		groups := map[page][]int{}
+		for i, k := range keys {
+			p := page{limit: k.Limit, before: k.Before}
+			groups[p] = append(groups[p], i)
+		}
+
+		for p, idx := range groups {
+			ids := make([]string, len(idx))
+			for j, i := range idx {
+				ids[j] = keys[i].ChatID
+			}
+
+			found, err := m.GetMessagesByChats(ctx, ids, p.limit, p.before)
+
+			byChat := map[string][]msmodel.Message{}
+			for _, msg := range found {
+				chatID := pointer.GetString(msg.ChatID)
+				byChat[chatID] = append(byChat[chatID], msg)
+			}
+
+			for _, i := range idx {
+				results[i] = &dataloader.Result[[]msmodel.Message]{Data: byChat[keys[i].ChatID], Error: err}
+			}
+		}
+
+		return results
+	}
+}
+
+func unreadBatch(r Read, username string) dataloader.BatchFunc[string, *rdmodel.Unread] {
+	return func(ctx context.Context, ids []string) []*dataloader.Result[*rdmodel.Unread] {
+		results := make([]*dataloader.Result[*rdmodel.Unread], len(ids))
+
+		found, err := r.Unread(ctx, username, ids)
+
+		byChat := make(map[string]*rdmodel.Unread, len(found))
+		for i := range found {
+			byChat[pointer.GetString(found[i].ChatID)] = &found[i]
+		}
+
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*rdmodel.Unread]{Data: byChat[id], Error: err}
+		}
+
+		return results
+	}
+}
@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/transport/graphql (interfaces: Chat,Message,Read)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	model0 "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model1 "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChat is a mock of Chat interface.
+type MockChat struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatMockRecorder
+}
+
+// MockChatMockRecorder is the mock recorder for MockChat.
+type MockChatMockRecorder struct {
+	mock *MockChat
+}
+
+// NewMockChat creates a new mock instance.
+func NewMockChat(ctrl *gomock.Controller) *MockChat {
+	mock := &MockChat{ctrl: ctrl}
+	mock.recorder = &MockChatMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChat) EXPECT() *MockChatMockRecorder {
+	return m.recorder
+}
+
+// CreateChat mocks base method.
+func (m *MockChat) CreateChat(arg0 context.Context, arg1 *model.Chat) (*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChat", arg0, arg1)
+	ret0, _ := ret[0].(*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChat indicates an expected call of CreateChat.
+func (mr *MockChatMockRecorder) CreateChat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChat", reflect.TypeOf((*MockChat)(nil).CreateChat), arg0, arg1)
+}
+
+// DeleteChat mocks base method.
+func (m *MockChat) DeleteChat(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChat", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChat indicates an expected call of DeleteChat.
+func (mr *MockChatMockRecorder) DeleteChat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChat)(nil).DeleteChat), arg0, arg1)
+}
+
+// GetChatsByIDs mocks base method.
+func (m *MockChat) GetChatsByIDs(arg0 context.Context, arg1 []string) ([]model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatsByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatsByIDs indicates an expected call of GetChatsByIDs.
+func (mr *MockChatMockRecorder) GetChatsByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatsByIDs", reflect.TypeOf((*MockChat)(nil).GetChatsByIDs), arg0, arg1)
+}
+
+// ListChats mocks base method.
+func (m *MockChat) ListChats(arg0 context.Context, arg1 int64, arg2 string) ([]model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockChatMockRecorder) ListChats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockChat)(nil).ListChats), arg0, arg1, arg2)
+}
+
+// MockMessage is a mock of Message interface.
+type MockMessage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageMockRecorder
+}
+
+// MockMessageMockRecorder is the mock recorder for MockMessage.
+type MockMessageMockRecorder struct {
+	mock *MockMessage
+}
+
+// NewMockMessage creates a new mock instance.
+func NewMockMessage(ctrl *gomock.Controller) *MockMessage {
+	mock := &MockMessage{ctrl: ctrl}
+	mock.recorder = &MockMessageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessage) EXPECT() *MockMessageMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockMessage) CreateMessage(arg0 context.Context, arg1 *model0.Message) (*model0.Posted, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", arg0, arg1)
+	ret0, _ := ret[0].(*model0.Posted)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockMessageMockRecorder) CreateMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessage)(nil).CreateMessage), arg0, arg1)
+}
+
+// GetMessagesByChats mocks base method.
+func (m *MockMessage) GetMessagesByChats(arg0 context.Context, arg1 []string, arg2 int64, arg3 string) ([]model0.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByChats", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model0.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByChats indicates an expected call of GetMessagesByChats.
+func (mr *MockMessageMockRecorder) GetMessagesByChats(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByChats", reflect.TypeOf((*MockMessage)(nil).GetMessagesByChats), arg0, arg1, arg2, arg3)
+}
+
+// MockRead is a mock of Read interface.
+type MockRead struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadMockRecorder
+}
+
+// MockReadMockRecorder is the mock recorder for MockRead.
+type MockReadMockRecorder struct {
+	mock *MockRead
+}
+
+// NewMockRead creates a new mock instance.
+func NewMockRead(ctrl *gomock.Controller) *MockRead {
+	mock := &MockRead{ctrl: ctrl}
+	mock.recorder = &MockReadMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRead) EXPECT() *MockReadMockRecorder {
+	return m.recorder
+}
+
+// Unread mocks base method.
+func (m *MockRead) Unread(arg0 context.Context, arg1 string, arg2 []string) ([]model1.Unread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unread", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model1.Unread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unread indicates an expected call of Unread.
+func (mr *MockReadMockRecorder) Unread(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unread", reflect.TypeOf((*MockRead)(nil).Unread), arg0, arg1, arg2)
+}
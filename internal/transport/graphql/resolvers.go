@@ -0,0 +1,243 @@
+package graphql
+
+import (
+	"context"
+	"math"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type pageArgs struct {
+	First int32
+	After *string
+}
+
+type resolver struct {
+	chat    Chat
+	message Message
+}
+
+func (r *resolver) Chat(ctx context.Context, args struct{ ID graphql.ID }) (*chatResolver, error) {
+	c, err := loadersFrom(ctx).chats.Load(ctx, string(args.ID))()
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	if c == nil {
+		return nil, nil
+	}
+
+	return &chatResolver{chat: c}, nil
+}
+
+func (r *resolver) Chats(ctx context.Context, args pageArgs) (*chatConnectionResolver, error) {
+	limit, after, err := parsePage(args, chatCursor)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	chats, err := r.chat.ListChats(ctx, limit+1, after)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	res := &chatConnectionResolver{hasNext: int64(len(chats)) > limit}
+	if res.hasNext {
+		chats = chats[:limit]
+	}
+
+	l := loadersFrom(ctx)
+	for i := range chats {
+		l.chats.Prime(ctx, pointer.GetString(chats[i].ID), &chats[i])
+		res.edges = append(res.edges, &chatEdgeResolver{chat: &chatResolver{chat: &chats[i]}})
+	}
+
+	return res, nil
+}
+
+func (r *resolver) CreateChat(ctx context.Context, args struct{ Title string }) (*chatResolver, error) {
+	c, err := r.chat.CreateChat(ctx, &chmodel.Chat{Title: pointer.ToString(args.Title)})
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	return &chatResolver{chat: c}, nil
+}
+
+func (r *resolver) DeleteChat(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := r.chat.DeleteChat(ctx, string(args.ID)); err != nil {
+		return false, toError(ctx, err)
+	}
+
+	return true, nil
+}
+
+func (r *resolver) CreateMessage(ctx context.Context, args struct {
+	ChatID graphql.ID
+	Text   string
+	Author *string
+}) (*createMessagePayloadResolver, error) {
+	posted, err := r.message.CreateMessage(ctx, &msmodel.Message{
+		ChatID: pointer.ToString(string(args.ChatID)),
+		Text:   pointer.ToString(args.Text),
+		Author: args.Author,
+	})
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	return &createMessagePayloadResolver{posted: posted}, nil
+}
+
+type chatResolver struct {
+	chat *chmodel.Chat
+}
+
+func (r *chatResolver) ID() graphql.ID {
+	return graphql.ID(pointer.GetString(r.chat.ID))
+}
+
+func (r *chatResolver) Title() string {
+	return pointer.GetString(r.chat.Title)
+}
+
+func (r *chatResolver) CreatedAt() *graphql.Time {
+	return toTime(r.chat.CreatedAt)
+}
+
+func (r *chatResolver) UnreadCount(ctx context.Context) (*int32, error) {
+	u, err := r.unread(ctx)
+	if err != nil || u == nil {
+		return nil, err
+	}
+
+	count := pointer.GetInt64(u.UnreadCount)
+	if count > math.MaxInt32 {
+		count = math.MaxInt32
+	}
+
+	return pointer.ToInt32(int32(count)), nil
+}
+
+func (r *chatResolver) FirstUnreadID(ctx context.Context) (*graphql.ID, error) {
+	u, err := r.unread(ctx)
+	if err != nil || u == nil || u.FirstUnreadID == nil {
+		return nil, err
+	}
+
+	id := graphql.ID(*u.FirstUnreadID)
+	return &id, nil
+}
+
+// unread returns how much of the chat the user has not read, loading it along
+// with the other chats of the query. It is nil when the user is not identified.
+func (r *chatResolver) unread(ctx context.Context) (*rdmodel.Unread, error) {
+	l := loadersFrom(ctx).unread
+	if l == nil {
+		return nil, nil
+	}
+
+	u, err := l.Load(ctx, pointer.GetString(r.chat.ID))()
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	return u, nil
+}
+
+func (r *chatResolver) Messages(ctx context.Context, args pageArgs) (*messageConnectionResolver, error) {
+	limit, before, err := parsePage(args, messageCursor)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	messages, err := loadersFrom(ctx).messages.Load(ctx, messagesKey{
+		ChatID: pointer.GetString(r.chat.ID),
+		Limit:  limit + 1,
+		Before: before,
+	})()
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	res := &messageConnectionResolver{hasNext: int64(len(messages)) > limit}
+	if res.hasNext {
+		messages = messages[:limit]
+	}
+
+	for i := range messages {
+		res.edges = append(res.edges, &messageEdgeResolver{message: &messageResolver{message: &messages[i]}})
+	}
+
+	return res, nil
+}
+
+type createMessagePayloadResolver struct {
+	posted *msmodel.Posted
+}
+
+func (r *createMessagePayloadResolver) Message() *messageResolver {
+	if r.posted.Message == nil {
+		return nil
+	}
+	return &messageResolver{message: r.posted.Message}
+}
+
+func (r *createMessagePayloadResolver) Command() *string {
+	if r.posted.Command == "" {
+		return nil
+	}
+	return pointer.ToString(r.posted.Command)
+}
+
+func (r *createMessagePayloadResolver) Replies() []*messageResolver {
+	replies := make([]*messageResolver, 0, len(r.posted.Replies))
+	for i := range r.posted.Replies {
+		replies = append(replies, &messageResolver{message: &r.posted.Replies[i]})
+	}
+	return replies
+}
+
+type messageResolver struct {
+	message *msmodel.Message
+}
+
+func (r *messageResolver) ID() graphql.ID {
+	return graphql.ID(pointer.GetString(r.message.ID))
+}
+
+func (r *messageResolver) ChatID() graphql.ID {
+	return graphql.ID(pointer.GetString(r.message.ChatID))
+}
+
+func (r *messageResolver) Chat(ctx context.Context) (*chatResolver, error) {
+	c, err := loadersFrom(ctx).chats.Load(ctx, pointer.GetString(r.message.ChatID))()
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	if c == nil {
+		return nil, nil
+	}
+
+	return &chatResolver{chat: c}, nil
+}
+
+func (r *messageResolver) Text() string {
+	return pointer.GetString(r.message.Text)
+}
+
+func (r *messageResolver) Author() *string {
+	return r.message.Author
+}
+
+func (r *messageResolver) System() bool {
+	return pointer.GetBool(r.message.System)
+}
+
+func (r *messageResolver) CreatedAt() *graphql.Time {
+	return toTime(r.message.CreatedAt)
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"github.com/AlekSi/pointer"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *Server) CreateChat(ctx context.Context, req *pb.CreateChatRequest) (*pb.Chat, error) {
+	c, err := s.chat.CreateChat(ctx, &model.Chat{Title: pointer.ToString(req.GetTitle())})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toChat(c), nil
+}
+
+func (s *Server) GetChat(ctx context.Context, req *pb.GetChatRequest) (*pb.Chat, error) {
+	limit, err := limitOf(req.GetLimit())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	c, err := s.chat.GetChat(ctx, req.GetId(), limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toChat(c), nil
+}
+
+func (s *Server) DeleteChat(ctx context.Context, req *pb.DeleteChatRequest) (*emptypb.Empty, error) {
+	if err := s.chat.DeleteChat(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func limitOf(limit int64) (int64, error) {
+	switch {
+	case limit < 0:
+		return 0, errors.ErrInvalidRequest.Wrap(errors.Error("limit must not be negative"))
+	case limit == 0:
+		return defaultLimit, nil
+	default:
+		return limit, nil
+	}
+}
+
+func toChat(c *model.Chat) *pb.Chat {
+	res := &pb.Chat{
+		Id:        pointer.GetString(c.ID),
+		Title:     pointer.GetString(c.Title),
+		CreatedAt: toTimestamp(c.CreatedAt),
+	}
+
+	for i := range c.Messages {
+		res.Messages = append(res.Messages, toMessage(&c.Messages[i]))
+	}
+
+	return res
+}
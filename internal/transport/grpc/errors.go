@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps domain errors onto grpc status codes, mirroring the status codes
+// used by the REST API.
+func toStatus(err error) error {
+	var code codes.Code
+
+	switch {
+	case errors.Is(err, errors.ErrInvalidRequest):
+		fallthrough
+	case errors.Is(err, commands.ErrUsage):
+		fallthrough
+	case errors.Is(err, errors.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, chats.ErrChatNotFound):
+		fallthrough
+	case errors.Is(err, errors.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, chats.ErrLegalHold):
+		code = codes.FailedPrecondition
+	case errors.Is(err, commands.ErrCommandFailed):
+		code = codes.Unavailable
+	default:
+		code = codes.Internal
+	}
+
+	return status.Error(code, strings.Split(err.Error(), errors.ErrSeperator)[0])
+}
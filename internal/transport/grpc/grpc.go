@@ -0,0 +1,55 @@
+//go:generate mockgen -destination=./mocks/grpc_mock.go -package mocks github.com/Polilo-User/test-task-hitalent/internal/transport/grpc Chat,Message,Broker
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"google.golang.org/grpc"
+)
+
+const defaultLimit = 20
+
+type Chat interface {
+	CreateChat(ctx context.Context, chat *chmodel.Chat) (*chmodel.Chat, error)
+	GetChat(ctx context.Context, id string, limit int64, opts ...chats.GetChatOption) (*chmodel.Chat, error)
+	DeleteChat(ctx context.Context, id string) error
+	ChatExist(ctx context.Context, id string) error
+}
+
+type Message interface {
+	CreateMessage(ctx context.Context, message *msmodel.Message) (*msmodel.Posted, error)
+	GetMessagesByChat(ctx context.Context, id string, limit int64) ([]msmodel.Message, error)
+}
+
+type Broker interface {
+	Subscribe(chatID string) (<-chan events.Event, func())
+}
+
+// Server implements pb.ChatServiceServer on top of the chat and message services.
+type Server struct {
+	pb.UnimplementedChatServiceServer
+
+	chat    Chat
+	message Message
+	broker  Broker
+}
+
+func New(c Chat, m Message, b Broker) *Server {
+	return &Server{
+		chat:    c,
+		message: m,
+		broker:  b,
+	}
+}
+
+// Register registers the chat service with a grpc server.
+func (s *Server) Register(srv *grpc.Server) {
+	pb.RegisterChatServiceServer(srv, s)
+}
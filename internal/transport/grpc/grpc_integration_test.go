@@ -0,0 +1,285 @@
+package grpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatsModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	grpctransport "github.com/Polilo-User/test-task-hitalent/internal/transport/grpc"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T, c grpctransport.Chat, m grpctransport.Message, b grpctransport.Broker) pb.ChatServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	srv := grpc.NewServer()
+	grpctransport.New(c, m, b).Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewChatServiceClient(conn)
+}
+
+func TestServer_GetChat_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatsModel.Chat{
+		ID:    pointer.ToString("1"),
+		Title: pointer.ToString("test"),
+		Messages: []messagesModel.Message{
+			{ID: pointer.ToString("2"), ChatID: pointer.ToString("1"), Text: pointer.ToString("hi")},
+		},
+	}, nil).Times(1)
+
+	res, err := client.GetChat(context.Background(), &pb.GetChatRequest{Id: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "test", res.GetTitle())
+	require.Len(t, res.GetMessages(), 1)
+	assert.Equal(t, "hi", res.GetMessages()[0].GetText())
+}
+
+func TestServer_GetChat_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int64
+		err      error
+		wantCode codes.Code
+	}{
+		{
+			name:     "negative limit",
+			limit:    -1,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "chat not found",
+			err:      chats.ErrChatNotFound,
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			b := mocks.NewMockBroker(ctrl)
+
+			client := newClient(t, c, m, b)
+
+			if tt.err != nil {
+				c.EXPECT().GetChat(gomock.Any(), "1", gomock.Any()).Return(nil, tt.err).Times(1)
+			}
+
+			res, err := client.GetChat(context.Background(), &pb.GetChatRequest{Id: "1", Limit: tt.limit})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Nil(t, res)
+		})
+	}
+}
+
+func TestServer_CreateMessage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("hello"),
+		}).
+		Return(&messagesModel.Posted{Message: &messagesModel.Message{
+			ID:     pointer.ToString("3"),
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("hello"),
+		}}, nil).Times(1)
+
+	res, err := client.CreateMessage(context.Background(), &pb.CreateMessageRequest{ChatId: "1", Text: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", res.GetMessage().GetId())
+	assert.Empty(t, res.GetCommand())
+}
+
+func TestServer_CreateMessage_Command(t *testing.T) {
+	tests := []struct {
+		name        string
+		replies     []messagesModel.Message
+		wantReplies []string
+	}{
+		{
+			name:        "replied",
+			replies:     []messagesModel.Message{{ID: pointer.ToString("4"), Text: pointer.ToString("Poll: Lunch?"), System: pointer.ToBool(true)}},
+			wantReplies: []string{"4"},
+		},
+		{
+			name: "no reply",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			b := mocks.NewMockBroker(ctrl)
+
+			client := newClient(t, c, m, b)
+
+			m.EXPECT().
+				CreateMessage(gomock.Any(), gomock.Any()).
+				Return(&messagesModel.Posted{Command: "poll", Replies: tt.replies}, nil).Times(1)
+
+			res, err := client.CreateMessage(context.Background(), &pb.CreateMessageRequest{ChatId: "1", Text: "/poll Lunch? | Pizza | Sushi"})
+			require.NoError(t, err)
+			assert.Nil(t, res.GetMessage())
+			assert.Equal(t, "poll", res.GetCommand())
+
+			var replies []string
+			for _, r := range res.GetReplies() {
+				assert.True(t, r.GetSystem())
+				replies = append(replies, r.GetId())
+			}
+			assert.Equal(t, tt.wantReplies, replies)
+		})
+	}
+}
+
+func TestServer_CreateMessage_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	m.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil, commands.ErrUsage).Times(1)
+
+	_, err := client.CreateMessage(context.Background(), &pb.CreateMessageRequest{ChatId: "1", Text: "/poll"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_SubscribeChat_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	ch := make(chan events.Event, 2)
+	ch <- events.Event{ID: "1", Type: events.MessageCreated, ChatID: "1", Data: []byte(`{"id":"2"}`)}
+	ch <- events.Event{ID: "2", Type: events.ChatDeleted, ChatID: "1", Data: []byte(`{"id":"1"}`)}
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+	b.EXPECT().Subscribe("1").Return(ch, func() {}).Times(1)
+
+	stream, err := client.SubscribeChat(context.Background(), &pb.SubscribeChatRequest{ChatId: "1"})
+	require.NoError(t, err)
+
+	e, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, string(events.MessageCreated), e.GetType())
+	assert.JSONEq(t, `{"id":"2"}`, string(e.GetData()))
+
+	e, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, string(events.ChatDeleted), e.GetType())
+
+	// The stream ends once the chat is gone.
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_SubscribeChat_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(chats.ErrChatNotFound).Times(1)
+
+	stream, err := client.SubscribeChat(context.Background(), &pb.SubscribeChatRequest{ChatId: "1"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_SubscribeChat_DataLoss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	b := mocks.NewMockBroker(ctrl)
+
+	client := newClient(t, c, m, b)
+
+	ch := make(chan events.Event, 1)
+	ch <- events.Event{ID: "1", Type: events.MessageCreated, ChatID: "1", Data: []byte(`{"id":"2"}`)}
+	close(ch)
+
+	c.EXPECT().ChatExist(gomock.Any(), "1").Return(nil).Times(1)
+	b.EXPECT().Subscribe("1").Return(ch, func() {}).Times(1)
+
+	stream, err := client.SubscribeChat(context.Background(), &pb.SubscribeChatRequest{ChatId: "1"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// The broker closed the subscription, so events were missed.
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+}
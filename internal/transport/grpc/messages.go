@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"github.com/AlekSi/pointer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) CreateMessage(ctx context.Context, req *pb.CreateMessageRequest) (*pb.CreateMessageResponse, error) {
+	m := &model.Message{
+		ChatID: pointer.ToString(req.GetChatId()),
+		Text:   pointer.ToString(req.GetText()),
+	}
+	if req.GetAuthor() != "" {
+		m.Author = pointer.ToString(req.GetAuthor())
+	}
+
+	posted, err := s.message.CreateMessage(ctx, m)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.CreateMessageResponse{Command: posted.Command}
+	if posted.Message != nil {
+		res.Message = toMessage(posted.Message)
+	}
+	for i := range posted.Replies {
+		res.Replies = append(res.Replies, toMessage(&posted.Replies[i]))
+	}
+
+	return res, nil
+}
+
+func (s *Server) ListMessages(ctx context.Context, req *pb.ListMessagesRequest) (*pb.ListMessagesResponse, error) {
+	limit, err := limitOf(req.GetLimit())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.chat.ChatExist(ctx, req.GetChatId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	messages, err := s.message.GetMessagesByChat(ctx, req.GetChatId(), limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.ListMessagesResponse{}
+	for i := range messages {
+		res.Messages = append(res.Messages, toMessage(&messages[i]))
+	}
+
+	return res, nil
+}
+
+func toMessage(m *model.Message) *pb.Message {
+	return &pb.Message{
+		Id:        pointer.GetString(m.ID),
+		ChatId:    pointer.GetString(m.ChatID),
+		Text:      pointer.GetString(m.Text),
+		Author:    pointer.GetString(m.Author),
+		System:    pointer.GetBool(m.System),
+		CreatedAt: toTimestamp(m.CreatedAt),
+	}
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
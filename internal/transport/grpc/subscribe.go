@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) SubscribeChat(req *pb.SubscribeChatRequest, stream pb.ChatService_SubscribeChatServer) error {
+	ctx := stream.Context()
+
+	if err := s.chat.ChatExist(ctx, req.GetChatId()); err != nil {
+		return toStatus(err)
+	}
+
+	sub, cancel := s.broker.Subscribe(req.GetChatId())
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub:
+			if !ok {
+				return status.Error(codes.DataLoss, "subscriber fell behind, events were missed")
+			}
+			if err := stream.Send(toEvent(e)); err != nil {
+				return err
+			}
+			if e.Type == events.ChatDeleted {
+				return nil
+			}
+		}
+	}
+}
+
+func toEvent(e events.Event) *pb.Event {
+	return &pb.Event{
+		Id:         e.ID,
+		Type:       string(e.Type),
+		ChatId:     e.ChatID,
+		Data:       e.Data,
+		OccurredAt: timestamppb.New(e.OccurredAt),
+	}
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ErrTooManyFiles is returned when more files are uploaded at once than allowed.
+const ErrTooManyFiles = errors.Error("too_many_files: at most 10 files can be uploaded at once")
+
+// maxUploadFiles is the number of files accepted by one upload request.
+const maxUploadFiles = 10
+
+// uploadOverhead is the room left in an upload request for its multipart framing.
+const uploadOverhead = 1 << 20
+
+// addAttachmentRoutes registers the routes carrying file contents on their own
+// subrouter. Uploads are multipart forms and downloads are the files themselves,
+// so content negotiation is skipped.
+func (s *Server) addAttachmentRoutes(r *mux.Router, validate mux.MiddlewareFunc) {
+	if s.attachment == nil {
+		return
+	}
+
+	r = r.PathPrefix("/v1").Subrouter()
+	r.Use(s.idempotent(handleError, maxUploadFiles*s.attachmentMaxSize+uploadOverhead), validate)
+
+	r.HandleFunc("/chats/{id}/messages/{message_id}/attachments/", s.uploadAttachments).Methods(http.MethodPost)
+	r.HandleFunc("/attachments/{id}/content", s.downloadAttachment).Methods(http.MethodGet)
+	r.HandleFunc("/attachments/{id}/thumbnails/{name}", s.downloadThumbnail).Methods(http.MethodGet)
+}
+
+// uploadAttachments attaches every file of the multipart/form-data body sent in a
+// "file" field to the message. Either all files are attached or none are.
+func (s *Server) uploadAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var uploaded []model.Attachment
+	fail := func(err error) {
+		s.discardAttachments(ctx, uploaded)
+		handleError(ctx, w, err)
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fail(errors.ErrInvalidRequest.Wrap(err))
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		if len(uploaded) == maxUploadFiles {
+			fail(ErrTooManyFiles)
+			return
+		}
+
+		att, err := s.attachment.Upload(ctx, vars["id"], vars["message_id"], part.FileName(), part)
+		if err != nil {
+			fail(err)
+			return
+		}
+		uploaded = append(uploaded, *att)
+	}
+
+	if len(uploaded) == 0 {
+		handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("no file was uploaded")))
+		return
+	}
+
+	writeBody(ctx, w, http.StatusCreated, struct {
+		Data []model.Attachment `json:"data"`
+	}{
+		Data: uploaded,
+	})
+}
+
+// discardAttachments deletes the attachments uploaded by a request that failed.
+func (s *Server) discardAttachments(ctx context.Context, atts []model.Attachment) {
+	ctx = context.WithoutCancel(ctx)
+
+	for _, att := range atts {
+		if err := s.attachment.DeleteAttachment(ctx, pointer.GetString(att.ID)); err != nil {
+			logging.From(ctx).Error("failed to delete attachment of failed upload", zap.Error(err))
+		}
+	}
+}
+
+// downloadAttachment serves the content of an attachment through a signed link.
+// Range and conditional requests are answered from the blob store without
+// reading the parts of the file that are not requested.
+func (s *Server) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	att, content, err := s.attachment.Open(ctx, mux.Vars(r)["id"], q.Get("expires"), q.Get("signature"))
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", pointer.GetString(att.ContentType))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": pointer.GetString(att.Name)}))
+	w.Header().Set("ETag", `"`+pointer.GetString(att.SHA256)+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+
+	http.ServeContent(w, r, "", pointer.GetTime(att.CreatedAt), content)
+}
+
+// downloadThumbnail serves a thumbnail of an image attachment through a signed
+// link. Thumbnails are shown inline, as previews.
+func (s *Server) downloadThumbnail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+
+	t, content, err := s.attachment.OpenThumbnail(ctx, vars["id"], vars["name"], q.Get("expires"), q.Get("signature"))
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", pointer.GetString(t.ContentType))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+
+	http.ServeContent(w, r, "", time.Time{}, content)
+}
+
+func (s *Server) listAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	atts, err := s.attachment.ListAttachments(ctx, vars["id"], vars["message_id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, atts)
+}
+
+func (s *Server) getAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	att, err := s.attachment.GetAttachment(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, att)
+}
+
+// deleteAttachment refuses to delete the attachments of a chat under legal hold.
+// The hold is checked here since the chat service depends on attachments.
+func (s *Server) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := mux.Vars(r)["id"]
+
+	att, err := s.attachment.GetAttachment(ctx, id)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	if err := s.chat.CheckLegalHold(ctx, pointer.GetString(att.ChatID)); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	if err := s.attachment.DeleteAttachment(ctx, id); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, "deleted")
+}
@@ -0,0 +1,428 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments"
+	attachmentModel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const attachmentsURL = "/v1/chats/1/messages/2/attachments/"
+
+func serveAttachment(t *testing.T, c *mocks.MockChat, a *mocks.MockAttachment, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	if c == nil {
+		c = mocks.NewMockChat(ctrl)
+	}
+	ht := httptransport.New(c, mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl),
+		httptransport.WithAttachments(a, 1<<20),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+// uploadRequest builds a multipart upload of the given files, keyed by name.
+func uploadRequest(t *testing.T, files ...string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("comment", "ignored"))
+	for _, name := range files {
+		fw, err := mw.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("content of " + name))
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, attachmentsURL, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	return req
+}
+
+func uploaded(ctx context.Context, chatID, messageID, name string, r io.Reader) (*attachmentModel.Attachment, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachmentModel.Attachment{
+		ID:        pointer.ToString(name),
+		MessageID: pointer.ToString(messageID),
+		ChatID:    pointer.ToString(chatID),
+		Name:      pointer.ToString(name),
+		Size:      pointer.ToInt64(int64(len(content))),
+	}, nil
+}
+
+func TestServer_UploadAttachments_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := mocks.NewMockAttachment(ctrl)
+	a.EXPECT().Upload(gomock.Any(), "1", "2", "a.txt", gomock.Any()).DoAndReturn(uploaded).Times(1)
+	a.EXPECT().Upload(gomock.Any(), "1", "2", "b.png", gomock.Any()).DoAndReturn(uploaded).Times(1)
+
+	w := serveAttachment(t, nil, a, uploadRequest(t, "a.txt", "b.png"))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"data":[
+		{"id":"a.txt","message_id":"2","chat_id":"1","name":"a.txt","size":16,"content_type":null,"sha256":null,"created_at":null},
+		{"id":"b.png","message_id":"2","chat_id":"1","name":"b.png","size":16,"content_type":null,"sha256":null,"created_at":null}
+	]}`, w.Body.String())
+}
+
+func TestServer_UploadAttachments_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      func(t *testing.T) *http.Request
+		setup    func(a *mocks.MockAttachment)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "not multipart",
+			req: func(t *testing.T) *http.Request {
+				req, err := http.NewRequest(http.MethodPost, attachmentsURL, strings.NewReader(`{}`))
+				require.NoError(t, err)
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "no files",
+			req:      func(t *testing.T) *http.Request { return uploadRequest(t) },
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"err_validation: failed validation"}`,
+		},
+		{
+			name: "too many files",
+			req: func(t *testing.T) *http.Request {
+				return uploadRequest(t, "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11")
+			},
+			setup: func(a *mocks.MockAttachment) {
+				a.EXPECT().Upload(gomock.Any(), "1", "2", gomock.Any(), gomock.Any()).DoAndReturn(uploaded).Times(10)
+				a.EXPECT().DeleteAttachment(gomock.Any(), gomock.Any()).Return(nil).Times(10)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"too_many_files: at most 10 files can be uploaded at once"}`,
+		},
+		{
+			name: "second file too large",
+			req:  func(t *testing.T) *http.Request { return uploadRequest(t, "a.txt", "b.txt") },
+			setup: func(a *mocks.MockAttachment) {
+				gomock.InOrder(
+					a.EXPECT().Upload(gomock.Any(), "1", "2", "a.txt", gomock.Any()).DoAndReturn(uploaded).Times(1),
+					a.EXPECT().Upload(gomock.Any(), "1", "2", "b.txt", gomock.Any()).Return(nil, attachments.ErrAttachmentTooLarge).Times(1),
+					a.EXPECT().DeleteAttachment(gomock.Any(), "a.txt").Return(nil).Times(1),
+				)
+			},
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: `{"error":"attachment_too_large: the attachment exceeds the maximum size"}`,
+		},
+		{
+			name: "message not found",
+			req:  func(t *testing.T) *http.Request { return uploadRequest(t, "a.txt") },
+			setup: func(a *mocks.MockAttachment) {
+				a.EXPECT().Upload(gomock.Any(), "1", "2", "a.txt", gomock.Any()).Return(nil, attachments.ErrMessageNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"message_not_found: message not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			a := mocks.NewMockAttachment(ctrl)
+			if tt.setup != nil {
+				tt.setup(a)
+			}
+
+			w := serveAttachment(t, nil, a, tt.req(t))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_ListAttachments_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := mocks.NewMockAttachment(ctrl)
+	a.EXPECT().ListAttachments(gomock.Any(), "1", "2").Return([]attachmentModel.Attachment{
+		{ID: pointer.ToString("3"), Name: pointer.ToString("a.txt"), URL: pointer.ToString("/v1/attachments/3/content?expires=1&signature=ab")},
+	}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodGet, attachmentsURL, nil)
+	require.NoError(t, err)
+
+	w := serveAttachment(t, nil, a, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":"3","message_id":null,"chat_id":null,"name":"a.txt","size":null,"content_type":null,
+		"sha256":null,"created_at":null,"url":"/v1/attachments/3/content?expires=1&signature=ab"}]}`, w.Body.String())
+}
+
+func TestServer_DeleteAttachment_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	a := mocks.NewMockAttachment(ctrl)
+
+	gomock.InOrder(
+		a.EXPECT().GetAttachment(gomock.Any(), "3").Return(&attachmentModel.Attachment{ID: pointer.ToString("3"), ChatID: pointer.ToString("1")}, nil).Times(1),
+		c.EXPECT().CheckLegalHold(gomock.Any(), "1").Return(nil).Times(1),
+		a.EXPECT().DeleteAttachment(gomock.Any(), "3").Return(nil).Times(1),
+	)
+
+	req, err := http.NewRequest(http.MethodDelete, "/v1/attachments/3", nil)
+	require.NoError(t, err)
+
+	w := serveAttachment(t, c, a, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_DeleteAttachment_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(c *mocks.MockChat, a *mocks.MockAttachment)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "not found",
+			setup: func(c *mocks.MockChat, a *mocks.MockAttachment) {
+				a.EXPECT().GetAttachment(gomock.Any(), "3").Return(nil, attachments.ErrAttachmentNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"attachment_not_found: attachment not found"}`,
+		},
+		{
+			name: "legal hold",
+			setup: func(c *mocks.MockChat, a *mocks.MockAttachment) {
+				a.EXPECT().GetAttachment(gomock.Any(), "3").Return(&attachmentModel.Attachment{ID: pointer.ToString("3"), ChatID: pointer.ToString("1")}, nil).Times(1)
+				c.EXPECT().CheckLegalHold(gomock.Any(), "1").Return(chats.ErrLegalHold).Times(1)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"error":"legal_hold: chat is under legal hold"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			a := mocks.NewMockAttachment(ctrl)
+			tt.setup(c, a)
+
+			req, err := http.NewRequest(http.MethodDelete, "/v1/attachments/3", nil)
+			require.NoError(t, err)
+
+			w := serveAttachment(t, c, a, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_DownloadAttachment_Success(t *testing.T) {
+	att := &attachmentModel.Attachment{
+		ID:          pointer.ToString("3"),
+		Name:        pointer.ToString("notes ü.txt"),
+		ContentType: pointer.ToString("text/plain; charset=utf-8"),
+		SHA256:      pointer.ToString("abc"),
+		CreatedAt:   pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name      string
+		header    map[string]string
+		wantCode  int
+		wantBody  string
+		wantRange string
+	}{
+		{
+			name:     "whole file",
+			wantCode: http.StatusOK,
+			wantBody: "0123456789",
+		},
+		{
+			name:      "range",
+			header:    map[string]string{"Range": "bytes=2-5"},
+			wantCode:  http.StatusPartialContent,
+			wantBody:  "2345",
+			wantRange: "bytes 2-5/10",
+		},
+		{
+			name:     "not modified",
+			header:   map[string]string{"If-None-Match": `"abc"`},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:      "unsatisfiable range",
+			header:    map[string]string{"Range": "bytes=20-"},
+			wantCode:  http.StatusRequestedRangeNotSatisfiable,
+			wantBody:  "invalid range: failed to overlap\n",
+			wantRange: "bytes */10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			a := mocks.NewMockAttachment(ctrl)
+			a.EXPECT().Open(gomock.Any(), "3", "123", "ab").
+				Return(att, nopSeekCloser{strings.NewReader("0123456789")}, nil).Times(1)
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/attachments/3/content?expires=123&signature=ab", nil)
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			w := serveAttachment(t, nil, a, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, tt.wantRange, w.Header().Get("Content-Range"))
+			if tt.wantCode < http.StatusMultipleChoices {
+				assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename*=utf-8''notes%20%C3%BC.txt`, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestServer_DownloadAttachment_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "invalid signature",
+			err:      attachments.ErrInvalidSignature,
+			wantCode: http.StatusForbidden,
+			wantBody: `{"error":"invalid_signature: the download link is invalid"}`,
+		},
+		{
+			name:     "expired",
+			err:      attachments.ErrLinkExpired,
+			wantCode: http.StatusForbidden,
+			wantBody: `{"error":"link_expired: the download link has expired"}`,
+		},
+		{
+			name:     "not found",
+			err:      attachments.ErrAttachmentNotFound,
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"attachment_not_found: attachment not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			a := mocks.NewMockAttachment(ctrl)
+			a.EXPECT().Open(gomock.Any(), "3", "123", "ab").Return(nil, nil, tt.err).Times(1)
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/attachments/3/content?expires=123&signature=ab", nil)
+			require.NoError(t, err)
+
+			w := serveAttachment(t, nil, a, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_DownloadThumbnail_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := mocks.NewMockAttachment(ctrl)
+	a.EXPECT().OpenThumbnail(gomock.Any(), "3", "small", "123", "ab").Return(&attachmentModel.Thumbnail{
+		Name:        pointer.ToString("small"),
+		ContentType: pointer.ToString("image/png"),
+	}, nopSeekCloser{strings.NewReader("png")}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/attachments/3/thumbnails/small?expires=123&signature=ab", nil)
+	require.NoError(t, err)
+
+	w := serveAttachment(t, nil, a, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "png", w.Body.String())
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestServer_DownloadThumbnail_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := mocks.NewMockAttachment(ctrl)
+	a.EXPECT().OpenThumbnail(gomock.Any(), "3", "large", "123", "ab").Return(nil, nil, attachments.ErrAttachmentNotFound).Times(1)
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/attachments/3/thumbnails/large?expires=123&signature=ab", nil)
+	require.NoError(t, err)
+
+	w := serveAttachment(t, nil, a, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"attachment_not_found: attachment not found"}`, w.Body.String())
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
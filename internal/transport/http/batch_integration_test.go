@@ -0,0 +1,143 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const batchURL = "/v1/chats/1/messages:batch"
+
+func TestServer_CreateMessages_Success(t *testing.T) {
+	want := []messagesModel.Message{
+		{Text: pointer.ToString("first"), CreatedAt: pointer.ToTime(time.Date(2019, 5, 1, 8, 0, 0, 0, time.UTC))},
+		{Text: pointer.ToString("second"), Author: pointer.ToString("bob")},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		atomic      bool
+	}{
+		{
+			name:        "json array",
+			url:         batchURL,
+			contentType: "application/json",
+			body:        `[{"text":"first","created_at":"2019-05-01T08:00:00Z"},{"text":"second","author":"bob"}]`,
+		},
+		{
+			name:        "ndjson",
+			url:         batchURL + "?atomic=true",
+			contentType: "application/x-ndjson",
+			body:        "{\"text\":\"first\",\"created_at\":\"2019-05-01T08:00:00Z\"}\n\n{\"text\":\"second\",\"author\":\"bob\"}\n",
+			atomic:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockMessage(ctrl)
+			m.EXPECT().CreateMessages(gomock.Any(), "1", want, tt.atomic).Return(&messagesModel.Batch{
+				Inserted: 2,
+				Results: []messagesModel.BatchResult{
+					{Index: 0, ID: pointer.ToString("10")},
+					{Index: 1, ID: pointer.ToString("11")},
+				},
+			}, nil).Times(1)
+
+			w := serveEncoded(t, mocks.NewMockChat(ctrl), m, http.MethodPost, tt.url, tt.contentType, "", []byte(tt.body))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"data":{"inserted":2,"failed":0,"results":[{"index":0,"id":"10","error":null},{"index":1,"id":"11","error":null}]}}`, w.Body.String())
+		})
+	}
+}
+
+func TestServer_CreateMessages_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMessage(ctrl)
+	m.EXPECT().CreateMessages(gomock.Any(), "1", gomock.Any(), true).Return(&messagesModel.Batch{
+		Failed: 1,
+		Results: []messagesModel.BatchResult{
+			{Index: 0},
+			{Index: 1, Error: pointer.ToString("text is required")},
+		},
+	}, messages.ErrBatchRejected).Times(1)
+
+	w := serveEncoded(t, mocks.NewMockChat(ctrl), m, http.MethodPost, batchURL+"?atomic=1", "application/json", "", []byte(`[{"text":"ok"},{"text":""}]`))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var res struct {
+		Error string              `json:"error"`
+		Data  messagesModel.Batch `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "batch_rejected: batch contains invalid messages", res.Error)
+	assert.Equal(t, "text is required", pointer.GetString(res.Data.Results[1].Error))
+}
+
+func TestServer_CreateMessages_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		body     string
+		setup    func(m *mocks.MockMessage)
+		wantCode int
+	}{
+		{
+			name:     "not an array",
+			url:      batchURL,
+			body:     `{"text":"hi"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid atomic parameter",
+			url:      batchURL + "?atomic=maybe",
+			body:     `[{"text":"hi"}]`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "too many messages",
+			url:  batchURL,
+			body: `[{"text":"hi"}]`,
+			setup: func(m *mocks.MockMessage) {
+				m.EXPECT().CreateMessages(gomock.Any(), "1", gomock.Any(), false).Return(nil, messages.ErrBatchTooLarge).Times(1)
+			},
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockMessage(ctrl)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			w := serveEncoded(t, mocks.NewMockChat(ctrl), m, http.MethodPost, tt.url, "application/json", "", []byte(tt.body))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
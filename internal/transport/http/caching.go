@@ -0,0 +1,134 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/AlekSi/pointer"
+)
+
+// chatCacheControl lets clients keep chat reads but makes them revalidate every
+// time, which is cheap for unchanged chats thanks to conditional requests.
+const chatCacheControl = "private, no-cache"
+
+// checkNotModified sets the validators and caching headers of a chat read and
+// reports whether the client's copy is still current. In that case a 304 has
+// already been written and the chat does not need to be loaded. unread is what
+// the caller has not read of the chat, when it is part of the response.
+func (s *Server) checkNotModified(w http.ResponseWriter, r *http.Request, id string, unread *rdmodel.Unread) (bool, error) {
+	ctx := r.Context()
+
+	v, err := s.chat.GetChatVersion(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	etag := chatETag(v, unread, r.URL.RequestURI(), responseCodec(ctx).ContentType())
+	modified := lastModified(v, unread)
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", chatCacheControl)
+	h.Add("Vary", "Accept")
+	if s.read != nil {
+		h.Add("Vary", UserHeader)
+	}
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.Format(http.TimeFormat))
+	}
+
+	if !notModified(r, etag, modified) {
+		return false, nil
+	}
+
+	// A 304 carries the validators only.
+	h.Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+
+	return true, nil
+}
+
+// chatETag derives a strong entity tag from everything the representation of a
+// chat read depends on: the chat version, its latest message, the latest expiry
+// of its messages, which hides them before they are deleted, the read cursor of
+// the caller, the request URI, which carries the page and the selected fields,
+// and the response encoding.
+func chatETag(v *chmodel.Version, unread *rdmodel.Unread, uri, contentType string) string {
+	var cursor string
+	if unread != nil {
+		cursor = "read:" + pointer.GetString(unread.LastReadMessageID)
+	}
+
+	var expired string
+	if v.LastExpiredAt != nil {
+		expired = v.LastExpiredAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%s\x00%s\x00%s\x00%s",
+		pointer.GetString(v.ChatID),
+		pointer.GetInt64(v.Version),
+		pointer.GetString(v.LastMessageID),
+		expired,
+		cursor,
+		uri,
+		contentType,
+	)))
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func lastModified(v *chmodel.Version, unread *rdmodel.Unread) time.Time {
+	t := pointer.GetTime(v.UpdatedAt)
+	if at := pointer.GetTime(v.LastMessageAt); at.After(t) {
+		t = at
+	}
+	if at := pointer.GetTime(v.LastExpiredAt); at.After(t) {
+		t = at
+	}
+	if unread != nil {
+		if at := pointer.GetTime(unread.ReadAt); at.After(t) {
+			t = at
+		}
+	}
+
+	return t.UTC().Truncate(time.Second)
+}
+
+// notModified evaluates If-None-Match and, only when it is absent, If-Modified-Since,
+// as RFC 9110 requires.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+// etagMatches uses the weak comparison, which RFC 9110 prescribes for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,208 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chatVersion(version int64, lastMessageID string) *chatModel.Version {
+	return &chatModel.Version{
+		ChatID:        pointer.ToString("1"),
+		Version:       pointer.ToInt64(version),
+		UpdatedAt:     pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		LastMessageID: pointer.ToString(lastMessageID),
+		LastMessageAt: pointer.ToTime(time.Date(2020, 1, 2, 10, 30, 0, 500, time.UTC)),
+	}
+}
+
+// expiredVersion is the version of chatVersion(1, "5") once one of its messages
+// expired at at, before the message is deleted.
+func expiredVersion(at time.Time) *chatModel.Version {
+	v := chatVersion(1, "5")
+	v.LastExpiredAt = pointer.ToTime(at)
+	return v
+}
+
+func getChatWithHeaders(t *testing.T, c *mocks.MockChat, url string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ht := httptransport.New(c, mocks.NewMockMessage(gomock.NewController(t)), nil)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_GetChat_CachingHeaders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "5"), nil).Times(1)
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+	w := getChatWithHeaders(t, c, "/v1/chats/1", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+	assert.Equal(t, "Thu, 02 Jan 2020 10:30:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+}
+
+func TestServer_GetChat_NotModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "5"), nil).Times(1)
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+	etag := getChatWithHeaders(t, c, "/v1/chats/1", nil).Header().Get("ETag")
+
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+	}{
+		{name: "if-none-match", url: "/v1/chats/1", headers: map[string]string{"If-None-Match": etag}},
+		{name: "weak if-none-match in a list", url: "/v1/chats/1", headers: map[string]string{"If-None-Match": `"other", W/` + etag}},
+		{name: "if-none-match any", url: "/v1/chats/1", headers: map[string]string{"If-None-Match": "*"}},
+		{name: "if-modified-since", url: "/v1/chats/1", headers: map[string]string{"If-Modified-Since": "Thu, 02 Jan 2020 10:30:00 GMT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "5"), nil).Times(1)
+
+			w := getChatWithHeaders(t, c, tt.url, tt.headers)
+
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Empty(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestServer_GetChat_Modified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "5"), nil).Times(1)
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+	etag := getChatWithHeaders(t, c, "/v1/chats/1", nil).Header().Get("ETag")
+
+	tests := []struct {
+		name        string
+		version     *chatModel.Version
+		url         string
+		headers     map[string]string
+		wantNewETag bool
+	}{
+		{
+			name:        "new message",
+			version:     chatVersion(1, "6"),
+			url:         "/v1/chats/1",
+			headers:     map[string]string{"If-None-Match": etag},
+			wantNewETag: true,
+		},
+		{
+			name:        "message edited",
+			version:     chatVersion(2, "5"),
+			url:         "/v1/chats/1",
+			headers:     map[string]string{"If-None-Match": etag},
+			wantNewETag: true,
+		},
+		{
+			name:        "message expired",
+			version:     expiredVersion(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+			url:         "/v1/chats/1",
+			headers:     map[string]string{"If-None-Match": etag},
+			wantNewETag: true,
+		},
+		{
+			name:        "expired since",
+			version:     expiredVersion(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+			url:         "/v1/chats/1",
+			headers:     map[string]string{"If-Modified-Since": "Thu, 02 Jan 2020 10:30:00 GMT"},
+			wantNewETag: true,
+		},
+		{
+			name:    "if-none-match wins over if-modified-since",
+			version: chatVersion(1, "5"),
+			url:     "/v1/chats/1",
+			headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Fri, 03 Jan 2020 00:00:00 GMT"},
+		},
+		{
+			name:    "modified since",
+			version: chatVersion(1, "5"),
+			url:     "/v1/chats/1",
+			headers: map[string]string{"If-Modified-Since": "Thu, 02 Jan 2020 10:29:59 GMT"},
+		},
+		{
+			name:        "other representation",
+			version:     chatVersion(1, "5"),
+			url:         "/v1/chats/1",
+			headers:     map[string]string{"If-None-Match": etag, "Accept": "application/msgpack"},
+			wantNewETag: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(tt.version, nil).Times(1)
+			c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+			w := getChatWithHeaders(t, c, tt.url, tt.headers)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantNewETag, etag != w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestServer_GetChatV2_NotModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(3, "9"), nil).Times(2)
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+	etag := getChatWithHeaders(t, c, "/v2/chats/1", nil).Header().Get("ETag")
+	w := getChatWithHeaders(t, c, "/v2/chats/1", map[string]string{"If-None-Match": etag})
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
@@ -209,6 +209,9 @@ func TestServer_GetChat_Success(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			c.EXPECT().
+				GetChatVersion(gomock.Any(), tt.args.id).
+				Return(&chatModel.Version{ChatID: pointer.ToString(tt.args.id), Version: pointer.ToInt64(1)}, nil).Times(1)
 			c.EXPECT().
 				GetChat(gomock.Any(), tt.args.id, int64(20)).
 				Return(&tt.wantChat, nil).Times(1)
@@ -269,6 +272,7 @@ func TestServer_GetChat_Error(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			c.EXPECT().GetChatVersion(gomock.Any(), tt.args.id).Return(&chatModel.Version{Version: pointer.ToInt64(1)}, nil).Times(1)
 			c.EXPECT().GetChat(gomock.Any(), tt.args.id, int64(20)).Return(nil, errors.New(tt.wantErr)).Times(1)
 
 			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(chatURL, tt.args.id), nil)
@@ -334,12 +338,7 @@ func TestServer_DeleteChat_Success(t *testing.T) {
 
 			assert.Equal(t, tt.wantCode, w.Code)
 
-			var res struct {
-				Data string `json:"data"`
-			}
-
-			err = json.Unmarshal(w.Body.Bytes(), &res)
-			require.NoError(t, err)
+			assert.Equal(t, "{\"data\":\"deleted\"}\n{\"success\":true}\n", w.Body.String())
 		})
 	}
 }
@@ -449,8 +448,8 @@ func TestServer_CreateMessage_Success(t *testing.T) {
 
 			m.EXPECT().
 				CreateMessage(gomock.Any(), gomock.AssignableToTypeOf(&messagesModel.Message{})).
-				DoAndReturn(func(ctx context.Context, c *messagesModel.Message) (*messagesModel.Message, error) {
-					return &tt.wantMessage, nil
+				DoAndReturn(func(ctx context.Context, c *messagesModel.Message) (*messagesModel.Posted, error) {
+					return &messagesModel.Posted{Message: &tt.wantMessage}, nil
 				}).Times(1)
 
 			data, err := json.Marshal(tt.args.message)
@@ -539,3 +538,69 @@ func TestServer_CreateMessage_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_CreateMessage_System(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	d := mocks.NewMockDB(ctrl)
+
+	ht := httptransport.New(c, m, d)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	// Only the server posts system messages, so the flag is ignored.
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("deploying"),
+			Author: pointer.ToString("bot"),
+		}).
+		Return(&messagesModel.Posted{Message: &messagesModel.Message{
+			ID:     pointer.ToString("2"),
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("deploying"),
+			Author: pointer.ToString("bot"),
+		}}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(messageURL, "1"), bytes.NewBufferString(`{"text":"deploying","author":"bot","system":true}`))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_CreateMessage_NoReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	d := mocks.NewMockDB(ctrl)
+
+	ht := httptransport.New(c, m, d)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("1"),
+			Text:   pointer.ToString("/mute"),
+		}).
+		Return(&messagesModel.Posted{Command: "mute"}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(messageURL, "1"), bytes.NewBufferString(`{"text":"/mute"}`))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -2,13 +2,16 @@ package http
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
 	"github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
@@ -21,26 +24,26 @@ func (s *Server) createChat(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var c model.Chat
-	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+	if err := decodeBody(r, &c); err != nil {
 		logging.From(ctx).Error("failed to decode request body", zap.Error(err))
 		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
 		return
 	}
 
 	createdChat, err := s.chat.CreateChat(ctx, &c)
+	if errors.Is(err, chats.ErrInvalidTTL) || errors.Is(err, chats.ErrInvalidRetention) {
+		handleError(ctx, w, err)
+		return
+	}
 	if err != nil {
 		logging.From(ctx).Error("failed to create chat", zap.Error(err))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{
+		writeBody(ctx, w, http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": createdChat,
-	})
+	handleResponse(ctx, w, createdChat)
 }
 
 func (s *Server) getChat(w http.ResponseWriter, r *http.Request) {
@@ -63,21 +66,75 @@ func (s *Server) getChat(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	chat, err := s.chat.GetChat(ctx, id, 20)
+	sel, err := parseSelection(r, chatFields)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	unread, err := s.unread(r, id)
+	if err != nil {
+		logging.From(ctx).Error("failed to get unread messages", zap.Error(err))
+
+		writeBody(ctx, w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	done, err := s.checkNotModified(w, r, id, unread)
+	if err != nil {
+		logging.From(ctx).Error("failed to get chat version", zap.Error(err))
+
+		writeBody(ctx, w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+	if done {
+		return
+	}
+
+	chat, err := s.chat.GetChat(ctx, id, 20, sel.options()...)
 	if err != nil {
 		logging.From(ctx).Error("failed to get chat", zap.Error(err))
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{
+		writeBody(ctx, w, http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": chat,
-	})
+	if unread != nil {
+		chat.UnreadCount = unread.UnreadCount
+		chat.FirstUnreadID = unread.FirstUnreadID
+	}
+
+	handleResponse(ctx, w, sel.apply(chat))
+}
+
+// UpdateChatRequest is the body accepted by PATCH /v1/chats/{id}. A null
+// message_ttl lets new messages of the chat live forever.
+type UpdateChatRequest struct {
+	MessageTTL *int64 `json:"message_ttl"`
+}
+
+func (s *Server) updateChat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req UpdateChatRequest
+	if err := decodeBody(r, &req); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	c, err := s.chat.SetMessageTTL(ctx, mux.Vars(r)["id"], req.MessageTTL)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, c)
 }
 
 func (s *Server) deleteChat(w http.ResponseWriter, r *http.Request) {
@@ -91,17 +148,25 @@ func (s *Server) deleteChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = s.chat.DeleteChat(ctx, id)
+	if errors.Is(err, chats.ErrLegalHold) {
+		handleError(ctx, w, err)
+		return
+	}
 	if err != nil {
 		logging.From(ctx).Error("failed to delete chat", zap.Error(err))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{
+		writeBody(ctx, w, http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	// JSON clients keep getting the two documents /v1 always answered with,
+	// other encodings carry the status alone.
+	if _, ok := responseCodec(ctx).(codec.JSON); !ok {
+		handleResponse(ctx, w, "deleted")
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(map[string]string{
 		"data": "deleted",
@@ -0,0 +1,182 @@
+package codec
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+)
+
+const (
+	// ErrNotAcceptable is returned when none of the media types a client accepts can be produced.
+	ErrNotAcceptable = errors.Error("not_acceptable: none of the accepted media types are supported")
+	// ErrUnsupportedMediaType is returned when a request body is in a media type that cannot be decoded.
+	ErrUnsupportedMediaType = errors.Error("unsupported_media_type: request content type is not supported")
+	// ErrNotEncodable is returned when a value has no representation in a codec's format.
+	ErrNotEncodable = errors.Error("not_encodable: value cannot be encoded in this format")
+)
+
+// Codec encodes and decodes bodies of a single media type.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Registry selects codecs for requests and responses. The first registered
+// codec is the default used when a client expresses no preference.
+type Registry struct {
+	codecs []Codec
+	byType map[string]Codec
+	// decoders holds the codecs accepted for request bodies, by content type.
+	decoders map[string]Codec
+}
+
+// NewRegistry instantiates a new instance of Registry with the given codecs.
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{
+		byType:   map[string]Codec{},
+		decoders: map[string]Codec{},
+	}
+
+	for _, c := range codecs {
+		r.Register(c)
+	}
+
+	return r
+}
+
+// Register adds c to the registry under its content type and any aliases.
+func (r *Registry) Register(c Codec, aliases ...string) {
+	r.RegisterResponse(c, aliases...)
+
+	r.decoders[c.ContentType()] = c
+	for _, a := range aliases {
+		r.decoders[a] = c
+	}
+}
+
+// RegisterResponse adds c to the registry for responses only. Request bodies of
+// its content type are refused as unsupported.
+func (r *Registry) RegisterResponse(c Codec, aliases ...string) {
+	r.codecs = append(r.codecs, c)
+
+	r.byType[c.ContentType()] = c
+	for _, a := range aliases {
+		r.byType[a] = c
+	}
+}
+
+// Default returns the codec used when a client expresses no preference.
+func (r *Registry) Default() Codec {
+	return r.codecs[0]
+}
+
+// ForContentType returns the codec for a request Content-Type header. An empty
+// header selects the default codec.
+func (r *Registry) ForContentType(header string) (Codec, error) {
+	if strings.TrimSpace(header) == "" {
+		return r.Default(), nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType.Wrap(err)
+	}
+
+	c, ok := r.decoders[mediaType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	return c, nil
+}
+
+// Negotiate returns the codec best matching an Accept header. Media ranges are
+// ranked by quality, then by specificity; an empty header selects the default codec.
+func (r *Registry) Negotiate(header string) (Codec, error) {
+	if strings.TrimSpace(header) == "" {
+		return r.Default(), nil
+	}
+
+	ranges := parseAccept(header)
+
+	// Media types listed with q=0 are explicitly refused, even if a wildcard matches them.
+	refused := map[Codec]bool{}
+	for _, rng := range ranges {
+		if c, ok := r.byType[rng.mediaType]; ok && rng.quality == 0 {
+			refused[c] = true
+		}
+	}
+
+	for _, rng := range ranges {
+		if rng.quality == 0 {
+			continue
+		}
+
+		if c, ok := r.byType[rng.mediaType]; ok && !refused[c] {
+			return c, nil
+		}
+
+		// Wildcards match by prefix: "application/*" by "application/", "*/*" by "".
+		prefix := strings.TrimSuffix(strings.TrimPrefix(rng.mediaType, "*/"), "*")
+		if prefix == rng.mediaType {
+			continue
+		}
+
+		for _, c := range r.codecs {
+			if !refused[c] && strings.HasPrefix(c.ContentType(), prefix) {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, ErrNotAcceptable
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+
+	return ranges
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,123 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistry() *codec.Registry {
+	r := codec.NewRegistry(codec.JSON{})
+	r.Register(codec.MessagePack{}, "application/x-msgpack")
+	r.Register(codec.Protobuf{})
+	return r
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "empty header", accept: "", want: "application/json"},
+		{name: "exact match", accept: "application/msgpack", want: "application/msgpack"},
+		{name: "alias", accept: "application/x-msgpack", want: "application/msgpack"},
+		{name: "any", accept: "*/*", want: "application/json"},
+		{name: "subtype wildcard", accept: "application/*", want: "application/json"},
+		{name: "quality", accept: "application/json;q=0.5, application/x-protobuf", want: "application/x-protobuf"},
+		{name: "specific before wildcard", accept: "*/*, application/msgpack", want: "application/msgpack"},
+		{name: "refused by q=0", accept: "application/json;q=0, */*", want: "application/msgpack"},
+		{name: "unsupported before supported", accept: "text/html, application/json;q=0.9", want: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newRegistry().Negotiate(tt.accept)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.ContentType())
+		})
+	}
+}
+
+func TestRegistry_Negotiate_Error(t *testing.T) {
+	for _, accept := range []string{"text/html", "text/*", "application/json;q=0"} {
+		t.Run(accept, func(t *testing.T) {
+			_, err := newRegistry().Negotiate(accept)
+			assert.ErrorIs(t, err, codec.ErrNotAcceptable)
+		})
+	}
+}
+
+func TestRegistry_ForContentType(t *testing.T) {
+	r := newRegistry()
+
+	c, err := r.ForContentType("")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", c.ContentType())
+
+	c, err = r.ForContentType("application/json; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", c.ContentType())
+
+	_, err = r.ForContentType("text/xml")
+	assert.ErrorIs(t, err, codec.ErrUnsupportedMediaType)
+}
+
+func TestRegistry_RegisterResponse(t *testing.T) {
+	r := codec.NewRegistry(codec.JSON{})
+	r.RegisterResponse(codec.Protobuf{})
+
+	c, err := r.Negotiate("application/x-protobuf")
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-protobuf", c.ContentType())
+
+	_, err = r.ForContentType("application/x-protobuf")
+	assert.ErrorIs(t, err, codec.ErrUnsupportedMediaType)
+}
+
+func TestMessagePack_RoundTrip(t *testing.T) {
+	type body struct {
+		Title  string  `json:"title"`
+		Author *string `json:"author"`
+	}
+
+	data, err := codec.MessagePack{}.Marshal(body{Title: "general"})
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	require.NoError(t, codec.MessagePack{}.Unmarshal(data, &got))
+	assert.Equal(t, map[string]interface{}{"title": "general", "author": nil}, got)
+
+	strict := codec.MessagePack{Strict: true}
+	data, err = strict.Marshal(map[string]string{"title": "general", "id": "1"})
+	require.NoError(t, err)
+	assert.Error(t, strict.Unmarshal(data, &body{}))
+}
+
+func TestProtobuf_NotEncodable(t *testing.T) {
+	_, err := codec.Protobuf{}.Marshal(map[string]string{"title": "general"})
+	assert.ErrorIs(t, err, codec.ErrNotEncodable)
+}
+
+func TestNDJSON_RoundTrip(t *testing.T) {
+	type item struct {
+		Text string `json:"text"`
+	}
+
+	data, err := codec.NDJSON{}.Marshal([]item{{Text: "a"}, {Text: "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"text\":\"a\"}\n{\"text\":\"b\"}\n", string(data))
+
+	var items []item
+	require.NoError(t, codec.NDJSON{}.Unmarshal([]byte("{\"text\":\"a\"}\n\r\n{\"text\":\"b\"}"), &items))
+	assert.Equal(t, []item{{Text: "a"}, {Text: "b"}}, items)
+
+	var single item
+	require.NoError(t, codec.NDJSON{}.Unmarshal([]byte(`{"text":"c"}`), &single))
+	assert.Equal(t, item{Text: "c"}, single)
+
+	assert.Error(t, codec.NDJSON{}.Unmarshal([]byte("{\"text\":\"a\"}\nnot json\n"), &items))
+}
@@ -0,0 +1,172 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSON encodes bodies as application/json. Strict rejects documents with fields
+// the destination type does not declare.
+type JSON struct {
+	Strict bool
+}
+
+func (JSON) ContentType() string {
+	return "application/json"
+}
+
+func (JSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (c JSON) Unmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if c.Strict {
+		dec.DisallowUnknownFields()
+	}
+
+	return dec.Decode(v)
+}
+
+// NDJSON encodes bodies as newline delimited JSON. Slices are written and read
+// one element per line, any other value as a single line.
+type NDJSON struct{}
+
+func (NDJSON) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (NDJSON) Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	for i := 0; i < rv.Len(); i++ {
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (NDJSON) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice {
+		return json.Unmarshal(data, v)
+	}
+
+	slice := rv.Elem()
+	slice.SetLen(0)
+
+	for len(data) > 0 {
+		var line []byte
+		line, data, _ = bytes.Cut(data, []byte("\n"))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		elem := reflect.New(slice.Type().Elem())
+		if err := json.Unmarshal(line, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	return nil
+}
+
+// MessagePack encodes bodies as application/msgpack. Field names follow the json
+// struct tags so both encodings describe the same documents. Strict rejects
+// documents with fields the destination type does not declare.
+type MessagePack struct {
+	Strict bool
+}
+
+func (MessagePack) ContentType() string {
+	return "application/msgpack"
+}
+
+func (MessagePack) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetOmitEmpty(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c MessagePack) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(c.Strict)
+
+	return dec.Decode(v)
+}
+
+// ProtoMarshaler is implemented by types that have a protobuf representation.
+type ProtoMarshaler interface {
+	ToProto() proto.Message
+}
+
+// ProtoUnmarshaler is implemented by types that can be decoded from protobuf.
+// NewProto returns an empty message to decode into, FromProto copies its fields back.
+type ProtoUnmarshaler interface {
+	NewProto() proto.Message
+	FromProto(m proto.Message) error
+}
+
+// Protobuf encodes bodies as application/x-protobuf. Only proto messages and
+// types implementing ProtoMarshaler or ProtoUnmarshaler can be encoded or decoded.
+type Protobuf struct{}
+
+func (Protobuf) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (Protobuf) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Marshal(m)
+	case ProtoMarshaler:
+		msg := m.ToProto()
+		if msg == nil {
+			return nil, ErrNotEncodable
+		}
+		return proto.Marshal(msg)
+	default:
+		return nil, ErrNotEncodable
+	}
+}
+
+func (Protobuf) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	case ProtoUnmarshaler:
+		msg := m.NewProto()
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		return m.FromProto(msg)
+	default:
+		return ErrNotEncodable
+	}
+}
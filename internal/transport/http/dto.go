@@ -0,0 +1,110 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+)
+
+// CreateChatRequest is the body accepted by POST /v2/chats.
+type CreateChatRequest struct {
+	Title string `json:"title" validate:"required,max=200"`
+}
+
+// CreateMessageRequest is the body accepted by POST /v2/chats/{id}/messages.
+type CreateMessageRequest struct {
+	Text   string  `json:"text" validate:"required"`
+	Author *string `json:"author" validate:"omitempty,max=100"`
+}
+
+// ChatResponse is a chat as returned by the /v2 API.
+type ChatResponse struct {
+	ID        int64             `json:"id"`
+	Title     string            `json:"title"`
+	CreatedAt string            `json:"created_at"`
+	Messages  []MessageResponse `json:"messages"`
+}
+
+// MessageResponse is a message as returned by the /v2 API.
+type MessageResponse struct {
+	ID        int64   `json:"id"`
+	ChatID    int64   `json:"chat_id"`
+	Text      string  `json:"text"`
+	Author    *string `json:"author"`
+	System    bool    `json:"system"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// DataResponse is the envelope of every successful /v2 response.
+type DataResponse struct {
+	Data interface{} `json:"data"`
+}
+
+// ErrorResponse is the envelope of every failed /v2 response.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes a failure with a stable machine readable code.
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (r CreateChatRequest) toModel() *chmodel.Chat {
+	return &chmodel.Chat{
+		Title: pointer.ToString(r.Title),
+	}
+}
+
+func (r CreateMessageRequest) toModel(chatID int64) *msmodel.Message {
+	return &msmodel.Message{
+		ChatID: pointer.ToString(strconv.FormatInt(chatID, 10)),
+		Text:   pointer.ToString(r.Text),
+		Author: r.Author,
+	}
+}
+
+func toChatResponse(c *chmodel.Chat) ChatResponse {
+	res := ChatResponse{
+		ID:        toID(c.ID),
+		Title:     pointer.GetString(c.Title),
+		CreatedAt: toTimestamp(c.CreatedAt),
+		Messages:  make([]MessageResponse, 0, len(c.Messages)),
+	}
+
+	for i := range c.Messages {
+		res.Messages = append(res.Messages, toMessageResponse(&c.Messages[i]))
+	}
+
+	return res
+}
+
+func toMessageResponse(m *msmodel.Message) MessageResponse {
+	return MessageResponse{
+		ID:        toID(m.ID),
+		ChatID:    toID(m.ChatID),
+		Text:      pointer.GetString(m.Text),
+		Author:    m.Author,
+		System:    pointer.GetBool(m.System),
+		CreatedAt: toTimestamp(m.CreatedAt),
+	}
+}
+
+// toID converts a database id, which the models carry as a string, to the
+// integer exposed by the /v2 API.
+func toID(id *string) int64 {
+	n, _ := strconv.ParseInt(pointer.GetString(id), 10, 64)
+	return n
+}
+
+func toTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -2,12 +2,24 @@ package http
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"strings"
 
+	"github.com/Polilo-User/test-task-hitalent/internal/attachments"
+	"github.com/Polilo-User/test-task-hitalent/internal/changes"
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads"
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks"
 
 	"go.uber.org/zap"
 )
@@ -15,18 +27,7 @@ import (
 func handleError(ctx context.Context, w http.ResponseWriter, err error) {
 	logging.From(ctx).Error("error occurred in request", zap.Error(err))
 
-	switch {
-	case errors.Is(err, errors.ErrInvalidRequest):
-		fallthrough
-	case errors.Is(err, errors.ErrValidation):
-		w.WriteHeader(http.StatusBadRequest)
-	case errors.Is(err, errors.ErrNotFound):
-		w.WriteHeader(http.StatusNotFound)
-	case errors.Is(err, errors.ErrUnknown):
-		fallthrough
-	default:
-		w.WriteHeader(http.StatusInternalServerError)
-	}
+	status := errorStatus(err)
 
 	errJSON := struct {
 		Error string `json:"error"`
@@ -34,14 +35,136 @@ func handleError(ctx context.Context, w http.ResponseWriter, err error) {
 		Error: strings.Split(err.Error(), errors.ErrSeperator)[0],
 	}
 
-	data, err := json.Marshal(errJSON)
+	c := responseCodec(ctx)
+
+	data, err := c.Marshal(errJSON)
 	if err != nil {
 		logging.From(ctx).Error("failed to serialize error response", zap.Error(err))
+		c = codec.JSON{}
 		data = []byte(`{"error": "internal server error"}`)
 	}
 
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(status)
+
 	_, err = w.Write(data)
 	if err != nil {
 		logging.From(ctx).Error("failed to write error response", zap.Error(err))
 	}
 }
+
+// errorStatus maps domain errors onto the http status code returned to clients.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errors.ErrInvalidRequest):
+		fallthrough
+	case errors.Is(err, webhooks.ErrInvalidWebhook):
+		fallthrough
+	case errors.Is(err, hooks.ErrInvalidHook):
+		fallthrough
+	case errors.Is(err, commands.ErrUsage):
+		fallthrough
+	case errors.Is(err, messages.ErrEmptyBatch):
+		fallthrough
+	case errors.Is(err, messages.ErrInvalidRange):
+		fallthrough
+	case errors.Is(err, messages.ErrEmptyText):
+		fallthrough
+	case errors.Is(err, messages.ErrInvalidSeqRange):
+		fallthrough
+	case errors.Is(err, messages.ErrInvalidExpiry):
+		fallthrough
+	case errors.Is(err, chats.ErrInvalidTTL):
+		fallthrough
+	case errors.Is(err, chats.ErrInvalidRetention):
+		fallthrough
+	case errors.Is(err, ErrInvalidExportFormat):
+		fallthrough
+	case errors.Is(err, imports.ErrUnknownSource):
+		fallthrough
+	case errors.Is(err, imports.ErrEmptyArchive):
+		fallthrough
+	case errors.Is(err, idempotency.ErrInvalidKey):
+		fallthrough
+	case errors.Is(err, attachments.ErrInvalidName):
+		fallthrough
+	case errors.Is(err, ErrTooManyFiles):
+		fallthrough
+	case errors.Is(err, changes.ErrInvalidToken):
+		fallthrough
+	case errors.Is(err, scheduled.ErrInvalidSendAt):
+		fallthrough
+	case errors.Is(err, scheduled.ErrInvalidExpiresAt):
+		fallthrough
+	case errors.Is(err, scheduled.ErrEmptyText):
+		fallthrough
+	case errors.Is(err, ErrSchedulingDisabled):
+		fallthrough
+	case errors.Is(err, errors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, messages.ErrBatchTooLarge):
+		fallthrough
+	case errors.Is(err, imports.ErrArchiveTooLarge):
+		fallthrough
+	case errors.Is(err, attachments.ErrAttachmentTooLarge):
+		fallthrough
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, messages.ErrBatchRejected):
+		fallthrough
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, idempotency.ErrKeyInProgress):
+		fallthrough
+	case errors.Is(err, chats.ErrLegalHold):
+		return http.StatusConflict
+	case errors.Is(err, chats.ErrChatNotFound):
+		fallthrough
+	case errors.Is(err, webhooks.ErrWebhookNotFound):
+		fallthrough
+	case errors.Is(err, hooks.ErrHookNotFound):
+		fallthrough
+	case errors.Is(err, webhooks.ErrDeliveryNotFound):
+		fallthrough
+	case errors.Is(err, imports.ErrJobNotFound):
+		fallthrough
+	case errors.Is(err, attachments.ErrAttachmentNotFound):
+		fallthrough
+	case errors.Is(err, attachments.ErrMessageNotFound):
+		fallthrough
+	case errors.Is(err, messages.ErrMessageNotFound):
+		fallthrough
+	case errors.Is(err, notifications.ErrNotificationNotFound):
+		fallthrough
+	case errors.Is(err, reads.ErrMessageNotFound):
+		fallthrough
+	case errors.Is(err, scheduled.ErrScheduledNotFound):
+		fallthrough
+	case errors.Is(err, errors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, attachments.ErrInvalidSignature):
+		fallthrough
+	case errors.Is(err, attachments.ErrLinkExpired):
+		return http.StatusForbidden
+	case errors.Is(err, hooks.ErrHookRevoked):
+		fallthrough
+	case errors.Is(err, changes.ErrTokenExpired):
+		return http.StatusGone
+	case errors.Is(err, hooks.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, codec.ErrNotAcceptable):
+		fallthrough
+	case errors.Is(err, codec.ErrNotEncodable):
+		return http.StatusNotAcceptable
+	case errors.Is(err, codec.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, commands.ErrCommandFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, errors.ErrUnknown):
+		fallthrough
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,170 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_UpdateChat(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantTTL  *int64
+		chat     *chatModel.Chat
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "sets ttl",
+			body:     `{"message_ttl":3600}`,
+			wantTTL:  pointer.ToInt64(3600),
+			chat:     &chatModel.Chat{ID: pointer.ToString("1"), MessageTTL: pointer.ToInt64(3600)},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "clears ttl",
+			body:     `{"message_ttl":null}`,
+			chat:     &chatModel.Chat{ID: pointer.ToString("1")},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "chat not found",
+			body:     `{"message_ttl":60}`,
+			wantTTL:  pointer.ToInt64(60),
+			err:      chats.ErrChatNotFound,
+			wantCode: http.StatusNotFound,
+			wantErr:  chats.ErrChatNotFound.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			d := mocks.NewMockDB(ctrl)
+
+			ht := httptransport.New(c, m, d)
+			require.NotNil(t, ht)
+
+			r := mux.NewRouter()
+			require.NoError(t, ht.AddRoutes(r))
+
+			c.EXPECT().SetMessageTTL(gomock.Any(), "1", tt.wantTTL).Return(tt.chat, tt.err).Times(1)
+
+			req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf(chatURL, "1"), bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var res struct {
+				Data  chatModel.Chat `json:"data"`
+				Error string         `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, res.Error)
+				return
+			}
+			assert.Equal(t, *tt.chat, res.Data)
+		})
+	}
+}
+
+func TestServer_UpdateChat_InvalidBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf(chatURL, "1"), bytes.NewBufferString(`{"message_ttl":0}`))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestServer_CreateMessage_Expiry(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "expires later",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "already expired",
+			err:      messages.ErrInvalidExpiry,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			d := mocks.NewMockDB(ctrl)
+
+			ht := httptransport.New(c, m, d)
+			require.NotNil(t, ht)
+
+			r := mux.NewRouter()
+			require.NoError(t, ht.AddRoutes(r))
+
+			m.EXPECT().
+				CreateMessage(gomock.Any(), gomock.AssignableToTypeOf(&messagesModel.Message{})).
+				DoAndReturn(func(_ interface{}, msg *messagesModel.Message) (*messagesModel.Posted, error) {
+					require.NotNil(t, msg.ExpiresAt)
+					assert.True(t, expiresAt.Equal(*msg.ExpiresAt))
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &messagesModel.Posted{Message: msg}, nil
+				}).Times(1)
+
+			body := fmt.Sprintf(`{"text":"hi","author":"bob","expires_at":%q}`, expiresAt.Format(time.RFC3339))
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(messageURL, "1"), bytes.NewBufferString(body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidExportFormat is returned when an export is requested in an unknown format.
+const ErrInvalidExportFormat = errors.Error("invalid_export_format: format must be one of jsonl, csv or md")
+
+// transcript writes a chat history in one export format.
+type transcript interface {
+	begin(chat *chmodel.Chat) error
+	write(m *msmodel.Message) error
+	end() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	new         func(w io.Writer) transcript
+}
+
+var exportFormats = map[string]exportFormat{
+	"jsonl": {contentType: "application/x-ndjson", extension: "jsonl", new: newJSONLTranscript},
+	"csv":   {contentType: "text/csv; charset=utf-8", extension: "csv", new: newCSVTranscript},
+	"md":    {contentType: "text/markdown; charset=utf-8", extension: "md", new: newMarkdownTranscript},
+}
+
+// addExportRoutes registers exports on their own subrouter. Their format is named
+// by the query rather than negotiated, so content negotiation is skipped.
+func (s *Server) addExportRoutes(r *mux.Router, validate mux.MiddlewareFunc) {
+	r = r.PathPrefix("/v1").Subrouter()
+	r.Use(validate)
+
+	r.HandleFunc("/chats/{id}/export", s.exportChat).Methods(http.MethodGet)
+}
+
+// exportChat streams the whole history of a chat, or the part of it within the
+// from and to query parameters, as a downloadable transcript.
+func (s *Server) exportChat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	name := q.Get("format")
+	if name == "" {
+		name = "jsonl"
+	}
+	format, ok := exportFormats[name]
+	if !ok {
+		handleError(ctx, w, ErrInvalidExportFormat)
+		return
+	}
+
+	from, err := parseTime(q.Get("from"))
+	if err != nil {
+		handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("invalid from parameter")))
+		return
+	}
+	to, err := parseTime(q.Get("to"))
+	if err != nil {
+		handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("invalid to parameter")))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	chat, err := s.chat.GetChat(ctx, id, 0, chats.WithoutMessages())
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%s.%s"`, id, format.extension))
+
+	rc := http.NewResponseController(w)
+	buf := bufio.NewWriter(w)
+	t := format.new(buf)
+
+	// The transcript is started with the first batch, so that failures before it
+	// can still be answered with an error status.
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		return t.begin(chat)
+	}
+	flush := func() error {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	err = s.message.ExportMessages(ctx, id, from, to, func(batch []msmodel.Message) error {
+		if err := start(); err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err := t.write(&batch[i]); err != nil {
+				return err
+			}
+		}
+
+		return flush()
+	})
+	if err != nil && !started {
+		handleError(ctx, w, err)
+		return
+	}
+	if err == nil {
+		err = start()
+	}
+	if err == nil {
+		err = t.end()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		logging.From(ctx).Error("failed to export chat", zap.Error(err))
+		// Abort the connection so the client sees a truncated transfer rather
+		// than a transcript that looks complete.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+type jsonlTranscript struct {
+	enc *json.Encoder
+}
+
+func newJSONLTranscript(w io.Writer) transcript {
+	return &jsonlTranscript{enc: json.NewEncoder(w)}
+}
+
+func (t *jsonlTranscript) begin(*chmodel.Chat) error {
+	return nil
+}
+
+func (t *jsonlTranscript) write(m *msmodel.Message) error {
+	return t.enc.Encode(m)
+}
+
+func (t *jsonlTranscript) end() error {
+	return nil
+}
+
+type csvTranscript struct {
+	w *csv.Writer
+}
+
+func newCSVTranscript(w io.Writer) transcript {
+	return &csvTranscript{w: csv.NewWriter(w)}
+}
+
+func (t *csvTranscript) begin(*chmodel.Chat) error {
+	return t.w.Write([]string{"id", "chat_id", "created_at", "author", "system", "text"})
+}
+
+func (t *csvTranscript) write(m *msmodel.Message) error {
+	err := t.w.Write([]string{
+		pointer.GetString(m.ID),
+		pointer.GetString(m.ChatID),
+		formatTime(m.CreatedAt, time.RFC3339),
+		pointer.GetString(m.Author),
+		strconv.FormatBool(pointer.GetBool(m.System)),
+		pointer.GetString(m.Text),
+	})
+	if err != nil {
+		return err
+	}
+
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTranscript) end() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// markdownTranscript renders a chat as a human readable document. Message text is
+// quoted so that markdown written by participants cannot break the structure.
+type markdownTranscript struct {
+	w io.Writer
+}
+
+func newMarkdownTranscript(w io.Writer) transcript {
+	return &markdownTranscript{w: w}
+}
+
+func (t *markdownTranscript) begin(c *chmodel.Chat) error {
+	_, err := fmt.Fprintf(t.w, "# %s\n\nChat %s, created %s.\n",
+		escapeMarkdown(pointer.GetString(c.Title)),
+		pointer.GetString(c.ID),
+		formatTime(c.CreatedAt, "2006-01-02 15:04:05 MST"),
+	)
+	return err
+}
+
+func (t *markdownTranscript) write(m *msmodel.Message) error {
+	author := pointer.GetString(m.Author)
+	switch {
+	case pointer.GetBool(m.System):
+		author = "system"
+	case author == "":
+		author = "anonymous"
+	}
+
+	lines := strings.Split(pointer.GetString(m.Text), "\n")
+	_, err := fmt.Fprintf(t.w, "\n**%s** · %s\n\n> %s\n",
+		escapeMarkdown(author),
+		formatTime(m.CreatedAt, "2006-01-02 15:04:05 MST"),
+		strings.Join(lines, "\n> "),
+	)
+	return err
+}
+
+func (t *markdownTranscript) end() error {
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "#", `\#`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func formatTime(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(layout)
+}
@@ -0,0 +1,242 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestMessages() [][]messagesModel.Message {
+	at := time.Date(2020, 1, 2, 10, 30, 0, 0, time.UTC)
+
+	return [][]messagesModel.Message{
+		{
+			{ID: pointer.ToString("1"), ChatID: pointer.ToString("1"), Text: pointer.ToString("hello, *world*"), Author: pointer.ToString("bob"), CreatedAt: pointer.ToTime(at)},
+		},
+		{
+			{ID: pointer.ToString("2"), ChatID: pointer.ToString("1"), Text: pointer.ToString("two\nlines"), CreatedAt: pointer.ToTime(at.Add(time.Minute))},
+			{ID: pointer.ToString("3"), ChatID: pointer.ToString("1"), Text: pointer.ToString("rolled 4"), System: pointer.ToBool(true), CreatedAt: pointer.ToTime(at.Add(2 * time.Minute))},
+		},
+	}
+}
+
+func serveExport(t *testing.T, c *mocks.MockChat, m *mocks.MockMessage, url string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ht := httptransport.New(c, m, nil)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/csv")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_ExportChat_Success(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		wantContentType string
+		wantFilename    string
+		wantBody        string
+	}{
+		{
+			name:            "jsonl by default",
+			url:             "/v1/chats/1/export",
+			wantContentType: "application/x-ndjson",
+			wantFilename:    "chat-1.jsonl",
+			wantBody: `{"id":"1","text":"hello, *world*","author":"bob","system":null,"created_at":"2020-01-02T10:30:00Z","chat_id":"1"}
+{"id":"2","text":"two\nlines","author":null,"system":null,"created_at":"2020-01-02T10:31:00Z","chat_id":"1"}
+{"id":"3","text":"rolled 4","author":null,"system":true,"created_at":"2020-01-02T10:32:00Z","chat_id":"1"}
+`,
+		},
+		{
+			name:            "csv",
+			url:             "/v1/chats/1/export?format=csv",
+			wantContentType: "text/csv; charset=utf-8",
+			wantFilename:    "chat-1.csv",
+			wantBody: `id,chat_id,created_at,author,system,text
+1,1,2020-01-02T10:30:00Z,bob,false,"hello, *world*"
+2,1,2020-01-02T10:31:00Z,,false,"two
+lines"
+3,1,2020-01-02T10:32:00Z,,true,rolled 4
+`,
+		},
+		{
+			name:            "markdown",
+			url:             "/v1/chats/1/export?format=md",
+			wantContentType: "text/markdown; charset=utf-8",
+			wantFilename:    "chat-1.md",
+			wantBody: `# general \#1
+
+Chat 1, created 2020-01-01 00:00:00 UTC.
+
+**bob** · 2020-01-02 10:30:00 UTC
+
+> hello, *world*
+
+**anonymous** · 2020-01-02 10:31:00 UTC
+
+> two
+> lines
+
+**system** · 2020-01-02 10:32:00 UTC
+
+> rolled 4
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+
+			c.EXPECT().GetChat(gomock.Any(), "1", int64(0), gomock.Any()).Return(&chatModel.Chat{
+				ID:        pointer.ToString("1"),
+				Title:     pointer.ToString("general #1"),
+				CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}, nil).Times(1)
+			m.EXPECT().ExportMessages(gomock.Any(), "1", nil, nil, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _, _ *time.Time, fn func([]messagesModel.Message) error) error {
+					for _, batch := range exportTestMessages() {
+						if err := fn(batch); err != nil {
+							return err
+						}
+					}
+					return nil
+				}).Times(1)
+
+			w := serveExport(t, c, m, tt.url)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="`+tt.wantFilename+`"`, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_ExportChat_TimeRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(0), gomock.Any()).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+	m.EXPECT().ExportMessages(gomock.Any(), "1", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, gotFrom, gotTo *time.Time, _ func([]messagesModel.Message) error) error {
+			assert.True(t, from.Equal(*gotFrom))
+			assert.True(t, to.Equal(*gotTo))
+			return nil
+		}).Times(1)
+
+	w := serveExport(t, c, m, "/v1/chats/1/export?format=csv&from=2020-01-01T00:00:00Z&to=2020-02-01T00:00:00Z")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,chat_id,created_at,author,system,text\n", w.Body.String())
+}
+
+func TestServer_ExportChat_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		setup    func(c *mocks.MockChat, m *mocks.MockMessage)
+		wantCode int
+	}{
+		{
+			name:     "unknown format",
+			url:      "/v1/chats/1/export?format=pdf",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid from",
+			url:      "/v1/chats/1/export?from=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "chat not found",
+			url:  "/v1/chats/1/export",
+			setup: func(c *mocks.MockChat, m *mocks.MockMessage) {
+				c.EXPECT().GetChat(gomock.Any(), "1", int64(0), gomock.Any()).Return(nil, chats.ErrChatNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "invalid range",
+			url:  "/v1/chats/1/export?from=2020-02-01T00:00:00Z&to=2020-01-01T00:00:00Z",
+			setup: func(c *mocks.MockChat, m *mocks.MockMessage) {
+				c.EXPECT().GetChat(gomock.Any(), "1", int64(0), gomock.Any()).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+				m.EXPECT().ExportMessages(gomock.Any(), "1", gomock.Any(), gomock.Any(), gomock.Any()).Return(messages.ErrInvalidRange).Times(1)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			if tt.setup != nil {
+				tt.setup(c, m)
+			}
+
+			w := serveExport(t, c, m, tt.url)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestServer_ExportChat_FailsMidStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(0), gomock.Any()).Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+	m.EXPECT().ExportMessages(gomock.Any(), "1", nil, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _, _ *time.Time, fn func([]messagesModel.Message) error) error {
+			if err := fn(exportTestMessages()[0]); err != nil {
+				return err
+			}
+			return errors.New("connection reset")
+		}).Times(1)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serveExport(t, c, m, "/v1/chats/1/export")
+	})
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// chatFields are the fields of a /v1 chat a client can select with ?fields=.
+var chatFields = map[string]bool{
+	"id":              true,
+	"title":           true,
+	"created_at":      true,
+	"last_seq":        true,
+	"message_ttl":     true,
+	"retention_days":  true,
+	"legal_hold":      true,
+	"messages":        true,
+	"unread_count":    true,
+	"first_unread_id": true,
+}
+
+// chatFieldsV2 are the fields of a /v2 ChatResponse a client can select with ?fields=.
+var chatFieldsV2 = map[string]bool{
+	"id":         true,
+	"title":      true,
+	"created_at": true,
+	"messages":   true,
+}
+
+// includes are the related resources a client can embed with ?include=.
+var includes = map[string]bool{
+	"messages": true,
+}
+
+// selection is the part of a chat a client asked for with the fields and include
+// query parameters. Without either parameter the whole chat, messages included,
+// is returned.
+type selection struct {
+	// known are the fields of the chat representation selected from.
+	known map[string]bool
+	// fields is nil when every field is selected.
+	fields   map[string]bool
+	messages bool
+}
+
+// parseSelection reads the selection of a request for a chat that has the known
+// fields, rejecting any other.
+func parseSelection(r *http.Request, known map[string]bool) (selection, error) {
+	q := r.URL.Query()
+
+	sel := selection{known: known, messages: true}
+
+	_, hasFields := q["fields"]
+	_, hasInclude := q["include"]
+	if !hasFields && !hasInclude {
+		return sel, nil
+	}
+
+	sel.messages = false
+
+	if hasFields {
+		sel.fields = map[string]bool{}
+		for _, f := range splitList(q.Get("fields")) {
+			if !known[f] {
+				return selection{}, errors.ErrValidation.Wrap(errors.Error("unknown field " + f))
+			}
+			sel.fields[f] = true
+		}
+		sel.messages = sel.fields["messages"]
+	}
+
+	for _, inc := range splitList(q.Get("include")) {
+		if !includes[inc] {
+			return selection{}, errors.ErrValidation.Wrap(errors.Error("unknown include " + inc))
+		}
+		sel.messages = true
+	}
+
+	return sel, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// options returns the ChatService options loading only what the selection needs.
+func (s selection) options() []chats.GetChatOption {
+	if s.messages {
+		return nil
+	}
+	return []chats.GetChatOption{chats.WithoutMessages()}
+}
+
+// apply limits a chat response to the selected fields.
+func (s selection) apply(v interface{}) interface{} {
+	if s.fields == nil && s.messages {
+		return v
+	}
+
+	keep := map[string]bool{}
+	for f := range s.known {
+		keep[f] = s.fields == nil || s.fields[f]
+	}
+	keep["messages"] = s.messages
+
+	return sparse{keep: keep, value: v}
+}
+
+// sparse is a response reduced to some of its fields. JSON and MessagePack bodies
+// omit the other fields, protobuf bodies leave them unset.
+type sparse struct {
+	keep  map[string]bool
+	value interface{}
+}
+
+// fields returns the kept fields of value keyed by their json names. Like in
+// JSON, empty omitempty fields are left out.
+func (s sparse) fields() map[string]interface{} {
+	v := reflect.Indirect(reflect.ValueOf(s.value))
+	t := v.Type()
+
+	res := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if !s.keep[name] || strings.Contains(opts, "omitempty") && v.Field(i).IsZero() {
+			continue
+		}
+		res[name] = v.Field(i).Interface()
+	}
+
+	return res
+}
+
+func (s sparse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.fields())
+}
+
+func (s sparse) MarshalMsgpack() ([]byte, error) {
+	return codec.MessagePack{}.Marshal(s.fields())
+}
+
+func (s sparse) ToProto() proto.Message {
+	msg := toProto(s.value)
+	if msg == nil {
+		return nil
+	}
+
+	m := msg.ProtoReflect()
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !s.keep[string(fd.Name())] {
+			m.Clear(fd)
+		}
+		return true
+	})
+
+	return msg
+}
@@ -0,0 +1,149 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func fieldsTestChat() *chatModel.Chat {
+	return &chatModel.Chat{
+		ID:        pointer.ToString("1"),
+		Title:     pointer.ToString("general"),
+		CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Messages: []messagesModel.Message{
+			{ID: pointer.ToString("2"), ChatID: pointer.ToString("1"), Text: pointer.ToString("hi")},
+		},
+	}
+}
+
+func TestServer_GetChat_Fields(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		withMessages bool
+		wantKeys     []string
+	}{
+		{
+			name:         "everything by default",
+			url:          "/v1/chats/1",
+			withMessages: true,
+			wantKeys:     []string{"id", "title", "created_at", "messages"},
+		},
+		{
+			name:     "fields only",
+			url:      "/v1/chats/1?fields=id,title",
+			wantKeys: []string{"id", "title"},
+		},
+		{
+			name:         "fields with include",
+			url:          "/v1/chats/1?fields=title&include=messages",
+			withMessages: true,
+			wantKeys:     []string{"title", "messages"},
+		},
+		{
+			name:         "messages selected as a field",
+			url:          "/v1/chats/1?fields=id,messages",
+			withMessages: true,
+			wantKeys:     []string{"id", "messages"},
+		},
+		{
+			name:     "empty include",
+			url:      "/v1/chats/1?include=",
+			wantKeys: []string{"id", "title", "created_at"},
+		},
+		{
+			name:     "v2 fields only",
+			url:      "/v2/chats/1?fields=id,created_at",
+			wantKeys: []string{"id", "created_at"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "2"), nil).Times(1)
+
+			chat := fieldsTestChat()
+			if tt.withMessages {
+				c.EXPECT().GetChat(gomock.Any(), "1", int64(20)).Return(chat, nil).Times(1)
+			} else {
+				chat.Messages = nil
+				c.EXPECT().GetChat(gomock.Any(), "1", int64(20), gomock.Any()).Return(chat, nil).Times(1)
+			}
+
+			w := getChatWithHeaders(t, c, tt.url, nil)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var res struct {
+				Data map[string]json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+			var keys []string
+			for k := range res.Data {
+				keys = append(keys, k)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys)
+		})
+	}
+}
+
+func TestServer_GetChatV2_FieldsProtobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chat := fieldsTestChat()
+	chat.Messages = nil
+
+	c := mocks.NewMockChat(ctrl)
+	c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "2"), nil).Times(1)
+	c.EXPECT().GetChat(gomock.Any(), "1", int64(20), gomock.Any()).Return(chat, nil).Times(1)
+
+	w := getChatWithHeaders(t, c, "/v2/chats/1?fields=title", map[string]string{"Accept": "application/x-protobuf"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res pb.Chat
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "general", res.GetTitle())
+	assert.Empty(t, res.GetId())
+	assert.Nil(t, res.GetCreatedAt())
+}
+
+func TestServer_GetChat_UnknownField(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "v1 unknown field", url: "/v1/chats/1?fields=id,secret"},
+		{name: "v1 unknown include", url: "/v1/chats/1?include=hooks"},
+		{name: "v2 unknown field", url: "/v2/chats/1?fields=author"},
+		{name: "v2 field only v1 has", url: "/v2/chats/1?fields=id,last_seq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			w := getChatWithHeaders(t, mocks.NewMockChat(ctrl), tt.url, nil)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks"
+	hookModel "github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const postHookURL = "/v1/hooks/%s/%s"
+
+func TestServer_PostHook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	d := mocks.NewMockDB(ctrl)
+	hk := mocks.NewMockHook(ctrl)
+
+	ht := httptransport.New(c, m, d, httptransport.WithHooks(hk))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	want := messagesModel.Message{
+		ID:     pointer.ToString("9"),
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("build passed"),
+		Author: pointer.ToString("ci"),
+	}
+
+	hk.EXPECT().
+		Post(gomock.Any(), "5", "token", &hookModel.Payload{Text: pointer.ToString("build passed")}).
+		Return(&messagesModel.Posted{Message: &want}, nil).Times(1)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(postHookURL, "5", "token"), bytes.NewBufferString(`{"text":"build passed"}`))
+	require.NoError(t, err)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Data messagesModel.Message `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, want, res.Data)
+}
+
+func TestServer_PostHook_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "not found",
+			err:      hooks.ErrHookNotFound,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "revoked",
+			err:      hooks.ErrHookRevoked,
+			wantCode: http.StatusGone,
+		},
+		{
+			name:     "rate limited",
+			err:      hooks.ErrRateLimited,
+			wantCode: http.StatusTooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			d := mocks.NewMockDB(ctrl)
+			hk := mocks.NewMockHook(ctrl)
+
+			ht := httptransport.New(c, m, d, httptransport.WithHooks(hk))
+
+			r := mux.NewRouter()
+			require.NoError(t, ht.AddRoutes(r))
+
+			hk.EXPECT().Post(gomock.Any(), "5", "token", gomock.Any()).Return(nil, tt.err).Times(1)
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(postHookURL, "5", "token"), bytes.NewBufferString(`{"text":"hi"}`))
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var res struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.NotEmpty(t, res.Error)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) createHook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var h model.Hook
+	if err := decodeBody(r, &h); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	created, err := s.hook.CreateHook(ctx, mux.Vars(r)["id"], &model.Hook{
+		Name:      h.Name,
+		RateLimit: h.RateLimit,
+	})
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, created)
+}
+
+func (s *Server) listHooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	hooks, err := s.hook.ListHooks(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, hooks)
+}
+
+func (s *Server) revokeHook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if err := s.hook.RevokeHook(ctx, vars["id"], vars["hook_id"]); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, "revoked")
+}
+
+func (s *Server) postHook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	var p model.Payload
+	if err := decodeBody(r, &p); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	posted, err := s.hook.Post(ctx, vars["id"], vars["token"], &p)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handlePosted(ctx, w, posted)
+}
@@ -1,58 +1,335 @@
-//go:generate mockgen -destination=./mocks/http_mock.go -package mocks github.com/Polilo-User/test-task-hitalent/internal/transport/http Users,DB
+//go:generate mockgen -destination=./mocks/http_mock.go -package mocks github.com/Polilo-User/test-task-hitalent/internal/transport/http Chat,Message,DB,Webhook,Hook,Import,Idempotency,Attachment,Notification,Read,Sync,Scheduled,Retention
 
 package http
 
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"io"
 	"net/http"
+	"time"
 
+	atmodel "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	cgmodel "github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
 	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	hkmodel "github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	idmodel "github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+	immodel "github.com/Polilo-User/test-task-hitalent/internal/imports/model"
 	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	ntmodel "github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	rtmodel "github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+	scmodel "github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+	whmodel "github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
 )
 
-// go:generate mockgen -destination=./mocks/http_mock.go -package=mocks github.com/Polilo-User/test-task-hitalent/internal/transport/http Chat,Message,DB
-
 type Chat interface {
 	CreateChat(ctx context.Context, chat *chmodel.Chat) (*chmodel.Chat, error)
-	GetChat(ctx context.Context, id string, limit int64) (*chmodel.Chat, error)
+	GetChat(ctx context.Context, id string, limit int64, opts ...chats.GetChatOption) (*chmodel.Chat, error)
+	GetChatVersion(ctx context.Context, id string) (*chmodel.Version, error)
+	SetMessageTTL(ctx context.Context, id string, ttl *int64) (*chmodel.Chat, error)
+	SetRetention(ctx context.Context, id string, days *int64) (*chmodel.Chat, error)
+	SetLegalHold(ctx context.Context, id string, hold bool) (*chmodel.Chat, error)
+	CheckLegalHold(ctx context.Context, id string) error
 	DeleteChat(ctx context.Context, id string) error
 }
 
 type Message interface {
-	CreateMessage(ctx context.Context, message *msmodel.Message) (*msmodel.Message, error)
+	CreateMessage(ctx context.Context, message *msmodel.Message) (*msmodel.Posted, error)
+	CreateMessages(ctx context.Context, chatID string, ms []msmodel.Message, atomic bool) (*msmodel.Batch, error)
+	ExportMessages(ctx context.Context, chatID string, from, to *time.Time, fn func([]msmodel.Message) error) error
 	GetMessagesByChat(ctx context.Context, id string, limit int64) ([]msmodel.Message, error)
+	UpdateMessage(ctx context.Context, chatID, id, text string) (*msmodel.Message, error)
+	DeleteMessage(ctx context.Context, chatID, id string) error
+	ListMessages(ctx context.Context, chatID string, afterSeq, beforeSeq *int64, limit int64) ([]msmodel.Message, error)
 }
 
 type DB interface {
 	DB() (*sql.DB, error)
 }
 
+type Webhook interface {
+	CreateWebhook(ctx context.Context, sub *whmodel.Subscription) (*whmodel.Subscription, error)
+	GetWebhook(ctx context.Context, id string) (*whmodel.Subscription, error)
+	ListWebhooks(ctx context.Context) ([]whmodel.Subscription, error)
+	UpdateWebhook(ctx context.Context, id string, sub *whmodel.Subscription) (*whmodel.Subscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, webhookID string, limit int64) ([]whmodel.Delivery, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID string) (*whmodel.Delivery, error)
+}
+
+type Hook interface {
+	CreateHook(ctx context.Context, chatID string, hook *hkmodel.Hook) (*hkmodel.Hook, error)
+	ListHooks(ctx context.Context, chatID string) ([]hkmodel.Hook, error)
+	RevokeHook(ctx context.Context, chatID, id string) error
+	Post(ctx context.Context, id, token string, p *hkmodel.Payload) (*msmodel.Posted, error)
+}
+
+type Import interface {
+	CreateJob(ctx context.Context, source string, dryRun bool, archive []byte) (*immodel.Job, error)
+	GetJob(ctx context.Context, id string) (*immodel.Job, error)
+}
+
+type Idempotency interface {
+	Begin(ctx context.Context, key, fingerprint string) (*idmodel.Key, error)
+	Complete(ctx context.Context, key string, status int, header map[string][]string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+type Attachment interface {
+	Upload(ctx context.Context, chatID, messageID, name string, r io.Reader) (*atmodel.Attachment, error)
+	ListAttachments(ctx context.Context, chatID, messageID string) ([]atmodel.Attachment, error)
+	GetAttachment(ctx context.Context, id string) (*atmodel.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	Open(ctx context.Context, id, expires, signature string) (*atmodel.Attachment, io.ReadSeekCloser, error)
+	OpenThumbnail(ctx context.Context, id, name, expires, signature string) (*atmodel.Thumbnail, io.ReadSeekCloser, error)
+}
+
+type Notification interface {
+	ListNotifications(ctx context.Context, username string, limit int64, before string, unread bool) ([]ntmodel.Notification, error)
+	MarkRead(ctx context.Context, username, id string) (*ntmodel.Notification, error)
+	MarkAllRead(ctx context.Context, username string) (int64, error)
+}
+
+type Read interface {
+	MarkRead(ctx context.Context, username, chatID, messageID string) (*rdmodel.Cursor, error)
+	Unread(ctx context.Context, username string, chatIDs []string) ([]rdmodel.Unread, error)
+}
+
+type Scheduled interface {
+	Schedule(ctx context.Context, m *scmodel.ScheduledMessage) (*scmodel.ScheduledMessage, error)
+	ListScheduled(ctx context.Context, chatID string) ([]scmodel.ScheduledMessage, error)
+	UpdateScheduled(ctx context.Context, chatID, id string, text *string, sendAt *time.Time) (*scmodel.ScheduledMessage, error)
+	CancelScheduled(ctx context.Context, chatID, id string) error
+}
+
+type Sync interface {
+	Sync(ctx context.Context, since string, limit int64) (*cgmodel.Page, error)
+}
+
+type Retention interface {
+	ListPurges(ctx context.Context, chatID string, limit int64, before string) ([]rtmodel.Purge, error)
+}
+
 type Server struct {
 	chat    Chat
 	message Message
 	db      DB
+	webhook Webhook
+	hook    Hook
+	graphql http.Handler
+	imports Import
+
+	attachment Attachment
+
+	notification Notification
+
+	read Read
+
+	sync Sync
+
+	scheduled Scheduled
+
+	retention Retention
+
+	idempotency Idempotency
+
+	importMaxSize int64
+
+	attachmentMaxSize int64
+
+	validate *validator.Validate
+}
+
+// Option configures optional parts of the API served by Server.
+type Option func(*Server)
+
+// WithWebhooks enables the webhook subscription routes.
+func WithWebhooks(w Webhook) Option {
+	return func(s *Server) {
+		s.webhook = w
+	}
+}
+
+// WithHooks enables incoming webhooks and their management routes.
+func WithHooks(h Hook) Option {
+	return func(s *Server) {
+		s.hook = h
+	}
+}
+
+// WithGraphQL serves the given GraphQL handler on /graphql.
+func WithGraphQL(h http.Handler) Option {
+	return func(s *Server) {
+		s.graphql = h
+	}
+}
+
+// WithImports enables importing chats from export archives of up to maxSize bytes.
+func WithImports(i Import, maxSize int64) Option {
+	return func(s *Server) {
+		s.imports = i
+		s.importMaxSize = maxSize
+	}
+}
+
+// WithIdempotency lets clients retry POST requests safely by sending an
+// Idempotency-Key header.
+func WithIdempotency(i Idempotency) Option {
+	return func(s *Server) {
+		s.idempotency = i
+	}
+}
+
+// WithAttachments enables attaching files of up to maxSize bytes to messages.
+func WithAttachments(a Attachment, maxSize int64) Option {
+	return func(s *Server) {
+		s.attachment = a
+		s.attachmentMaxSize = maxSize
+	}
 }
 
-func New(c Chat, m Message, db DB) *Server {
-	return &Server{
+// WithNotifications enables the notification inbox of the caller under /v1/me.
+func WithNotifications(n Notification) Option {
+	return func(s *Server) {
+		s.notification = n
+	}
+}
+
+// WithReads tracks how far users have read chats, and tells identified users
+// how much of a chat they have not read yet when they read it.
+func WithReads(r Read) Option {
+	return func(s *Server) {
+		s.read = r
+	}
+}
+
+// WithSync lets clients catch up on the changes to chats and messages they
+// missed while offline.
+func WithSync(sy Sync) Option {
+	return func(s *Server) {
+		s.sync = sy
+	}
+}
+
+// WithScheduling lets clients write messages now to have them posted later.
+func WithScheduling(sc Scheduled) Option {
+	return func(s *Server) {
+		s.scheduled = sc
+	}
+}
+
+// WithRetention lets operators review what the retention job purged.
+func WithRetention(rt Retention) Option {
+	return func(s *Server) {
+		s.retention = rt
+	}
+}
+
+func New(c Chat, m Message, db DB, opts ...Option) *Server {
+	s := &Server{
 		chat:    c,
 		message: m,
 		db:      db,
+
+		validate: validator.New(),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *Server) AddRoutes(r *mux.Router) error {
+	validate, err := addOpenAPIRoutes(r)
+	if err != nil {
+		return err
+	}
+
 	r.HandleFunc("/health", s.healthCheck).Methods(http.MethodGet)
 
+	if s.graphql != nil {
+		r.Handle("/graphql", s.idempotent(handleError, maxBatchBodySize)(validate(s.graphql))).Methods(http.MethodPost)
+	}
+
+	s.addV2Routes(r, validate)
+	s.addExportRoutes(r, validate)
+	s.addImportRoutes(r, validate)
+	s.addAttachmentRoutes(r, validate)
+
 	r = r.PathPrefix("/v1").Subrouter()
+	r.Use(negotiate(newRegistryV1(), handleError), s.idempotent(handleError, maxBatchBodySize), validate)
 
 	r.HandleFunc("/chats/", s.createChat).Methods(http.MethodPost)                  // Done
 	r.HandleFunc("/chats/{id}", s.getChat).Methods(http.MethodGet)                  // Done
 	r.HandleFunc("/chats/{id}", s.deleteChat).Methods(http.MethodDelete)            // Done
 	r.HandleFunc("/chats/{id}/messages/", s.createMessage).Methods(http.MethodPost) // Done
+	r.HandleFunc("/chats/{id}", s.updateChat).Methods(http.MethodPatch)
+	r.HandleFunc("/chats/{id}/retention", s.setRetention).Methods(http.MethodPut)
+	r.HandleFunc("/chats/{id}/legal-hold", s.placeLegalHold).Methods(http.MethodPut)
+	r.HandleFunc("/chats/{id}/legal-hold", s.liftLegalHold).Methods(http.MethodDelete)
+	r.HandleFunc("/chats/{id}/messages/", s.listMessages).Methods(http.MethodGet)
+	r.HandleFunc("/chats/{id}/messages:batch", s.createMessages).Methods(http.MethodPost)
+	r.HandleFunc("/chats/{id}/messages/{message_id}", s.updateMessage).Methods(http.MethodPatch)
+	r.HandleFunc("/chats/{id}/messages/{message_id}", s.deleteMessage).Methods(http.MethodDelete)
+
+	if s.webhook != nil {
+		r.HandleFunc("/webhooks/", s.createWebhook).Methods(http.MethodPost)
+		r.HandleFunc("/webhooks/", s.listWebhooks).Methods(http.MethodGet)
+		r.HandleFunc("/webhooks/{id}", s.getWebhook).Methods(http.MethodGet)
+		r.HandleFunc("/webhooks/{id}", s.updateWebhook).Methods(http.MethodPut)
+		r.HandleFunc("/webhooks/{id}", s.deleteWebhook).Methods(http.MethodDelete)
+		r.HandleFunc("/webhooks/{id}/deliveries", s.listDeliveries).Methods(http.MethodGet)
+		r.HandleFunc("/webhooks/{id}/deliveries/{delivery_id}/redeliver", s.redeliver).Methods(http.MethodPost)
+	}
+
+	if s.hook != nil {
+		r.HandleFunc("/chats/{id}/hooks/", s.createHook).Methods(http.MethodPost)
+		r.HandleFunc("/chats/{id}/hooks/", s.listHooks).Methods(http.MethodGet)
+		r.HandleFunc("/chats/{id}/hooks/{hook_id}", s.revokeHook).Methods(http.MethodDelete)
+		r.HandleFunc("/hooks/{id}/{token}", s.postHook).Methods(http.MethodPost)
+	}
+
+	if s.imports != nil {
+		r.HandleFunc("/imports/{id}", s.getImport).Methods(http.MethodGet)
+	}
+
+	if s.attachment != nil {
+		r.HandleFunc("/chats/{id}/messages/{message_id}/attachments/", s.listAttachments).Methods(http.MethodGet)
+		r.HandleFunc("/attachments/{id}", s.getAttachment).Methods(http.MethodGet)
+		r.HandleFunc("/attachments/{id}", s.deleteAttachment).Methods(http.MethodDelete)
+	}
+
+	if s.notification != nil {
+		r.HandleFunc("/me/notifications", s.listNotifications).Methods(http.MethodGet)
+		r.HandleFunc("/me/notifications:read-all", s.markAllNotificationsRead).Methods(http.MethodPost)
+		r.HandleFunc("/me/notifications/{id}/read", s.markNotificationRead).Methods(http.MethodPost)
+	}
+
+	if s.read != nil {
+		r.HandleFunc("/chats/{id}/read", s.markChatRead).Methods(http.MethodPost)
+	}
+
+	if s.sync != nil {
+		r.HandleFunc("/sync", s.syncChanges).Methods(http.MethodGet)
+	}
+
+	if s.scheduled != nil {
+		r.HandleFunc("/chats/{id}/scheduled/", s.listScheduled).Methods(http.MethodGet)
+		r.HandleFunc("/chats/{id}/scheduled/{scheduled_id}", s.updateScheduled).Methods(http.MethodPatch)
+		r.HandleFunc("/chats/{id}/scheduled/{scheduled_id}", s.cancelScheduled).Methods(http.MethodDelete)
+	}
+
+	if s.retention != nil {
+		r.HandleFunc("/retention/purges", s.listPurges).Methods(http.MethodGet)
+	}
 
 	return nil
 }
@@ -72,22 +349,18 @@ func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleResponse(ctx context.Context, w http.ResponseWriter, data interface{}) {
-	jsonRes := struct {
-		Data interface{} `json:"data"`
-	}{
-		Data: data,
-	}
+	c := responseCodec(ctx)
 
-	dataBytes, err := json.Marshal(jsonRes)
+	dataBytes, err := c.Marshal(DataResponse{Data: data})
 	if err != nil {
 		handleError(ctx, w, err)
 		return
 	}
 
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(http.StatusOK)
+
 	if _, err := w.Write(dataBytes); err != nil {
-		handleError(ctx, w, err)
-		return
+		logging.From(ctx).Error("failed to write response", zap.Error(err))
 	}
-
-	w.WriteHeader(http.StatusOK)
 }
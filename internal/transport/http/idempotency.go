@@ -0,0 +1,192 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	idmodel "github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client chosen key identifying a request
+	// across retries.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// ErrBodyTooLarge is returned when an idempotent request has a body larger than
+// its route accepts.
+const ErrBodyTooLarge = errors.Error("body_too_large: request body is too large")
+
+// idempotent makes POST requests sent with an Idempotency-Key header safe to retry.
+// The response to the first request with a key is stored and replayed for retries
+// with the same method, URI, body and negotiated response type, while other
+// requests using the key are rejected, so a retry is never answered in an
+// encoding it did not accept. Server errors are not stored, so a retry after one is processed again.
+//
+// The body is fingerprinted while it is spooled to a temporary file, so bodies of
+// up to limit bytes are accepted without being held in memory.
+//
+// It runs after content negotiation, so rejections are encoded as the client asked.
+func (s *Server) idempotent(onError func(context.Context, http.ResponseWriter, error), limit int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if s.idempotency == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			body, fp, err := spool(r, responseCodec(ctx).ContentType(), http.MaxBytesReader(w, r.Body, limit))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					onError(ctx, w, ErrBodyTooLarge.Wrap(err))
+					return
+				}
+				onError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+				return
+			}
+			defer func() {
+				body.Close()
+				os.Remove(body.Name())
+			}()
+			r.Body = io.NopCloser(body)
+
+			stored, err := s.idempotency.Begin(ctx, key, fp)
+			if err != nil {
+				onError(ctx, w, err)
+				return
+			}
+			if stored != nil {
+				replay(ctx, w, stored)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			completed := false
+
+			// The outcome is recorded even when the client has gone away, since it
+			// is what a retry will be answered with.
+			ctx = context.WithoutCancel(ctx)
+			defer func() {
+				if completed {
+					return
+				}
+				if err := s.idempotency.Release(ctx, key); err != nil {
+					logging.From(ctx).Error("failed to release idempotency key", zap.Error(err))
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status() >= http.StatusInternalServerError {
+				return
+			}
+
+			err = s.idempotency.Complete(ctx, key, rec.status(), rec.Header().Clone(), rec.body.Bytes())
+			if err != nil {
+				logging.From(ctx).Error("failed to store idempotent response", zap.Error(err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// spool copies body to a temporary file rewound to its start, returning it along
+// with a fingerprint identifying the request by its method, URI, the content type
+// of its response and its body.
+func spool(r *http.Request, contentType string, body io.Reader) (*os.File, string, error) {
+	f, err := os.CreateTemp("", "idempotent-*")
+	if err != nil {
+		return nil, "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.Method + "\x00" + r.URL.RequestURI() + "\x00" + contentType + "\x00"))
+
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, "", err
+	}
+
+	return f, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func replay(ctx context.Context, w http.ResponseWriter, k *idmodel.Key) {
+	var header http.Header
+	if len(k.Header) > 0 {
+		if err := json.Unmarshal(k.Header, &header); err != nil {
+			logging.From(ctx).Error("failed to read stored idempotent response headers", zap.Error(err))
+		}
+	}
+
+	for name, values := range header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(pointer.GetInt(k.StatusCode))
+
+	if _, err := w.Write(k.Body); err != nil {
+		logging.From(ctx).Error("failed to write response", zap.Error(err))
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *responseRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
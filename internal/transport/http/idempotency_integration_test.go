@@ -0,0 +1,269 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/idempotency"
+	idempotencyModel "github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const createChatBody = `{"title":"testChat"}`
+
+func serveIdempotent(t *testing.T, c *mocks.MockChat, idem *mocks.MockIdempotency, url, key string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(c, mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl), httptransport.WithIdempotency(idem))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(createChatBody))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(httptransport.IdempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_Idempotency_Success(t *testing.T) {
+	created := &chatModel.Chat{
+		ID:        pointer.ToString("1"),
+		Title:     pointer.ToString("testChat"),
+		CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	createdBody := `{"data":{"id":"1","title":"testChat","created_at":"2020-01-01T00:00:00Z","messages":null}}`
+
+	tests := []struct {
+		name         string
+		url          string
+		key          string
+		setup        func(c *mocks.MockChat, idem *mocks.MockIdempotency)
+		wantCode     int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{
+			name: "without a key",
+			url:  baseChatURL,
+			setup: func(c *mocks.MockChat, idem *mocks.MockIdempotency) {
+				c.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(created, nil).Times(1)
+			},
+			wantCode: http.StatusOK,
+			wantBody: createdBody,
+		},
+		{
+			name: "first request",
+			url:  baseChatURL,
+			key:  "retry-1",
+			setup: func(c *mocks.MockChat, idem *mocks.MockIdempotency) {
+				var fingerprint string
+				gomock.InOrder(
+					idem.EXPECT().Begin(gomock.Any(), "retry-1", gomock.Any()).
+						DoAndReturn(func(ctx context.Context, key, fp string) (*idempotencyModel.Key, error) {
+							fingerprint = fp
+							return nil, nil
+						}).Times(1),
+					c.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(created, nil).Times(1),
+					idem.EXPECT().Complete(gomock.Any(), "retry-1", http.StatusOK, gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, key string, status int, header map[string][]string, body []byte) error {
+							assert.Len(t, fingerprint, 64)
+							assert.Equal(t, []string{"application/json"}, header["Content-Type"])
+							assert.JSONEq(t, createdBody, string(body))
+							return nil
+						}).Times(1),
+				)
+			},
+			wantCode: http.StatusOK,
+			wantBody: createdBody,
+		},
+		{
+			name: "retry",
+			url:  baseChatURL,
+			key:  "retry-1",
+			setup: func(c *mocks.MockChat, idem *mocks.MockIdempotency) {
+				idem.EXPECT().Begin(gomock.Any(), "retry-1", gomock.Any()).Return(&idempotencyModel.Key{
+					Key:        pointer.ToString("retry-1"),
+					StatusCode: pointer.ToInt(http.StatusOK),
+					Header:     json.RawMessage(`{"Content-Type":["application/json"]}`),
+					Body:       []byte(createdBody),
+				}, nil).Times(1)
+			},
+			wantCode:     http.StatusOK,
+			wantBody:     createdBody,
+			wantReplayed: true,
+		},
+		{
+			name: "server error",
+			url:  baseChatURL,
+			key:  "retry-1",
+			setup: func(c *mocks.MockChat, idem *mocks.MockIdempotency) {
+				idem.EXPECT().Begin(gomock.Any(), "retry-1", gomock.Any()).Return(nil, nil).Times(1)
+				c.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(nil, errors.New("test fail")).Times(1)
+				idem.EXPECT().Release(gomock.Any(), "retry-1").Return(nil).Times(1)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"error":"test fail"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			idem := mocks.NewMockIdempotency(ctrl)
+			tt.setup(c, idem)
+
+			w := serveIdempotent(t, c, idem, tt.url, tt.key)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			if tt.wantReplayed {
+				assert.Equal(t, "true", w.Header().Get(httptransport.IdempotentReplayedHeader))
+			} else {
+				assert.Empty(t, w.Header().Get(httptransport.IdempotentReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestServer_Idempotency_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "different request",
+			url:      baseChatURL,
+			err:      idempotency.ErrKeyReused,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `{"error":"idempotency_key_reused: idempotency key was already used for a different request"}`,
+		},
+		{
+			name:     "in progress",
+			url:      baseChatURL,
+			err:      idempotency.ErrKeyInProgress,
+			wantCode: http.StatusConflict,
+			wantBody: `{"error":"idempotency_key_in_progress: a request with this idempotency key is still being processed"}`,
+		},
+		{
+			name:     "invalid key",
+			url:      baseChatURL,
+			err:      idempotency.ErrInvalidKey,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"invalid_idempotency_key: idempotency key must be 1 to 255 printable ascii characters"}`,
+		},
+		{
+			name:     "different request on v2",
+			url:      "/v2/chats",
+			err:      idempotency.ErrKeyReused,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `{"error":{"code":"idempotency_key_reused","message":"idempotency key was already used for a different request"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			idem := mocks.NewMockIdempotency(ctrl)
+			idem.EXPECT().Begin(gomock.Any(), "retry-1", gomock.Any()).Return(nil, tt.err).Times(1)
+
+			w := serveIdempotent(t, c, idem, tt.url, "retry-1")
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Idempotency_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The body is rejected before the key is looked at.
+	idem := mocks.NewMockIdempotency(ctrl)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl),
+		httptransport.WithIdempotency(idem),
+		httptransport.WithImports(mocks.NewMockImport(ctrl), importMaxSize),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/imports/?source=slack", bytes.NewReader(bytes.Repeat([]byte("x"), importMaxSize+1)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/zip")
+	req.Header.Set(httptransport.IdempotencyKeyHeader, "retry-1")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"body_too_large: request body is too large"}`, w.Body.String())
+}
+
+func TestServer_Idempotency_Accept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idem := mocks.NewMockIdempotency(ctrl)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl), httptransport.WithIdempotency(idem))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	var fingerprints []string
+	idem.EXPECT().Begin(gomock.Any(), "retry-1", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key, fp string) (*idempotencyModel.Key, error) {
+			fingerprints = append(fingerprints, fp)
+			return nil, idempotency.ErrKeyInProgress
+		}).Times(3)
+
+	for _, accept := range []string{"", "application/json", "application/msgpack"} {
+		req, err := http.NewRequest(http.MethodPost, baseChatURL, bytes.NewBufferString(createChatBody))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(httptransport.IdempotencyKeyHeader, "retry-1")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// A retry asking for another encoding is a different request, so the JSON
+	// response stored for the key is not replayed to it.
+	require.Len(t, fingerprints, 3)
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports"
+	"github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+)
+
+// addImportRoutes registers the archive upload on its own subrouter. Archives are
+// zip files or export documents rather than API payloads, so content negotiation
+// is skipped.
+func (s *Server) addImportRoutes(r *mux.Router, validate mux.MiddlewareFunc) {
+	if s.imports == nil {
+		return
+	}
+
+	r = r.PathPrefix("/v1").Subrouter()
+	r.Use(s.idempotent(handleError, s.importMaxSize), validate)
+
+	r.HandleFunc("/imports/", s.createImport).Methods(http.MethodPost)
+}
+
+// createImport queues the import of the archive in the body and answers with the
+// job, whose progress is polled on the URL in the Location header.
+func (s *Server) createImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	dryRun := false
+	if v := q.Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("invalid dry_run parameter")))
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.importMaxSize)
+
+	archive, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(ctx, w, imports.ErrArchiveTooLarge.Wrap(err))
+			return
+		}
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	job, err := s.imports.CreateJob(ctx, q.Get("source"), dryRun, archive)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/imports/"+pointer.GetString(job.ID))
+	writeBody(ctx, w, http.StatusAccepted, struct {
+		Data *model.Job `json:"data"`
+	}{
+		Data: job,
+	})
+}
+
+func (s *Server) getImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := s.imports.GetJob(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, job)
+}
@@ -0,0 +1,234 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/imports"
+	importModel "github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importMaxSize = 1 << 10
+
+func serveImport(t *testing.T, im *mocks.MockImport, method, url, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl),
+		httptransport.WithImports(im, importMaxSize),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_CreateImport_Success(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        []byte
+		wantSource  string
+		wantDryRun  bool
+	}{
+		{
+			name:        "slack zip",
+			url:         "/v1/imports/?source=slack",
+			contentType: "application/zip",
+			body:        []byte("PK\x03\x04 not really a zip"),
+			wantSource:  importModel.SourceSlack,
+		},
+		{
+			name:        "telegram dry run",
+			url:         "/v1/imports/?source=telegram&dry_run=true",
+			contentType: "application/json",
+			body:        []byte(`{"id": 1, "name": "team", "messages": []}`),
+			wantSource:  importModel.SourceTelegram,
+			wantDryRun:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			im := mocks.NewMockImport(ctrl)
+
+			job := &importModel.Job{
+				ID:     pointer.ToString("7"),
+				Source: pointer.ToString(tt.wantSource),
+				Status: pointer.ToString(importModel.JobPending),
+				DryRun: pointer.ToBool(tt.wantDryRun),
+			}
+
+			im.EXPECT().CreateJob(gomock.Any(), tt.wantSource, tt.wantDryRun, tt.body).Return(job, nil).Times(1)
+
+			w := serveImport(t, im, http.MethodPost, tt.url, tt.contentType, tt.body)
+
+			require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+			assert.Equal(t, "/v1/imports/7", w.Header().Get("Location"))
+
+			var res struct {
+				Data importModel.Job `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, *job, res.Data)
+		})
+	}
+}
+
+func TestServer_CreateImport_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        []byte
+		setup       func(im *mocks.MockImport)
+		wantStatus  int
+		wantError   string
+	}{
+		{
+			name:        "unknown source",
+			url:         "/v1/imports/?source=discord",
+			contentType: "application/zip",
+			body:        []byte("PK"),
+			wantStatus:  http.StatusBadRequest,
+			wantError:   "err_invalid_request: invalid request received",
+		},
+		{
+			name:        "invalid dry run",
+			url:         "/v1/imports/?source=slack&dry_run=maybe",
+			contentType: "application/zip",
+			body:        []byte("PK"),
+			wantStatus:  http.StatusBadRequest,
+			wantError:   "err_invalid_request: invalid request received",
+		},
+		{
+			name:        "too large",
+			url:         "/v1/imports/?source=slack",
+			contentType: "application/zip",
+			body:        bytes.Repeat([]byte("x"), importMaxSize+1),
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantError:   string(imports.ErrArchiveTooLarge),
+		},
+		{
+			name:        "empty archive",
+			url:         "/v1/imports/?source=slack",
+			contentType: "application/zip",
+			setup: func(im *mocks.MockImport) {
+				im.EXPECT().CreateJob(gomock.Any(), importModel.SourceSlack, false, gomock.Any()).Return(nil, imports.ErrEmptyArchive).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  string(imports.ErrEmptyArchive),
+		},
+		{
+			name:        "store failure",
+			url:         "/v1/imports/?source=slack",
+			contentType: "application/zip",
+			body:        []byte("PK"),
+			setup: func(im *mocks.MockImport) {
+				im.EXPECT().CreateJob(gomock.Any(), importModel.SourceSlack, false, []byte("PK")).Return(nil, errors.New("test fail")).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "test fail",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			im := mocks.NewMockImport(ctrl)
+			if tt.setup != nil {
+				tt.setup(im)
+			}
+
+			w := serveImport(t, im, http.MethodPost, tt.url, tt.contentType, tt.body)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var res struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.wantError, res.Error)
+		})
+	}
+}
+
+func TestServer_GetImport_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	im := mocks.NewMockImport(ctrl)
+
+	job := &importModel.Job{
+		ID:               pointer.ToString("7"),
+		Source:           pointer.ToString(importModel.SourceTelegram),
+		Status:           pointer.ToString(importModel.JobRunning),
+		DryRun:           pointer.ToBool(false),
+		ChatsTotal:       pointer.ToInt(2),
+		ChatsCreated:     pointer.ToInt(1),
+		MessagesTotal:    pointer.ToInt(3000),
+		MessagesImported: pointer.ToInt(1500),
+		MessagesSkipped:  pointer.ToInt(500),
+	}
+
+	im.EXPECT().GetJob(gomock.Any(), "7").DoAndReturn(func(ctx context.Context, id string) (*importModel.Job, error) {
+		return job, nil
+	}).Times(1)
+
+	w := serveImport(t, im, http.MethodGet, "/v1/imports/7", "", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Data importModel.Job `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, *job, res.Data)
+}
+
+func TestServer_GetImport_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	im := mocks.NewMockImport(ctrl)
+	im.EXPECT().GetJob(gomock.Any(), "7").Return(nil, imports.ErrJobNotFound).Times(1)
+
+	w := serveImport(t, im, http.MethodGet, "/v1/imports/7", "", nil)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var res struct {
+		Error string `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, string(imports.ErrJobNotFound), res.Error)
+}
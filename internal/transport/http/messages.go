@@ -1,23 +1,42 @@
 package http
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/commands"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
 	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
 	"github.com/Polilo-User/test-task-hitalent/internal/messages/model"
 
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
+// maxBatchBodySize bounds the body of a message batch, which is read into memory.
+const maxBatchBodySize = 64 << 20
+
+// PostMessageRequest is the body accepted by POST /v1/chats/{id}/messages/. Other
+// fields clients send are ignored, system among them since only the server posts
+// system messages.
+type PostMessageRequest struct {
+	Text      *string    `json:"text"`
+	Author    *string    `json:"author"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	// SendAt schedules the message to be posted later instead.
+	SendAt *time.Time `json:"send_at"`
+}
+
 func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	w.Header().Set("Content-Type", "application/json")
 
-	var c model.Message
-	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+	var c PostMessageRequest
+	if err := decodeBody(r, &c); err != nil {
 		logging.From(ctx).Error("failed to decode request body", zap.Error(err))
 		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
 		return
@@ -29,9 +48,19 @@ func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c.ChatID = &id
+	m := &model.Message{
+		ChatID:    &id,
+		Text:      c.Text,
+		Author:    c.Author,
+		ExpiresAt: c.ExpiresAt,
+	}
 
-	createdMessage, err := s.message.CreateMessage(ctx, &c)
+	if c.SendAt != nil {
+		s.scheduleMessage(w, r, m, c.SendAt)
+		return
+	}
+
+	posted, err := s.message.CreateMessage(ctx, m)
 	if err != nil {
 		logging.From(ctx).Error("failed to create message", zap.Error(err))
 
@@ -40,11 +69,160 @@ func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, commands.ErrUsage) || errors.Is(err, commands.ErrCommandFailed) || errors.Is(err, messages.ErrInvalidExpiry) {
+			handleError(ctx, w, err)
+			return
+		}
+
 		http.Error(w, `{"error":"failed to create message"}`, http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": createdMessage,
-	})
+	handlePosted(ctx, w, posted)
+}
+
+// handlePosted writes the message stored, or the first reply of the slash command
+// run instead. A command that did not reply leaves nothing to write.
+func handlePosted(ctx context.Context, w http.ResponseWriter, p *model.Posted) {
+	m := p.First()
+	if m == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	handleResponse(ctx, w, m)
+}
+
+// createMessages ingests a JSON array or NDJSON stream of messages in one go.
+// With ?atomic=true nothing is stored unless every message is valid.
+func (s *Server) createMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	atomic := false
+	if v := r.URL.Query().Get("atomic"); v != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(v); err != nil {
+			handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("invalid atomic parameter")))
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodySize)
+
+	var ms []model.Message
+	if err := decodeBody(r, &ms); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(ctx, w, messages.ErrBatchTooLarge.Wrap(err))
+			return
+		}
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	batch, err := s.message.CreateMessages(ctx, mux.Vars(r)["id"], ms, atomic)
+	if errors.Is(err, messages.ErrBatchRejected) {
+		logging.From(ctx).Error("error occurred in request", zap.Error(err))
+
+		// The results tell the client which messages to fix.
+		writeBody(ctx, w, errorStatus(err), struct {
+			Error string       `json:"error"`
+			Data  *model.Batch `json:"data"`
+		}{
+			Error: err.Error(),
+			Data:  batch,
+		})
+		return
+	}
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, batch)
+}
+
+// listMessages pages through the messages of a chat by seq. Clients catch up on
+// what they missed with after_seq, and scroll back through history with before_seq.
+func (s *Server) listMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseLimit(r, defaultMessageLimit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+	if limit > maxMessageLimit {
+		limit = maxMessageLimit
+	}
+
+	afterSeq, err := parseSeq(r, "after_seq")
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+	beforeSeq, err := parseSeq(r, "before_seq")
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	ms, err := s.message.ListMessages(ctx, mux.Vars(r)["id"], afterSeq, beforeSeq, limit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, ms)
+}
+
+// parseSeq reads an optional seq cursor from the query.
+func parseSeq(r *http.Request, name string) (*int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	seq, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || seq < 0 {
+		return nil, errors.ErrValidation.Wrap(errors.Error("invalid " + name + " parameter"))
+	}
+
+	return &seq, nil
+}
+
+// UpdateMessageRequest is the body accepted by PATCH /v1/chats/{id}/messages/{message_id}.
+type UpdateMessageRequest struct {
+	Text string `json:"text"`
+}
+
+func (s *Server) updateMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	var req UpdateMessageRequest
+	if err := decodeBody(r, &req); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	m, err := s.message.UpdateMessage(ctx, vars["id"], vars["message_id"], req.Text)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, m)
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if err := s.message.DeleteMessage(ctx, vars["id"], vars["message_id"]); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, "deleted")
 }
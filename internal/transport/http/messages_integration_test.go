@@ -0,0 +1,134 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listMessages(t *testing.T, m *mocks.MockMessage, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), m, mocks.NewMockDB(ctrl))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(messageURL, "1")+query, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_ListMessages_Success(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		afterSeq  *int64
+		beforeSeq *int64
+		limit     int64
+	}{
+		{
+			name:  "newest",
+			limit: 20,
+		},
+		{
+			name:     "catching up",
+			query:    "?after_seq=3&limit=2",
+			afterSeq: pointer.ToInt64(3),
+			limit:    2,
+		},
+		{
+			name:      "scrolling back",
+			query:     "?before_seq=10&limit=500",
+			beforeSeq: pointer.ToInt64(10),
+			limit:     100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockMessage(ctrl)
+			m.EXPECT().ListMessages(gomock.Any(), "1", tt.afterSeq, tt.beforeSeq, tt.limit).Return([]messagesModel.Message{
+				{ID: pointer.ToString("7"), ChatID: pointer.ToString("1"), Seq: pointer.ToInt64(4), Text: pointer.ToString("hi")},
+			}, nil).Times(1)
+
+			w := listMessages(t, m, tt.query)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"data":[{"id":"7","chat_id":"1","seq":4,"text":"hi","author":null,"system":null,"created_at":null}]}`,
+				w.Body.String())
+		})
+	}
+}
+
+func TestServer_ListMessages_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(m *mocks.MockMessage)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "invalid seq",
+			query:    "?after_seq=first",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:  "empty range",
+			query: "?after_seq=5&before_seq=5",
+			setup: func(m *mocks.MockMessage) {
+				m.EXPECT().ListMessages(gomock.Any(), "1", pointer.ToInt64(5), pointer.ToInt64(5), int64(20)).
+					Return(nil, messages.ErrInvalidSeqRange).Times(1)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"invalid_seq_range: after_seq must be below before_seq"}`,
+		},
+		{
+			name: "chat not found",
+			setup: func(m *mocks.MockMessage) {
+				m.EXPECT().ListMessages(gomock.Any(), "1", nil, nil, int64(20)).Return(nil, chats.ErrChatNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"chat_not_found: chat not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockMessage(ctrl)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			w := listMessages(t, m, tt.query)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/Polilo-User/test-task-hitalent/internal/transport/http (interfaces: Chat,Message,DB)
+// Source: github.com/Polilo-User/test-task-hitalent/internal/transport/http (interfaces: Chat,Message,DB,Webhook,Hook,Import,Idempotency,Attachment,Notification,Read,Sync,Scheduled,Retention)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -7,10 +7,23 @@ package mocks
 import (
 	context "context"
 	sql "database/sql"
+	io "io"
 	reflect "reflect"
+	time "time"
 
-	model "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
-	model0 "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model "github.com/Polilo-User/test-task-hitalent/internal/attachments/model"
+	model0 "github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	chats "github.com/Polilo-User/test-task-hitalent/internal/chats"
+	model1 "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	model2 "github.com/Polilo-User/test-task-hitalent/internal/hooks/model"
+	model3 "github.com/Polilo-User/test-task-hitalent/internal/idempotency/model"
+	model4 "github.com/Polilo-User/test-task-hitalent/internal/imports/model"
+	model5 "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	model6 "github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+	model7 "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	model8 "github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+	model9 "github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+	model10 "github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -37,11 +50,25 @@ func (m *MockChat) EXPECT() *MockChatMockRecorder {
 	return m.recorder
 }
 
+// CheckLegalHold mocks base method.
+func (m *MockChat) CheckLegalHold(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLegalHold", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckLegalHold indicates an expected call of CheckLegalHold.
+func (mr *MockChatMockRecorder) CheckLegalHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLegalHold", reflect.TypeOf((*MockChat)(nil).CheckLegalHold), arg0, arg1)
+}
+
 // CreateChat mocks base method.
-func (m *MockChat) CreateChat(arg0 context.Context, arg1 *model.Chat) (*model.Chat, error) {
+func (m *MockChat) CreateChat(arg0 context.Context, arg1 *model1.Chat) (*model1.Chat, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateChat", arg0, arg1)
-	ret0, _ := ret[0].(*model.Chat)
+	ret0, _ := ret[0].(*model1.Chat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -67,18 +94,83 @@ func (mr *MockChatMockRecorder) DeleteChat(arg0, arg1 interface{}) *gomock.Call
 }
 
 // GetChat mocks base method.
-func (m *MockChat) GetChat(arg0 context.Context, arg1 string, arg2 int64) (*model.Chat, error) {
+func (m *MockChat) GetChat(arg0 context.Context, arg1 string, arg2 int64, arg3 ...chats.GetChatOption) (*model1.Chat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChat", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*model.Chat)
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetChat", varargs...)
+	ret0, _ := ret[0].(*model1.Chat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetChat indicates an expected call of GetChat.
-func (mr *MockChatMockRecorder) GetChat(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockChatMockRecorder) GetChat(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockChat)(nil).GetChat), varargs...)
+}
+
+// GetChatVersion mocks base method.
+func (m *MockChat) GetChatVersion(arg0 context.Context, arg1 string) (*model1.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatVersion", arg0, arg1)
+	ret0, _ := ret[0].(*model1.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatVersion indicates an expected call of GetChatVersion.
+func (mr *MockChatMockRecorder) GetChatVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatVersion", reflect.TypeOf((*MockChat)(nil).GetChatVersion), arg0, arg1)
+}
+
+// SetLegalHold mocks base method.
+func (m *MockChat) SetLegalHold(arg0 context.Context, arg1 string, arg2 bool) (*model1.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLegalHold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model1.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLegalHold indicates an expected call of SetLegalHold.
+func (mr *MockChatMockRecorder) SetLegalHold(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLegalHold", reflect.TypeOf((*MockChat)(nil).SetLegalHold), arg0, arg1, arg2)
+}
+
+// SetMessageTTL mocks base method.
+func (m *MockChat) SetMessageTTL(arg0 context.Context, arg1 string, arg2 *int64) (*model1.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMessageTTL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model1.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMessageTTL indicates an expected call of SetMessageTTL.
+func (mr *MockChatMockRecorder) SetMessageTTL(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockChat)(nil).GetChat), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMessageTTL", reflect.TypeOf((*MockChat)(nil).SetMessageTTL), arg0, arg1, arg2)
+}
+
+// SetRetention mocks base method.
+func (m *MockChat) SetRetention(arg0 context.Context, arg1 string, arg2 *int64) (*model1.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRetention", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model1.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRetention indicates an expected call of SetRetention.
+func (mr *MockChatMockRecorder) SetRetention(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRetention", reflect.TypeOf((*MockChat)(nil).SetRetention), arg0, arg1, arg2)
 }
 
 // MockMessage is a mock of Message interface.
@@ -105,10 +197,10 @@ func (m *MockMessage) EXPECT() *MockMessageMockRecorder {
 }
 
 // CreateMessage mocks base method.
-func (m *MockMessage) CreateMessage(arg0 context.Context, arg1 *model0.Message) (*model0.Message, error) {
+func (m *MockMessage) CreateMessage(arg0 context.Context, arg1 *model5.Message) (*model5.Posted, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateMessage", arg0, arg1)
-	ret0, _ := ret[0].(*model0.Message)
+	ret0, _ := ret[0].(*model5.Posted)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -119,11 +211,54 @@ func (mr *MockMessageMockRecorder) CreateMessage(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessage)(nil).CreateMessage), arg0, arg1)
 }
 
+// CreateMessages mocks base method.
+func (m *MockMessage) CreateMessages(arg0 context.Context, arg1 string, arg2 []model5.Message, arg3 bool) (*model5.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model5.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessages indicates an expected call of CreateMessages.
+func (mr *MockMessageMockRecorder) CreateMessages(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessages", reflect.TypeOf((*MockMessage)(nil).CreateMessages), arg0, arg1, arg2, arg3)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockMessage) DeleteMessage(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockMessageMockRecorder) DeleteMessage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockMessage)(nil).DeleteMessage), arg0, arg1, arg2)
+}
+
+// ExportMessages mocks base method.
+func (m *MockMessage) ExportMessages(arg0 context.Context, arg1 string, arg2, arg3 *time.Time, arg4 func([]model5.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportMessages", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportMessages indicates an expected call of ExportMessages.
+func (mr *MockMessageMockRecorder) ExportMessages(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportMessages", reflect.TypeOf((*MockMessage)(nil).ExportMessages), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetMessagesByChat mocks base method.
-func (m *MockMessage) GetMessagesByChat(arg0 context.Context, arg1 string, arg2 int64) ([]model0.Message, error) {
+func (m *MockMessage) GetMessagesByChat(arg0 context.Context, arg1 string, arg2 int64) ([]model5.Message, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetMessagesByChat", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]model0.Message)
+	ret0, _ := ret[0].([]model5.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -134,6 +269,36 @@ func (mr *MockMessageMockRecorder) GetMessagesByChat(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByChat", reflect.TypeOf((*MockMessage)(nil).GetMessagesByChat), arg0, arg1, arg2)
 }
 
+// ListMessages mocks base method.
+func (m *MockMessage) ListMessages(arg0 context.Context, arg1 string, arg2, arg3 *int64, arg4 int64) ([]model5.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]model5.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockMessageMockRecorder) ListMessages(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockMessage)(nil).ListMessages), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockMessage) UpdateMessage(arg0 context.Context, arg1, arg2, arg3 string) (*model5.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model5.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockMessageMockRecorder) UpdateMessage(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockMessage)(nil).UpdateMessage), arg0, arg1, arg2, arg3)
+}
+
 // MockDB is a mock of DB interface.
 type MockDB struct {
 	ctrl     *gomock.Controller
@@ -171,3 +336,724 @@ func (mr *MockDBMockRecorder) DB() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DB", reflect.TypeOf((*MockDB)(nil).DB))
 }
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhook) CreateWebhook(arg0 context.Context, arg1 *model10.Subscription) (*model10.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(*model10.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhook)(nil).CreateWebhook), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhook) DeleteWebhook(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookMockRecorder) DeleteWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhook)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhook) GetWebhook(arg0 context.Context, arg1 string) (*model10.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(*model10.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhook)(nil).GetWebhook), arg0, arg1)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhook) ListDeliveries(arg0 context.Context, arg1 string, arg2 int64) ([]model10.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model10.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookMockRecorder) ListDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhook)(nil).ListDeliveries), arg0, arg1, arg2)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhook) ListWebhooks(arg0 context.Context) ([]model10.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", arg0)
+	ret0, _ := ret[0].([]model10.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookMockRecorder) ListWebhooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhook)(nil).ListWebhooks), arg0)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhook) Redeliver(arg0 context.Context, arg1, arg2 string) (*model10.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model10.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookMockRecorder) Redeliver(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhook)(nil).Redeliver), arg0, arg1, arg2)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhook) UpdateWebhook(arg0 context.Context, arg1 string, arg2 *model10.Subscription) (*model10.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model10.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookMockRecorder) UpdateWebhook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhook)(nil).UpdateWebhook), arg0, arg1, arg2)
+}
+
+// MockHook is a mock of Hook interface.
+type MockHook struct {
+	ctrl     *gomock.Controller
+	recorder *MockHookMockRecorder
+}
+
+// MockHookMockRecorder is the mock recorder for MockHook.
+type MockHookMockRecorder struct {
+	mock *MockHook
+}
+
+// NewMockHook creates a new mock instance.
+func NewMockHook(ctrl *gomock.Controller) *MockHook {
+	mock := &MockHook{ctrl: ctrl}
+	mock.recorder = &MockHookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHook) EXPECT() *MockHookMockRecorder {
+	return m.recorder
+}
+
+// CreateHook mocks base method.
+func (m *MockHook) CreateHook(arg0 context.Context, arg1 string, arg2 *model2.Hook) (*model2.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model2.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHook indicates an expected call of CreateHook.
+func (mr *MockHookMockRecorder) CreateHook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHook", reflect.TypeOf((*MockHook)(nil).CreateHook), arg0, arg1, arg2)
+}
+
+// ListHooks mocks base method.
+func (m *MockHook) ListHooks(arg0 context.Context, arg1 string) ([]model2.Hook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", arg0, arg1)
+	ret0, _ := ret[0].([]model2.Hook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHooks indicates an expected call of ListHooks.
+func (mr *MockHookMockRecorder) ListHooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockHook)(nil).ListHooks), arg0, arg1)
+}
+
+// Post mocks base method.
+func (m *MockHook) Post(arg0 context.Context, arg1, arg2 string, arg3 *model2.Payload) (*model5.Posted, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model5.Posted)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockHookMockRecorder) Post(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockHook)(nil).Post), arg0, arg1, arg2, arg3)
+}
+
+// RevokeHook mocks base method.
+func (m *MockHook) RevokeHook(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeHook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeHook indicates an expected call of RevokeHook.
+func (mr *MockHookMockRecorder) RevokeHook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeHook", reflect.TypeOf((*MockHook)(nil).RevokeHook), arg0, arg1, arg2)
+}
+
+// MockImport is a mock of Import interface.
+type MockImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportMockRecorder
+}
+
+// MockImportMockRecorder is the mock recorder for MockImport.
+type MockImportMockRecorder struct {
+	mock *MockImport
+}
+
+// NewMockImport creates a new mock instance.
+func NewMockImport(ctrl *gomock.Controller) *MockImport {
+	mock := &MockImport{ctrl: ctrl}
+	mock.recorder = &MockImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImport) EXPECT() *MockImportMockRecorder {
+	return m.recorder
+}
+
+// CreateJob mocks base method.
+func (m *MockImport) CreateJob(arg0 context.Context, arg1 string, arg2 bool, arg3 []byte) (*model4.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model4.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockImportMockRecorder) CreateJob(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockImport)(nil).CreateJob), arg0, arg1, arg2, arg3)
+}
+
+// GetJob mocks base method.
+func (m *MockImport) GetJob(arg0 context.Context, arg1 string) (*model4.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", arg0, arg1)
+	ret0, _ := ret[0].(*model4.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockImportMockRecorder) GetJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockImport)(nil).GetJob), arg0, arg1)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotency) Begin(arg0 context.Context, arg1, arg2 string) (*model3.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model3.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyMockRecorder) Begin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotency)(nil).Begin), arg0, arg1, arg2)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotency) Complete(arg0 context.Context, arg1 string, arg2 int, arg3 map[string][]string, arg4 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyMockRecorder) Complete(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotency)(nil).Complete), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Release mocks base method.
+func (m *MockIdempotency) Release(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyMockRecorder) Release(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotency)(nil).Release), arg0, arg1)
+}
+
+// MockAttachment is a mock of Attachment interface.
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment.
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance.
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockAttachment) DeleteAttachment(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockAttachmentMockRecorder) DeleteAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockAttachment)(nil).DeleteAttachment), arg0, arg1)
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttachment) GetAttachment(arg0 context.Context, arg1 string) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttachmentMockRecorder) GetAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttachment)(nil).GetAttachment), arg0, arg1)
+}
+
+// ListAttachments mocks base method.
+func (m *MockAttachment) ListAttachments(arg0 context.Context, arg1, arg2 string) ([]model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttachments", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachments indicates an expected call of ListAttachments.
+func (mr *MockAttachmentMockRecorder) ListAttachments(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachments", reflect.TypeOf((*MockAttachment)(nil).ListAttachments), arg0, arg1, arg2)
+}
+
+// Open mocks base method.
+func (m *MockAttachment) Open(arg0 context.Context, arg1, arg2, arg3 string) (*model.Attachment, io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(io.ReadSeekCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockAttachmentMockRecorder) Open(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachment)(nil).Open), arg0, arg1, arg2, arg3)
+}
+
+// OpenThumbnail mocks base method.
+func (m *MockAttachment) OpenThumbnail(arg0 context.Context, arg1, arg2, arg3, arg4 string) (*model.Thumbnail, io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenThumbnail", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.Thumbnail)
+	ret1, _ := ret[1].(io.ReadSeekCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenThumbnail indicates an expected call of OpenThumbnail.
+func (mr *MockAttachmentMockRecorder) OpenThumbnail(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenThumbnail", reflect.TypeOf((*MockAttachment)(nil).OpenThumbnail), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Upload mocks base method.
+func (m *MockAttachment) Upload(arg0 context.Context, arg1, arg2, arg3 string, arg4 io.Reader) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockAttachmentMockRecorder) Upload(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockAttachment)(nil).Upload), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockNotification is a mock of Notification interface.
+type MockNotification struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationMockRecorder
+}
+
+// MockNotificationMockRecorder is the mock recorder for MockNotification.
+type MockNotificationMockRecorder struct {
+	mock *MockNotification
+}
+
+// NewMockNotification creates a new mock instance.
+func NewMockNotification(ctrl *gomock.Controller) *MockNotification {
+	mock := &MockNotification{ctrl: ctrl}
+	mock.recorder = &MockNotificationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotification) EXPECT() *MockNotificationMockRecorder {
+	return m.recorder
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotification) ListNotifications(arg0 context.Context, arg1 string, arg2 int64, arg3 string, arg4 bool) ([]model6.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]model6.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationMockRecorder) ListNotifications(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotification)(nil).ListNotifications), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockNotification) MarkAllRead(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockNotificationMockRecorder) MarkAllRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockNotification)(nil).MarkAllRead), arg0, arg1)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotification) MarkRead(arg0 context.Context, arg1, arg2 string) (*model6.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model6.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationMockRecorder) MarkRead(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotification)(nil).MarkRead), arg0, arg1, arg2)
+}
+
+// MockRead is a mock of Read interface.
+type MockRead struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadMockRecorder
+}
+
+// MockReadMockRecorder is the mock recorder for MockRead.
+type MockReadMockRecorder struct {
+	mock *MockRead
+}
+
+// NewMockRead creates a new mock instance.
+func NewMockRead(ctrl *gomock.Controller) *MockRead {
+	mock := &MockRead{ctrl: ctrl}
+	mock.recorder = &MockReadMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRead) EXPECT() *MockReadMockRecorder {
+	return m.recorder
+}
+
+// MarkRead mocks base method.
+func (m *MockRead) MarkRead(arg0 context.Context, arg1, arg2, arg3 string) (*model7.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model7.Cursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockReadMockRecorder) MarkRead(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockRead)(nil).MarkRead), arg0, arg1, arg2, arg3)
+}
+
+// Unread mocks base method.
+func (m *MockRead) Unread(arg0 context.Context, arg1 string, arg2 []string) ([]model7.Unread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unread", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model7.Unread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unread indicates an expected call of Unread.
+func (mr *MockReadMockRecorder) Unread(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unread", reflect.TypeOf((*MockRead)(nil).Unread), arg0, arg1, arg2)
+}
+
+// MockSync is a mock of Sync interface.
+type MockSync struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncMockRecorder
+}
+
+// MockSyncMockRecorder is the mock recorder for MockSync.
+type MockSyncMockRecorder struct {
+	mock *MockSync
+}
+
+// NewMockSync creates a new mock instance.
+func NewMockSync(ctrl *gomock.Controller) *MockSync {
+	mock := &MockSync{ctrl: ctrl}
+	mock.recorder = &MockSyncMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSync) EXPECT() *MockSyncMockRecorder {
+	return m.recorder
+}
+
+// Sync mocks base method.
+func (m *MockSync) Sync(arg0 context.Context, arg1 string, arg2 int64) (*model0.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model0.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockSyncMockRecorder) Sync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockSync)(nil).Sync), arg0, arg1, arg2)
+}
+
+// MockScheduled is a mock of Scheduled interface.
+type MockScheduled struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledMockRecorder
+}
+
+// MockScheduledMockRecorder is the mock recorder for MockScheduled.
+type MockScheduledMockRecorder struct {
+	mock *MockScheduled
+}
+
+// NewMockScheduled creates a new mock instance.
+func NewMockScheduled(ctrl *gomock.Controller) *MockScheduled {
+	mock := &MockScheduled{ctrl: ctrl}
+	mock.recorder = &MockScheduledMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduled) EXPECT() *MockScheduledMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduled mocks base method.
+func (m *MockScheduled) CancelScheduled(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduled", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelScheduled indicates an expected call of CancelScheduled.
+func (mr *MockScheduledMockRecorder) CancelScheduled(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduled", reflect.TypeOf((*MockScheduled)(nil).CancelScheduled), arg0, arg1, arg2)
+}
+
+// ListScheduled mocks base method.
+func (m *MockScheduled) ListScheduled(arg0 context.Context, arg1 string) ([]model9.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", arg0, arg1)
+	ret0, _ := ret[0].([]model9.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockScheduledMockRecorder) ListScheduled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockScheduled)(nil).ListScheduled), arg0, arg1)
+}
+
+// Schedule mocks base method.
+func (m *MockScheduled) Schedule(arg0 context.Context, arg1 *model9.ScheduledMessage) (*model9.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", arg0, arg1)
+	ret0, _ := ret[0].(*model9.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockScheduledMockRecorder) Schedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockScheduled)(nil).Schedule), arg0, arg1)
+}
+
+// UpdateScheduled mocks base method.
+func (m *MockScheduled) UpdateScheduled(arg0 context.Context, arg1, arg2 string, arg3 *string, arg4 *time.Time) (*model9.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduled", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model9.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduled indicates an expected call of UpdateScheduled.
+func (mr *MockScheduledMockRecorder) UpdateScheduled(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduled", reflect.TypeOf((*MockScheduled)(nil).UpdateScheduled), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockRetention is a mock of Retention interface.
+type MockRetention struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionMockRecorder
+}
+
+// MockRetentionMockRecorder is the mock recorder for MockRetention.
+type MockRetentionMockRecorder struct {
+	mock *MockRetention
+}
+
+// NewMockRetention creates a new mock instance.
+func NewMockRetention(ctrl *gomock.Controller) *MockRetention {
+	mock := &MockRetention{ctrl: ctrl}
+	mock.recorder = &MockRetentionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetention) EXPECT() *MockRetentionMockRecorder {
+	return m.recorder
+}
+
+// ListPurges mocks base method.
+func (m *MockRetention) ListPurges(arg0 context.Context, arg1 string, arg2 int64, arg3 string) ([]model8.Purge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model8.Purge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurges indicates an expected call of ListPurges.
+func (mr *MockRetentionMockRecorder) ListPurges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurges", reflect.TypeOf((*MockRetention)(nil).ListPurges), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+
+	"github.com/gorilla/mux"
+)
+
+type codecsKey struct{}
+
+// negotiated holds the codecs chosen for a request body and its response.
+type negotiated struct {
+	request  codec.Codec
+	response codec.Codec
+}
+
+// newRegistryV1 offers protobuf responses for the chats and messages shared with
+// the gRPC API. Responses with no protobuf message are answered with 406, and
+// since no /v1 request has a protobuf message, protobuf bodies with 415.
+func newRegistryV1() *codec.Registry {
+	r := codec.NewRegistry(codec.JSON{})
+	r.Register(codec.MessagePack{}, "application/x-msgpack")
+	r.Register(codec.NDJSON{}, "application/jsonl")
+	r.RegisterResponse(codec.Protobuf{}, "application/protobuf")
+	return r
+}
+
+// newRegistryV2 adds protobuf, whose messages are shared with the gRPC API, and
+// rejects unknown fields so clients cannot set server owned properties.
+func newRegistryV2() *codec.Registry {
+	r := codec.NewRegistry(codec.JSON{Strict: true})
+	r.Register(codec.MessagePack{Strict: true}, "application/x-msgpack")
+	r.Register(codec.Protobuf{}, "application/protobuf")
+	return r
+}
+
+// negotiate picks codecs from reg for the request body and the response, answering
+// 406 or 415 before the handler runs when the client's media types are not supported.
+func negotiate(reg *codec.Registry, onError func(context.Context, http.ResponseWriter, error)) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			res, err := reg.Negotiate(r.Header.Get("Accept"))
+			if err != nil {
+				onError(ctx, w, err)
+				return
+			}
+
+			req := reg.Default()
+			if r.ContentLength != 0 {
+				req, err = reg.ForContentType(r.Header.Get("Content-Type"))
+				if err != nil {
+					onError(ctx, w, err)
+					return
+				}
+			}
+
+			ctx = context.WithValue(ctx, codecsKey{}, negotiated{request: req, response: res})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func requestCodec(ctx context.Context) codec.Codec {
+	if n, ok := ctx.Value(codecsKey{}).(negotiated); ok {
+		return n.request
+	}
+	return codec.JSON{}
+}
+
+func responseCodec(ctx context.Context) codec.Codec {
+	if n, ok := ctx.Value(codecsKey{}).(negotiated); ok {
+		return n.response
+	}
+	return codec.JSON{}
+}
+
+// decodeBody decodes the request body with the codec negotiated for it.
+func decodeBody(r *http.Request, v interface{}) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	return requestCodec(r.Context()).Unmarshal(data, v)
+}
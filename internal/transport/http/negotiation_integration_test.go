@@ -0,0 +1,290 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func serveEncoded(t *testing.T, c httptransport.Chat, m httptransport.Message, method, url, contentType, accept string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(c, m, mocks.NewMockDB(ctrl))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_CreateChat_MessagePack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().
+		CreateChat(gomock.Any(), &chatModel.Chat{Title: pointer.ToString("general")}).
+		Return(&chatModel.Chat{ID: pointer.ToString("1"), Title: pointer.ToString("general")}, nil).
+		Times(1)
+
+	body, err := msgpack.Marshal(map[string]string{"title": "general"})
+	require.NoError(t, err)
+
+	w := serveEncoded(t, c, m, http.MethodPost, "/v1/chats/", "application/msgpack", "application/msgpack", body)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	var res struct {
+		Data map[string]interface{} `msgpack:"data"`
+	}
+	require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "1", res.Data["id"])
+	assert.Equal(t, "general", res.Data["title"])
+}
+
+func TestServer_DeleteChat_Encoded(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		decode func(t *testing.T, body []byte) string
+	}{
+		{
+			name:   "messagepack",
+			accept: "application/msgpack",
+			decode: func(t *testing.T, body []byte) string {
+				var res struct {
+					Data string `msgpack:"data"`
+				}
+				require.NoError(t, msgpack.Unmarshal(body, &res))
+				return res.Data
+			},
+		},
+		{
+			name:   "protobuf",
+			accept: "application/x-protobuf",
+			decode: func(t *testing.T, body []byte) string {
+				var res wrapperspb.StringValue
+				require.NoError(t, proto.Unmarshal(body, &res))
+				return res.GetValue()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().DeleteChat(gomock.Any(), "1").Return(nil).Times(1)
+
+			w := serveEncoded(t, c, mocks.NewMockMessage(ctrl), http.MethodDelete, "/v1/chats/1", "", tt.accept, nil)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.accept, w.Header().Get("Content-Type"))
+			// The body is a single value in the negotiated encoding.
+			assert.Equal(t, "deleted", tt.decode(t, w.Body.Bytes()))
+		})
+	}
+}
+
+func TestServer_CreateChatV2_Protobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().
+		CreateChat(gomock.Any(), &chatModel.Chat{Title: pointer.ToString("general")}).
+		Return(&chatModel.Chat{
+			ID:        pointer.ToString("7"),
+			Title:     pointer.ToString("general"),
+			CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)),
+		}, nil).Times(1)
+
+	body, err := proto.Marshal(&pb.CreateChatRequest{Title: "general"})
+	require.NoError(t, err)
+
+	w := serveEncoded(t, c, m, http.MethodPost, "/v2/chats", "application/x-protobuf", "application/x-protobuf", body)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+
+	var res pb.Chat
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "7", res.GetId())
+	assert.Equal(t, "general", res.GetTitle())
+	assert.Equal(t, time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), res.GetCreatedAt().AsTime())
+}
+
+func TestServer_CreateMessageV2_Protobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("7"),
+			Text:   pointer.ToString("hello"),
+		}).
+		Return(&messagesModel.Posted{Message: &messagesModel.Message{
+			ID:     pointer.ToString("3"),
+			ChatID: pointer.ToString("7"),
+			Text:   pointer.ToString("hello"),
+		}}, nil).Times(1)
+
+	body, err := proto.Marshal(&pb.CreateMessageRequest{Text: "hello"})
+	require.NoError(t, err)
+
+	w := serveEncoded(t, c, m, http.MethodPost, "/v2/chats/7/messages", "application/protobuf", "application/json;q=0.5, application/protobuf", body)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var res pb.Message
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "3", res.GetId())
+	assert.Equal(t, "7", res.GetChatId())
+	assert.Empty(t, res.GetAuthor())
+}
+
+func TestServer_GetChat_Protobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().
+		GetChatVersion(gomock.Any(), "7").
+		Return(&chatModel.Version{ChatID: pointer.ToString("7"), Version: pointer.ToInt64(1)}, nil).Times(1)
+	c.EXPECT().
+		GetChat(gomock.Any(), "7", int64(20)).
+		Return(&chatModel.Chat{
+			ID:        pointer.ToString("7"),
+			Title:     pointer.ToString("general"),
+			CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)),
+			Messages: []messagesModel.Message{{
+				ID:     pointer.ToString("3"),
+				ChatID: pointer.ToString("7"),
+				Text:   pointer.ToString("hello"),
+			}},
+		}, nil).Times(1)
+
+	w := serveEncoded(t, c, m, http.MethodGet, "/v1/chats/7", "", "application/protobuf", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+
+	var res pb.Chat
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "7", res.GetId())
+	assert.Equal(t, "general", res.GetTitle())
+	assert.Equal(t, time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), res.GetCreatedAt().AsTime())
+	require.Len(t, res.GetMessages(), 1)
+	assert.Equal(t, "hello", res.GetMessages()[0].GetText())
+}
+
+func TestServer_GetChatV2_ProtobufError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w := serveEncoded(t, mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), http.MethodGet, "/v2/chats/abc", "", "application/x-protobuf", nil)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var res pb.Error
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "err_invalid_request", res.GetCode())
+}
+
+func TestServer_Negotiation_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		accept      string
+		body        []byte
+		want        int
+	}{
+		{
+			name:   "v1 not acceptable",
+			method: http.MethodGet,
+			url:    "/v1/chats/1",
+			accept: "text/html",
+			want:   http.StatusNotAcceptable,
+		},
+		{
+			name:        "v1 unsupported media type",
+			method:      http.MethodPost,
+			url:         "/v1/chats/",
+			contentType: "text/xml",
+			body:        []byte("<chat/>"),
+			want:        http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "v1 protobuf body",
+			method:      http.MethodPost,
+			url:         "/v1/chats/",
+			contentType: "application/x-protobuf",
+			body:        []byte{0x0a, 0x07, 'g', 'e', 'n', 'e', 'r', 'a', 'l'},
+			want:        http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "v2 unsupported media type",
+			method:      http.MethodPost,
+			url:         "/v2/chats",
+			contentType: "text/xml",
+			body:        []byte("<chat/>"),
+			want:        http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			w := serveEncoded(t, mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), tt.method, tt.url, tt.contentType, tt.accept, tt.body)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		})
+	}
+}
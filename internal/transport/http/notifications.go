@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// UserHeader identifies the user making a request to the /v1/me routes. It is
+// expected to be set by the gateway authenticating users in front of the service.
+const UserHeader = "X-Username"
+
+// ErrUnauthenticated is returned by the /v1/me routes when the caller is not identified.
+const ErrUnauthenticated = errors.Error("unauthenticated: the " + UserHeader + " header is required")
+
+const (
+	defaultNotificationsLimit = 50
+	maxUsernameLength         = 100
+)
+
+// caller returns the username of the user making the request.
+func caller(r *http.Request) (string, error) {
+	username := strings.TrimSpace(r.Header.Get(UserHeader))
+	if username == "" || utf8.RuneCountInString(username) > maxUsernameLength {
+		return "", ErrUnauthenticated
+	}
+	return username, nil
+}
+
+func (s *Server) listNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	username, err := caller(r)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	limit, err := parseLimit(r, defaultNotificationsLimit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	unread := false
+	if v := r.URL.Query().Get("unread"); v != "" {
+		if unread, err = strconv.ParseBool(v); err != nil {
+			handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("invalid unread parameter")))
+			return
+		}
+	}
+
+	ns, err := s.notification.ListNotifications(ctx, username, limit, r.URL.Query().Get("before"), unread)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, ns)
+}
+
+func (s *Server) markNotificationRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	username, err := caller(r)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	n, err := s.notification.MarkRead(ctx, username, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, n)
+}
+
+func (s *Server) markAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	username, err := caller(r)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	marked, err := s.notification.MarkAllRead(ctx, username)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, struct {
+		Marked int64 `json:"marked"`
+	}{
+		Marked: marked,
+	})
+}
@@ -0,0 +1,198 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/notifications"
+	ntmodel "github.com/Polilo-User/test-task-hitalent/internal/notifications/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveNotifications(t *testing.T, m *mocks.MockMessage, n *mocks.MockNotification, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), m, mocks.NewMockDB(ctrl),
+		httptransport.WithNotifications(n),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_ListNotifications_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	n := mocks.NewMockNotification(ctrl)
+	n.EXPECT().ListNotifications(gomock.Any(), "alice", int64(10), "7", true).Return([]ntmodel.Notification{
+		{
+			ID:        pointer.ToString("6"),
+			Username:  pointer.ToString("alice"),
+			Type:      pointer.ToString(ntmodel.TypeMention),
+			ChatID:    pointer.ToString("1"),
+			MessageID: pointer.ToString("2"),
+			Author:    pointer.ToString("bob"),
+			Excerpt:   pointer.ToString("hi @alice"),
+		},
+	}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/me/notifications?limit=10&before=7&unread=true", nil)
+	require.NoError(t, err)
+	req.Header.Set(httptransport.UserHeader, "alice")
+
+	w := serveNotifications(t, mocks.NewMockMessage(ctrl), n, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":"6","type":"mention","chat_id":"1","message_id":"2","author":"bob",
+		"excerpt":"hi @alice","read_at":null,"created_at":null,"updated_at":null}]}`, w.Body.String())
+}
+
+func TestServer_ListNotifications_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		username string
+		setup    func(n *mocks.MockNotification)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "no username",
+			url:      "/v1/me/notifications",
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"error":"unauthenticated: the X-Username header is required"}`,
+		},
+		{
+			name:     "invalid unread",
+			url:      "/v1/me/notifications?unread=maybe",
+			username: "alice",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "fails",
+			url:      "/v1/me/notifications",
+			username: "alice",
+			setup: func(n *mocks.MockNotification) {
+				n.EXPECT().ListNotifications(gomock.Any(), "alice", int64(50), "", false).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			n := mocks.NewMockNotification(ctrl)
+			if tt.setup != nil {
+				tt.setup(n)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			if tt.username != "" {
+				req.Header.Set(httptransport.UserHeader, tt.username)
+			}
+
+			w := serveNotifications(t, mocks.NewMockMessage(ctrl), n, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_MarkNotificationRead_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	n := mocks.NewMockNotification(ctrl)
+	n.EXPECT().MarkRead(gomock.Any(), "alice", "6").Return(nil, notifications.ErrNotificationNotFound).Times(1)
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/me/notifications/6/read", nil)
+	require.NoError(t, err)
+	req.Header.Set(httptransport.UserHeader, "alice")
+
+	w := serveNotifications(t, mocks.NewMockMessage(ctrl), n, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"notification_not_found: notification not found"}`, w.Body.String())
+}
+
+func TestServer_MarkAllNotificationsRead_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	n := mocks.NewMockNotification(ctrl)
+	n.EXPECT().MarkAllRead(gomock.Any(), "alice").Return(int64(4), nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/me/notifications:read-all", nil)
+	require.NoError(t, err)
+	req.Header.Set(httptransport.UserHeader, "alice")
+
+	w := serveNotifications(t, mocks.NewMockMessage(ctrl), n, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"marked":4}}`, w.Body.String())
+}
+
+func TestServer_UpdateMessage_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMessage(ctrl)
+	m.EXPECT().UpdateMessage(gomock.Any(), "1", "2", "hi @carol").Return(&msmodel.Message{
+		ID:       pointer.ToString("2"),
+		ChatID:   pointer.ToString("1"),
+		Text:     pointer.ToString("hi @carol"),
+		Mentions: msmodel.Mentions{{Username: "carol", Offset: 3, Length: 6}},
+	}, nil).Times(1)
+
+	req, err := http.NewRequest(http.MethodPatch, "/v1/chats/1/messages/2", strings.NewReader(`{"text":"hi @carol"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := serveNotifications(t, m, mocks.NewMockNotification(ctrl), req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"id":"2","chat_id":"1","text":"hi @carol","author":null,"system":null,"created_at":null,
+		"mentions":[{"username":"carol","offset":3,"length":6}]}}`, w.Body.String())
+}
+
+func TestServer_DeleteMessage_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockMessage(ctrl)
+	m.EXPECT().DeleteMessage(gomock.Any(), "1", "2").Return(messages.ErrMessageNotFound).Times(1)
+
+	req, err := http.NewRequest(http.MethodDelete, "/v1/chats/1/messages/2", nil)
+	require.NoError(t, err)
+
+	w := serveNotifications(t, m, mocks.NewMockNotification(ctrl), req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"message_not_found: message not found"}`, w.Body.String())
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+	"github.com/swaggest/swgui/v5emb"
+	"go.uber.org/zap"
+)
+
+// ErrOpenAPI is the error returned when the embedded OpenAPI document is invalid.
+const ErrOpenAPI = errors.Error("invalid openapi document")
+
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+// addOpenAPIRoutes serves the OpenAPI document and Swagger UI. It returns the
+// middleware validating requests against the document, which runs after content
+// negotiation so that validation errors are encoded as the client asked.
+func addOpenAPIRoutes(r *mux.Router) (mux.MiddlewareFunc, error) {
+	ctx := context.Background()
+
+	doc, err := openapi3.NewLoader().LoadFromData(openAPIDocument)
+	if err != nil {
+		return nil, ErrOpenAPI.Wrap(err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, ErrOpenAPI.Wrap(err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, ErrOpenAPI.Wrap(err)
+	}
+
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, ErrOpenAPI.Wrap(err)
+	}
+
+	r.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(spec); err != nil {
+			logging.From(r.Context()).Error("failed to write openapi document", zap.Error(err))
+		}
+	}).Methods(http.MethodGet)
+
+	r.Handle("/docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently))
+	r.PathPrefix("/docs/").Handler(v5emb.New(doc.Info.Title, "/openapi.json", "/docs/"))
+
+	return validateRequest(router), nil
+}
+
+// validateRequest rejects requests that do not match the OpenAPI document with 400.
+// Requests to routes the document does not describe are passed through untouched.
+func validateRequest(router routers.Router) mux.MiddlewareFunc {
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			route, params, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Handlers decode bodies without a Content-Type as JSON, so validate
+			// them that way too.
+			if r.Header.Get("Content-Type") == "" && r.ContentLength != 0 {
+				r.Header.Set("Content-Type", "application/json")
+			}
+
+			// The document only describes JSON bodies; other encodings are checked
+			// by the codecs and the handlers' own validation.
+			opts := options
+			if !isJSON(r.Header.Get("Content-Type")) {
+				o := *options
+				o.ExcludeRequestBody = true
+				opts = &o
+			}
+
+			err = openapi3filter.ValidateRequest(ctx, &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: params,
+				Route:      route,
+				Options:    opts,
+			})
+			if err != nil {
+				err = errors.ErrInvalidRequest.Wrap(err)
+				if strings.HasPrefix(r.URL.Path, "/v2/") {
+					handleErrorV2(ctx, w, err)
+				} else {
+					handleError(ctx, w, err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
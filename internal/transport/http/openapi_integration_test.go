@@ -0,0 +1,162 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_OpenAPI_CoversEveryRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ht := httptransport.New(
+		mocks.NewMockChat(ctrl),
+		mocks.NewMockMessage(ctrl),
+		mocks.NewMockDB(ctrl),
+		httptransport.WithWebhooks(mocks.NewMockWebhook(ctrl)),
+		httptransport.WithHooks(mocks.NewMockHook(ctrl)),
+		httptransport.WithGraphQL(http.NotFoundHandler()),
+		httptransport.WithImports(mocks.NewMockImport(ctrl), 1<<20),
+		httptransport.WithAttachments(mocks.NewMockAttachment(ctrl), 1<<20),
+		httptransport.WithNotifications(mocks.NewMockNotification(ctrl)),
+		httptransport.WithReads(mocks.NewMockRead(ctrl)),
+		httptransport.WithSync(mocks.NewMockSync(ctrl)),
+		httptransport.WithScheduling(mocks.NewMockScheduled(ctrl)),
+		httptransport.WithRetention(mocks.NewMockRetention(ctrl)),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var doc struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+
+	err = r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || strings.HasPrefix(path, "/docs") {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		for _, method := range methods {
+			_, ok := doc.Paths[path][strings.ToLower(method)]
+			assert.True(t, ok, "%s %s is not documented", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestServer_OpenAPI_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+	}{
+		{
+			name:   "missing title",
+			method: http.MethodPost,
+			url:    baseChatURL,
+			body:   `{}`,
+		},
+		{
+			name:   "title too long",
+			method: http.MethodPost,
+			url:    baseChatURL,
+			body:   `{"title":"` + strings.Repeat("a", 201) + `"}`,
+		},
+		{
+			name:   "malformed body",
+			method: http.MethodPost,
+			url:    "/v1/chats/1/messages/",
+			body:   `{"text":`,
+		},
+		{
+			name:   "limit not a number",
+			method: http.MethodGet,
+			url:    "/v1/chats/1?limit=many",
+		},
+		{
+			name:   "negative limit",
+			method: http.MethodGet,
+			url:    "/v1/chats/1?limit=-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// No expectations are set, so any call that reaches a handler fails the test.
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			d := mocks.NewMockDB(ctrl)
+
+			ht := httptransport.New(c, m, d)
+
+			r := mux.NewRouter()
+			require.NoError(t, ht.AddRoutes(r))
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var res struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.NotEmpty(t, res.Error)
+		})
+	}
+}
+
+func TestServer_OpenAPI_SwaggerUI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/docs/", nil)
+	require.NoError(t, err)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+}
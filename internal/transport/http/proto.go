@@ -0,0 +1,130 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	chmodel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/grpc/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The /v2 DTOs share their protobuf messages with the gRPC API. Protobuf bodies
+// carry the message itself, without the data and error envelopes used by JSON.
+
+var errUnexpectedProto = errors.Error("unexpected protobuf message")
+
+func (*CreateChatRequest) NewProto() proto.Message {
+	return &pb.CreateChatRequest{}
+}
+
+func (r *CreateChatRequest) FromProto(m proto.Message) error {
+	msg, ok := m.(*pb.CreateChatRequest)
+	if !ok {
+		return errUnexpectedProto
+	}
+
+	r.Title = msg.GetTitle()
+
+	return nil
+}
+
+func (*CreateMessageRequest) NewProto() proto.Message {
+	return &pb.CreateMessageRequest{}
+}
+
+// FromProto copies a pb.CreateMessageRequest. The chat id comes from the path,
+// and an empty author is treated as absent since proto3 strings cannot be null.
+func (r *CreateMessageRequest) FromProto(m proto.Message) error {
+	msg, ok := m.(*pb.CreateMessageRequest)
+	if !ok {
+		return errUnexpectedProto
+	}
+
+	r.Text = msg.GetText()
+	r.Author = nil
+	if msg.GetAuthor() != "" {
+		author := msg.GetAuthor()
+		r.Author = &author
+	}
+
+	return nil
+}
+
+func (r DataResponse) ToProto() proto.Message {
+	if r.Data == nil {
+		return &emptypb.Empty{}
+	}
+	return toProto(r.Data)
+}
+
+// toProto converts a response to its protobuf message. Besides the /v2 DTOs, the
+// chats and messages returned by /v1 are converted, so that both versions share
+// their protobuf bodies, and the statuses answered to deletions are sent as
+// strings. It returns nil for values with no protobuf message.
+func toProto(v interface{}) proto.Message {
+	switch d := v.(type) {
+	case proto.Message:
+		return d
+	case string:
+		return wrapperspb.String(d)
+	case interface{ ToProto() proto.Message }:
+		return d.ToProto()
+	case *chmodel.Chat:
+		return toChatResponse(d).ToProto()
+	case *msmodel.Message:
+		return toMessageResponse(d).ToProto()
+	default:
+		return nil
+	}
+}
+
+func (r ErrorResponse) ToProto() proto.Message {
+	return &pb.Error{
+		Code:    r.Error.Code,
+		Message: r.Error.Message,
+	}
+}
+
+func (r ChatResponse) ToProto() proto.Message {
+	res := &pb.Chat{
+		Id:        strconv.FormatInt(r.ID, 10),
+		Title:     r.Title,
+		CreatedAt: protoTimestamp(r.CreatedAt),
+	}
+
+	for _, m := range r.Messages {
+		res.Messages = append(res.Messages, m.ToProto().(*pb.Message))
+	}
+
+	return res
+}
+
+func (r MessageResponse) ToProto() proto.Message {
+	res := &pb.Message{
+		Id:        strconv.FormatInt(r.ID, 10),
+		ChatId:    strconv.FormatInt(r.ChatID, 10),
+		Text:      r.Text,
+		System:    r.System,
+		CreatedAt: protoTimestamp(r.CreatedAt),
+	}
+	if r.Author != nil {
+		res.Author = *r.Author
+	}
+
+	return res
+}
+
+func protoTimestamp(s string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	rdmodel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gorilla/mux"
+)
+
+// MarkChatReadRequest is the body accepted by POST /v1/chats/{id}/read.
+type MarkChatReadRequest struct {
+	LastReadMessageID string `json:"last_read_message_id"`
+}
+
+func (s *Server) markChatRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	username, err := caller(r)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	var req MarkChatReadRequest
+	if err := decodeBody(r, &req); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	if req.LastReadMessageID == "" {
+		handleError(ctx, w, errors.ErrValidation.Wrap(errors.Error("last_read_message_id is required")))
+		return
+	}
+
+	c, err := s.read.MarkRead(ctx, username, mux.Vars(r)["id"], req.LastReadMessageID)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, c)
+}
+
+// unread returns how much of a chat the caller has not read, or nil when reads
+// are not tracked or the caller is not identified.
+func (s *Server) unread(r *http.Request, chatID string) (*rdmodel.Unread, error) {
+	if s.read == nil {
+		return nil, nil
+	}
+
+	username, err := caller(r)
+	if err != nil {
+		return nil, nil
+	}
+
+	us, err := s.read.Unread(r.Context(), username, []string{chatID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range us {
+		if pointer.GetString(us[i].ChatID) == chatID {
+			return &us[i], nil
+		}
+	}
+
+	return nil, nil
+}
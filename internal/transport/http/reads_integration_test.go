@@ -0,0 +1,173 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/reads"
+	readModel "github.com/Polilo-User/test-task-hitalent/internal/reads/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveReads(t *testing.T, c *mocks.MockChat, rd *mocks.MockRead, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	if c == nil {
+		c = mocks.NewMockChat(ctrl)
+	}
+	ht := httptransport.New(c, mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl),
+		httptransport.WithReads(rd),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func markReadRequest(t *testing.T, username, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/chats/1/read", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if username != "" {
+		req.Header.Set(httptransport.UserHeader, username)
+	}
+
+	return req
+}
+
+func TestServer_MarkChatRead_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rd := mocks.NewMockRead(ctrl)
+	rd.EXPECT().MarkRead(gomock.Any(), "alice", "1", "7").Return(&readModel.Cursor{
+		ChatID:            pointer.ToString("1"),
+		LastReadMessageID: pointer.ToString("7"),
+		LastReadSeq:       pointer.ToInt64(4),
+		UpdatedAt:         pointer.ToTime(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}, nil).Times(1)
+
+	w := serveReads(t, nil, rd, markReadRequest(t, "alice", `{"last_read_message_id":"7"}`))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"chat_id":"1","last_read_message_id":"7","last_read_seq":4,"updated_at":"2020-01-02T00:00:00Z"}}`, w.Body.String())
+}
+
+func TestServer_MarkChatRead_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		body     string
+		setup    func(rd *mocks.MockRead)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "no username",
+			body:     `{"last_read_message_id":"7"}`,
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"error":"unauthenticated: the X-Username header is required"}`,
+		},
+		{
+			name:     "no message",
+			username: "alice",
+			body:     `{}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "message not in chat",
+			username: "alice",
+			body:     `{"last_read_message_id":"7"}`,
+			setup: func(rd *mocks.MockRead) {
+				rd.EXPECT().MarkRead(gomock.Any(), "alice", "1", "7").Return(nil, reads.ErrMessageNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"message_not_found: message not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rd := mocks.NewMockRead(ctrl)
+			if tt.setup != nil {
+				tt.setup(rd)
+			}
+
+			w := serveReads(t, nil, rd, markReadRequest(t, tt.username, tt.body))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_GetChat_Unread(t *testing.T) {
+	unread := func(lastRead string, count int64, firstUnread string) []readModel.Unread {
+		return []readModel.Unread{{
+			ChatID:            pointer.ToString("1"),
+			UnreadCount:       pointer.ToInt64(count),
+			FirstUnreadID:     pointer.ToString(firstUnread),
+			LastReadMessageID: pointer.ToString(lastRead),
+			ReadAt:            pointer.ToTime(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+		}}
+	}
+
+	get := func(t *testing.T, us []readModel.Unread, username string) *httptest.ResponseRecorder {
+		ctrl := gomock.NewController(t)
+
+		c := mocks.NewMockChat(ctrl)
+		c.EXPECT().GetChatVersion(gomock.Any(), "1").Return(chatVersion(1, "5"), nil).Times(1)
+		c.EXPECT().GetChat(gomock.Any(), "1", int64(20), gomock.Any()).
+			Return(&chatModel.Chat{ID: pointer.ToString("1")}, nil).Times(1)
+
+		rd := mocks.NewMockRead(ctrl)
+		if username != "" {
+			rd.EXPECT().Unread(gomock.Any(), username, []string{"1"}).Return(us, nil).Times(1)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/chats/1?fields=id,unread_count,first_unread_id", nil)
+		require.NoError(t, err)
+		if username != "" {
+			req.Header.Set(httptransport.UserHeader, username)
+		}
+
+		return serveReads(t, c, rd, req)
+	}
+
+	w := get(t, unread("3", 2, "4"), "alice")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"id":"1","unread_count":2,"first_unread_id":"4"}}`, w.Body.String())
+	assert.Equal(t, "Fri, 03 Jan 2020 00:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, []string{"Accept", "X-Username"}, w.Header().Values("Vary"))
+
+	// Reading on moves the cursor, which makes the cached copy stale.
+	assert.NotEqual(t, w.Header().Get("ETag"), get(t, unread("5", 0, ""), "alice").Header().Get("ETag"))
+
+	anonymous := get(t, nil, "")
+	require.Equal(t, http.StatusOK, anonymous.Code)
+	assert.JSONEq(t, `{"data":{"id":"1"}}`, anonymous.Body.String())
+}
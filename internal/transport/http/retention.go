@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultPurgesLimit = 50
+
+// RetentionRequest is the body accepted by PUT /v1/chats/{id}/retention. A null
+// retention_days makes the chat follow the global retention policy.
+type RetentionRequest struct {
+	RetentionDays *int64 `json:"retention_days"`
+}
+
+func (s *Server) setRetention(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req RetentionRequest
+	if err := decodeBody(r, &req); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	c, err := s.chat.SetRetention(ctx, mux.Vars(r)["id"], req.RetentionDays)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, c)
+}
+
+func (s *Server) placeLegalHold(w http.ResponseWriter, r *http.Request) {
+	s.setLegalHold(w, r, true)
+}
+
+func (s *Server) liftLegalHold(w http.ResponseWriter, r *http.Request) {
+	s.setLegalHold(w, r, false)
+}
+
+func (s *Server) setLegalHold(w http.ResponseWriter, r *http.Request, hold bool) {
+	ctx := r.Context()
+
+	c, err := s.chat.SetLegalHold(ctx, mux.Vars(r)["id"], hold)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, c)
+}
+
+// listPurges returns the reports of what the retention job purged, newest first.
+func (s *Server) listPurges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseLimit(r, defaultPurgesLimit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	q := r.URL.Query()
+
+	ps, err := s.retention.ListPurges(ctx, q.Get("chat_id"), limit, q.Get("before"))
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, ps)
+}
@@ -0,0 +1,267 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/messages"
+	retentionModel "github.com/Polilo-User/test-task-hitalent/internal/retention/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveRetention(t *testing.T, c *mocks.MockChat, m *mocks.MockMessage, rt *mocks.MockRetention, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	if c == nil {
+		c = mocks.NewMockChat(ctrl)
+	}
+	if m == nil {
+		m = mocks.NewMockMessage(ctrl)
+	}
+	if rt == nil {
+		rt = mocks.NewMockRetention(ctrl)
+	}
+
+	ht := httptransport.New(c, m, mocks.NewMockDB(ctrl), httptransport.WithRetention(rt))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(method, target, bytes.NewBufferString(body))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_SetRetention(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantDays *int64
+		chat     *chatModel.Chat
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "set",
+			body:     `{"retention_days":365}`,
+			wantDays: pointer.ToInt64(365),
+			chat:     &chatModel.Chat{ID: pointer.ToString("1"), RetentionDays: pointer.ToInt64(365)},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "cleared",
+			body:     `{"retention_days":null}`,
+			chat:     &chatModel.Chat{ID: pointer.ToString("1")},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "chat not found",
+			body:     `{"retention_days":30}`,
+			wantDays: pointer.ToInt64(30),
+			err:      chats.ErrChatNotFound,
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().SetRetention(gomock.Any(), "1", tt.wantDays).Return(tt.chat, tt.err).Times(1)
+
+			w := serveRetention(t, c, nil, nil, http.MethodPut, fmt.Sprintf(chatURL, "1")+"/retention", tt.body)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.chat == nil {
+				return
+			}
+
+			var res struct {
+				Data chatModel.Chat `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, *tt.chat, res.Data)
+		})
+	}
+}
+
+func TestServer_SetRetention_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "missing",
+			body: `{}`,
+		},
+		{
+			name: "too long",
+			body: `{"retention_days":36501}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveRetention(t, nil, nil, nil, http.MethodPut, fmt.Sprintf(chatURL, "1")+"/retention", tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestServer_LegalHold(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		wantHold bool
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "placed",
+			method:   http.MethodPut,
+			wantHold: true,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "lifted",
+			method:   http.MethodDelete,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "chat not found",
+			method:   http.MethodPut,
+			wantHold: true,
+			err:      chats.ErrChatNotFound,
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var chat *chatModel.Chat
+			if tt.err == nil {
+				chat = &chatModel.Chat{ID: pointer.ToString("1"), LegalHold: pointer.ToBool(tt.wantHold)}
+			}
+
+			c := mocks.NewMockChat(ctrl)
+			c.EXPECT().SetLegalHold(gomock.Any(), "1", tt.wantHold).Return(chat, tt.err).Times(1)
+
+			w := serveRetention(t, c, nil, nil, tt.method, fmt.Sprintf(chatURL, "1")+"/legal-hold", "")
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestServer_LegalHold_BlocksDeletes(t *testing.T) {
+	t.Run("chat", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c := mocks.NewMockChat(ctrl)
+		c.EXPECT().DeleteChat(gomock.Any(), "1").Return(chats.ErrLegalHold).Times(1)
+
+		w := serveRetention(t, c, nil, nil, http.MethodDelete, fmt.Sprintf(chatURL, "1"), "")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error":"legal_hold: chat is under legal hold"}`, w.Body.String())
+	})
+
+	t.Run("message", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mocks.NewMockMessage(ctrl)
+		m.EXPECT().DeleteMessage(gomock.Any(), "1", "2").Return(chats.ErrLegalHold).Times(1)
+
+		w := serveRetention(t, nil, m, nil, http.MethodDelete, fmt.Sprintf(messageURL, "1")+"2", "")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("message not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		m := mocks.NewMockMessage(ctrl)
+		m.EXPECT().DeleteMessage(gomock.Any(), "1", "2").Return(messages.ErrMessageNotFound).Times(1)
+
+		w := serveRetention(t, nil, m, nil, http.MethodDelete, fmt.Sprintf(messageURL, "1")+"2", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServer_ListPurges(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantChatID string
+		wantLimit  int64
+		wantBefore string
+	}{
+		{
+			name:      "defaults",
+			target:    "/v1/retention/purges",
+			wantLimit: 50,
+		},
+		{
+			name:       "chat page",
+			target:     "/v1/retention/purges?chat_id=1&limit=10&before=9",
+			wantChatID: "1",
+			wantLimit:  10,
+			wantBefore: "9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			want := []retentionModel.Purge{{
+				ID:       pointer.ToString("8"),
+				ChatID:   pointer.ToString("1"),
+				Messages: pointer.ToInt64(3),
+				OldestAt: &at,
+				NewestAt: &at,
+				PurgedAt: &at,
+			}}
+
+			rt := mocks.NewMockRetention(ctrl)
+			rt.EXPECT().ListPurges(gomock.Any(), tt.wantChatID, tt.wantLimit, tt.wantBefore).Return(want, nil).Times(1)
+
+			w := serveRetention(t, nil, nil, rt, http.MethodGet, tt.target, "")
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var res struct {
+				Data []retentionModel.Purge `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, want, res.Data)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	msmodel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	scmodel "github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+
+	"github.com/gorilla/mux"
+)
+
+// ErrSchedulingDisabled is returned when a message is sent with send_at but
+// scheduling is not enabled.
+const ErrSchedulingDisabled = errors.Error("scheduling_disabled: messages cannot be scheduled")
+
+// scheduleMessage answers a message created with a send time, which is stored
+// to be posted at that time.
+func (s *Server) scheduleMessage(w http.ResponseWriter, r *http.Request, m *msmodel.Message, sendAt *time.Time) {
+	ctx := r.Context()
+
+	if s.scheduled == nil {
+		handleError(ctx, w, ErrSchedulingDisabled)
+		return
+	}
+
+	sm, err := s.scheduled.Schedule(ctx, &scmodel.ScheduledMessage{
+		ChatID:    m.ChatID,
+		Text:      m.Text,
+		Author:    m.Author,
+		SendAt:    sendAt,
+		ExpiresAt: m.ExpiresAt,
+	})
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	writeBody(ctx, w, http.StatusAccepted, struct {
+		Data *scmodel.ScheduledMessage `json:"data"`
+	}{
+		Data: sm,
+	})
+}
+
+func (s *Server) listScheduled(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ms, err := s.scheduled.ListScheduled(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, ms)
+}
+
+// UpdateScheduledRequest is the body accepted by PATCH
+// /v1/chats/{id}/scheduled/{scheduled_id}. Fields left out are unchanged.
+type UpdateScheduledRequest struct {
+	Text   *string    `json:"text"`
+	SendAt *time.Time `json:"send_at"`
+}
+
+func (s *Server) updateScheduled(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	var req UpdateScheduledRequest
+	if err := decodeBody(r, &req); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	m, err := s.scheduled.UpdateScheduled(ctx, vars["id"], vars["scheduled_id"], req.Text, req.SendAt)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, m)
+}
+
+func (s *Server) cancelScheduled(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if err := s.scheduled.CancelScheduled(ctx, vars["id"], vars["scheduled_id"]); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, "deleted")
+}
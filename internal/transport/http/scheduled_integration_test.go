@@ -0,0 +1,244 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/scheduled"
+	scheduledModel "github.com/Polilo-User/test-task-hitalent/internal/scheduled/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveScheduled(t *testing.T, sc *mocks.MockScheduled, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	var opts []httptransport.Option
+	if sc != nil {
+		opts = append(opts, httptransport.WithScheduling(sc))
+	}
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl), opts...)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	require.NoError(t, err)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func scheduledMessage() *scheduledModel.ScheduledMessage {
+	return &scheduledModel.ScheduledMessage{
+		ID:        pointer.ToString("4"),
+		ChatID:    pointer.ToString("1"),
+		Text:      pointer.ToString("later"),
+		Author:    pointer.ToString("alice"),
+		SendAt:    pointer.ToTime(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)),
+		CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		UpdatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+}
+
+const scheduledJSON = `{"id":"4","chat_id":"1","text":"later","author":"alice","send_at":"2030-01-02T09:00:00Z","expires_at":null,"created_at":"2020-01-01T00:00:00Z","updated_at":"2020-01-01T00:00:00Z"}`
+
+func TestServer_CreateMessage_Scheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sc := mocks.NewMockScheduled(ctrl)
+	sc.EXPECT().Schedule(gomock.Any(), &scheduledModel.ScheduledMessage{
+		ChatID: pointer.ToString("1"),
+		Text:   pointer.ToString("later"),
+		Author: pointer.ToString("alice"),
+		SendAt: pointer.ToTime(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)),
+	}).Return(scheduledMessage(), nil).Times(1)
+
+	w := serveScheduled(t, sc, http.MethodPost, fmt.Sprintf(messageURL, "1"),
+		`{"text":"later","author":"alice","send_at":"2030-01-02T09:00:00Z"}`)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"data":`+scheduledJSON+`}`, w.Body.String())
+}
+
+func TestServer_CreateMessage_ScheduledExpiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	sm := scheduledMessage()
+	sm.ExpiresAt = pointer.ToTime(expiresAt)
+
+	sc := mocks.NewMockScheduled(ctrl)
+	sc.EXPECT().Schedule(gomock.Any(), &scheduledModel.ScheduledMessage{
+		ChatID:    pointer.ToString("1"),
+		Text:      pointer.ToString("later"),
+		Author:    pointer.ToString("alice"),
+		SendAt:    pointer.ToTime(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)),
+		ExpiresAt: pointer.ToTime(expiresAt),
+	}).Return(sm, nil).Times(1)
+
+	w := serveScheduled(t, sc, http.MethodPost, fmt.Sprintf(messageURL, "1"),
+		`{"text":"later","author":"alice","send_at":"2030-01-02T09:00:00Z","expires_at":"2030-01-02T10:00:00Z"}`)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"expires_at":"2030-01-02T10:00:00Z"`)
+}
+
+func TestServer_CreateMessage_Scheduled_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		scheduleErr error
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "send time in the past",
+			enabled:     true,
+			scheduleErr: scheduled.ErrInvalidSendAt,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"error":"invalid_send_at: send_at must be in the future"}`,
+		},
+		{
+			name:        "expiry before send time",
+			enabled:     true,
+			scheduleErr: scheduled.ErrInvalidExpiresAt,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"error":"invalid_expires_at: expires_at must be after send_at"}`,
+		},
+		{
+			name:       "scheduling disabled",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"scheduling_disabled: messages cannot be scheduled"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var sc *mocks.MockScheduled
+			if tt.enabled {
+				sc = mocks.NewMockScheduled(ctrl)
+				sc.EXPECT().Schedule(gomock.Any(), gomock.Any()).Return(nil, tt.scheduleErr).Times(1)
+			}
+
+			w := serveScheduled(t, sc, http.MethodPost, fmt.Sprintf(messageURL, "1"),
+				`{"text":"later","send_at":"2020-01-02T09:00:00Z"}`)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_ListScheduled_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sc := mocks.NewMockScheduled(ctrl)
+	sc.EXPECT().ListScheduled(gomock.Any(), "1").Return([]scheduledModel.ScheduledMessage{*scheduledMessage()}, nil).Times(1)
+
+	w := serveScheduled(t, sc, http.MethodGet, "/v1/chats/1/scheduled/", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[`+scheduledJSON+`]}`, w.Body.String())
+}
+
+func TestServer_UpdateScheduled(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		updateErr  error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			body:       `{"send_at":"2030-01-02T09:00:00Z"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":` + scheduledJSON + `}`,
+		},
+		{
+			name:       "already posted",
+			body:       `{"send_at":"2030-01-02T09:00:00Z"}`,
+			updateErr:  scheduled.ErrScheduledNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"scheduled_message_not_found: scheduled message not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var want *scheduledModel.ScheduledMessage
+			if tt.updateErr == nil {
+				want = scheduledMessage()
+			}
+
+			sc := mocks.NewMockScheduled(ctrl)
+			sc.EXPECT().UpdateScheduled(gomock.Any(), "1", "4", (*string)(nil), pointer.ToTime(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC))).
+				Return(want, tt.updateErr).Times(1)
+
+			w := serveScheduled(t, sc, http.MethodPatch, "/v1/chats/1/scheduled/4", tt.body)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_CancelScheduled(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancelErr  error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":"deleted"}`,
+		},
+		{
+			name:       "already posted",
+			cancelErr:  scheduled.ErrScheduledNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"scheduled_message_not_found: scheduled message not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sc := mocks.NewMockScheduled(ctrl)
+			sc.EXPECT().CancelScheduled(gomock.Any(), "1", "4").Return(tt.cancelErr).Times(1)
+
+			w := serveScheduled(t, sc, http.MethodDelete, "/v1/chats/1/scheduled/4", "")
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
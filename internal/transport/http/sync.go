@@ -0,0 +1,31 @@
+package http
+
+import "net/http"
+
+const (
+	defaultSyncLimit = 100
+	maxSyncLimit     = 1000
+)
+
+// syncChanges returns the changes to chats and messages since the sync token of
+// the caller, or the token to start from when it has none yet.
+func (s *Server) syncChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseLimit(r, defaultSyncLimit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+	if limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+
+	page, err := s.sync.Sync(ctx, r.URL.Query().Get("since"), limit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, page)
+}
@@ -0,0 +1,139 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/changes"
+	changeModel "github.com/Polilo-User/test-task-hitalent/internal/changes/model"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveSync(t *testing.T, sy *mocks.MockSync, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(mocks.NewMockChat(ctrl), mocks.NewMockMessage(ctrl), mocks.NewMockDB(ctrl),
+		httptransport.WithSync(sy),
+	)
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_Sync_Success(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		since     string
+		wantLimit int64
+	}{
+		{
+			name:      "default limit",
+			target:    "/v1/sync?since=MTIuNQ",
+			since:     "MTIuNQ",
+			wantLimit: 100,
+		},
+		{
+			name:      "capped limit",
+			target:    "/v1/sync?since=MTIuNQ&limit=5000",
+			since:     "MTIuNQ",
+			wantLimit: 1000,
+		},
+		{
+			name:      "no token",
+			target:    "/v1/sync?limit=10",
+			wantLimit: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sy := mocks.NewMockSync(ctrl)
+			sy.EXPECT().Sync(gomock.Any(), tt.since, tt.wantLimit).Return(&changeModel.Page{
+				Changes: []events.Event{{
+					ID:         "6",
+					Type:       events.MessageDeleted,
+					ChatID:     "1",
+					Data:       json.RawMessage(`{"id":"3"}`),
+					OccurredAt: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				}},
+				Next:    "MTIuNg",
+				HasMore: true,
+			}, nil).Times(1)
+
+			w := serveSync(t, sy, tt.target)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"data":{"changes":[{"id":"6","type":"message.deleted","chat_id":"1","data":{"id":"3"},"occurred_at":"2020-01-02T00:00:00Z"}],"next":"MTIuNg","has_more":true}}`, w.Body.String())
+		})
+	}
+}
+
+func TestServer_Sync_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		syncErr    error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "invalid token",
+			target:     "/v1/sync?since=x",
+			syncErr:    changes.ErrInvalidToken,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid_sync_token: sync token is malformed"}`,
+		},
+		{
+			name:       "token expired",
+			target:     "/v1/sync?since=MTIuNQ",
+			syncErr:    changes.ErrTokenExpired,
+			wantStatus: http.StatusGone,
+			wantBody:   `{"error":"sync_token_expired: the changes since this sync token were trimmed, a full resync is required"}`,
+		},
+		{
+			name:       "invalid limit",
+			target:     "/v1/sync?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sy := mocks.NewMockSync(ctrl)
+			if tt.syncErr != nil {
+				sy.EXPECT().Sync(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.syncErr).Times(1)
+			}
+
+			w := serveSync(t, sy, tt.target)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
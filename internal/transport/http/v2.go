@@ -0,0 +1,212 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/codec"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMessageLimit = 20
+	maxMessageLimit     = 100
+)
+
+func (s *Server) addV2Routes(r *mux.Router, validate mux.MiddlewareFunc) {
+	r = r.PathPrefix("/v2").Subrouter()
+	r.Use(negotiate(newRegistryV2(), handleErrorV2), s.idempotent(handleErrorV2, maxBatchBodySize), validate)
+
+	r.HandleFunc("/chats", s.createChatV2).Methods(http.MethodPost)
+	r.HandleFunc("/chats/{id}", s.getChatV2).Methods(http.MethodGet)
+	r.HandleFunc("/chats/{id}", s.deleteChatV2).Methods(http.MethodDelete)
+	r.HandleFunc("/chats/{id}/messages", s.createMessageV2).Methods(http.MethodPost)
+}
+
+func (s *Server) createChatV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateChatRequest
+	if err := s.decodeV2(r, &req); err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	created, err := s.chat.CreateChat(ctx, req.toModel())
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	handleResponseV2(ctx, w, http.StatusCreated, toChatResponse(created))
+}
+
+func (s *Server) getChatV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := pathID(r)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	limit, err := parseLimit(r, defaultMessageLimit)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+	if limit > maxMessageLimit {
+		limit = maxMessageLimit
+	}
+
+	sel, err := parseSelection(r, chatFieldsV2)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	done, err := s.checkNotModified(w, r, strconv.FormatInt(id, 10), nil)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+	if done {
+		return
+	}
+
+	chat, err := s.chat.GetChat(ctx, strconv.FormatInt(id, 10), limit, sel.options()...)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	handleResponseV2(ctx, w, http.StatusOK, sel.apply(toChatResponse(chat)))
+}
+
+func (s *Server) deleteChatV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := pathID(r)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	if err := s.chat.DeleteChat(ctx, strconv.FormatInt(id, 10)); err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createMessageV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := pathID(r)
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	var req CreateMessageRequest
+	if err := s.decodeV2(r, &req); err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	posted, err := s.message.CreateMessage(ctx, req.toModel(id))
+	if err != nil {
+		handleErrorV2(ctx, w, err)
+		return
+	}
+
+	// A slash command that did not reply leaves nothing to return.
+	created := posted.First()
+	if created == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	handleResponseV2(ctx, w, http.StatusCreated, toMessageResponse(created))
+}
+
+// decodeV2 decodes a request body into a DTO and validates it. The /v2 codecs
+// reject fields the DTO does not declare.
+func (s *Server) decodeV2(r *http.Request, v interface{}) error {
+	if err := decodeBody(r, v); err != nil {
+		return errors.ErrInvalidRequest.Wrap(err)
+	}
+
+	if err := s.validate.Struct(v); err != nil {
+		return errors.ErrValidation.Wrap(err)
+	}
+
+	return nil
+}
+
+func pathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id < 1 {
+		return 0, errors.ErrInvalidRequest.Wrap(errors.Error("id must be a positive integer"))
+	}
+	return id, nil
+}
+
+func handleResponseV2(ctx context.Context, w http.ResponseWriter, status int, data interface{}) {
+	writeBody(ctx, w, status, DataResponse{Data: data})
+}
+
+// handleErrorV2 writes err as an ErrorResponse. Domain errors carry their code and
+// message, anything unexpected is reported as err_unknown without details.
+func handleErrorV2(ctx context.Context, w http.ResponseWriter, err error) {
+	logging.From(ctx).Error("error occurred in request", zap.Error(err))
+
+	status := errorStatus(err)
+
+	var body ErrorBody
+	if status == http.StatusInternalServerError {
+		body = errorBody(errors.ErrUnknown)
+	} else {
+		body = errorBody(err)
+	}
+
+	writeBody(ctx, w, status, ErrorResponse{Error: body})
+}
+
+func errorBody(err error) ErrorBody {
+	first := strings.Split(err.Error(), errors.ErrSeperator)[0]
+
+	code, message, ok := strings.Cut(first, ": ")
+	if !ok {
+		return ErrorBody{Code: "err_unknown", Message: first}
+	}
+
+	return ErrorBody{Code: code, Message: message}
+}
+
+// writeBody encodes v with the codec negotiated for the response.
+func writeBody(ctx context.Context, w http.ResponseWriter, status int, v interface{}) {
+	c := responseCodec(ctx)
+
+	data, err := c.Marshal(v)
+	if err != nil {
+		logging.From(ctx).Error("failed to serialize response", zap.Error(err))
+		c = codec.JSON{}
+		status = http.StatusInternalServerError
+		data = []byte(`{"error":{"code":"err_unknown","message":"unknown error occurred"}}`)
+	}
+
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(status)
+
+	if _, err := w.Write(data); err != nil {
+		logging.From(ctx).Error("failed to write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,225 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/chats"
+	chatModel "github.com/Polilo-User/test-task-hitalent/internal/chats/model"
+	messagesModel "github.com/Polilo-User/test-task-hitalent/internal/messages/model"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveV2(t *testing.T, c httptransport.Chat, m httptransport.Message, method, url, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ht := httptransport.New(c, m, mocks.NewMockDB(ctrl))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestServer_CreateChatV2_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().
+		CreateChat(gomock.Any(), &chatModel.Chat{Title: pointer.ToString("general")}).
+		Return(&chatModel.Chat{
+			ID:        pointer.ToString("7"),
+			Title:     pointer.ToString("general"),
+			CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))),
+		}, nil).Times(1)
+
+	w := serveV2(t, c, m, http.MethodPost, "/v2/chats", `{"title":"general"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"data":{"id":7,"title":"general","created_at":"2020-01-01T09:00:00Z","messages":[]}}`, w.Body.String())
+}
+
+func TestServer_GetChatV2_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	created := pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	c.EXPECT().GetChatVersion(gomock.Any(), "7").Return(&chatModel.Version{Version: pointer.ToInt64(1)}, nil).Times(1)
+	c.EXPECT().GetChat(gomock.Any(), "7", int64(100)).Return(&chatModel.Chat{
+		ID:        pointer.ToString("7"),
+		Title:     pointer.ToString("general"),
+		CreatedAt: created,
+		Messages: []messagesModel.Message{
+			{ID: pointer.ToString("3"), ChatID: pointer.ToString("7"), Text: pointer.ToString("hi"), System: pointer.ToBool(false), CreatedAt: created},
+		},
+	}, nil).Times(1)
+
+	w := serveV2(t, c, m, http.MethodGet, "/v2/chats/7?limit=500", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"id":7,"title":"general","created_at":"2020-01-01T00:00:00Z","messages":[
+		{"id":3,"chat_id":7,"text":"hi","author":null,"system":false,"created_at":"2020-01-01T00:00:00Z"}
+	]}}`, w.Body.String())
+}
+
+func TestServer_ChatV2_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		setup    func(c *mocks.MockChat)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "client sets id",
+			method:   http.MethodPost,
+			url:      "/v2/chats",
+			body:     `{"id":1,"title":"general"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":{"code":"err_invalid_request","message":"invalid request received"}}`,
+		},
+		{
+			name:     "id not an integer",
+			method:   http.MethodGet,
+			url:      "/v2/chats/abc",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":{"code":"err_invalid_request","message":"invalid request received"}}`,
+		},
+		{
+			name:   "chat not found",
+			method: http.MethodGet,
+			url:    "/v2/chats/7",
+			setup: func(c *mocks.MockChat) {
+				c.EXPECT().GetChatVersion(gomock.Any(), "7").Return(nil, chats.ErrChatNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":{"code":"chat_not_found","message":"chat not found"}}`,
+		},
+		{
+			name:   "internal error is not leaked",
+			method: http.MethodDelete,
+			url:    "/v2/chats/7",
+			setup: func(c *mocks.MockChat) {
+				c.EXPECT().DeleteChat(gomock.Any(), "7").Return(errors.New("pq: connection refused")).Times(1)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"error":{"code":"err_unknown","message":"unknown error occurred"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(c)
+			}
+
+			w := serveV2(t, c, m, tt.method, tt.url, tt.body)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestServer_DeleteChatV2_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	c.EXPECT().DeleteChat(gomock.Any(), "7").Return(nil).Times(1)
+
+	w := serveV2(t, c, m, http.MethodDelete, "/v2/chats/7", "")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestServer_CreateMessageV2_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("7"),
+			Text:   pointer.ToString("hello"),
+			Author: pointer.ToString("bob"),
+		}).
+		Return(&messagesModel.Posted{Message: &messagesModel.Message{
+			ID:        pointer.ToString("3"),
+			ChatID:    pointer.ToString("7"),
+			Text:      pointer.ToString("hello"),
+			Author:    pointer.ToString("bob"),
+			CreatedAt: pointer.ToTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		}}, nil).Times(1)
+
+	w := serveV2(t, c, m, http.MethodPost, "/v2/chats/7/messages", `{"text":"hello","author":"bob"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var res struct {
+		Data httptransport.MessageResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, int64(3), res.Data.ID)
+	assert.Equal(t, int64(7), res.Data.ChatID)
+	assert.Equal(t, "2020-01-01T00:00:00Z", res.Data.CreatedAt)
+}
+
+func TestServer_CreateMessageV2_NoReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+
+	m.EXPECT().
+		CreateMessage(gomock.Any(), &messagesModel.Message{
+			ChatID: pointer.ToString("7"),
+			Text:   pointer.ToString("/mute"),
+		}).
+		Return(&messagesModel.Posted{Command: "mute"}, nil).Times(1)
+
+	w := serveV2(t, c, m, http.MethodPost, "/v2/chats/7/messages", `{"text":"/mute"}`)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,161 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	httptransport "github.com/Polilo-User/test-task-hitalent/internal/transport/http"
+	"github.com/Polilo-User/test-task-hitalent/internal/transport/http/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks"
+	webhookModel "github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	baseWebhookURL = "/v1/webhooks/"
+	webhookURL     = baseWebhookURL + "%s"
+	redeliverURL   = webhookURL + "/deliveries/%s/redeliver"
+)
+
+func TestServer_CreateWebhook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockChat(ctrl)
+	m := mocks.NewMockMessage(ctrl)
+	d := mocks.NewMockDB(ctrl)
+	wh := mocks.NewMockWebhook(ctrl)
+
+	ht := httptransport.New(c, m, d, httptransport.WithWebhooks(wh))
+
+	r := mux.NewRouter()
+	require.NoError(t, ht.AddRoutes(r))
+
+	want := webhookModel.Subscription{
+		ID:     pointer.ToString("1"),
+		URL:    pointer.ToString("https://example.com/hook"),
+		Events: pq.StringArray{string(events.ChatCreated)},
+		Secret: pointer.ToString("secret"),
+	}
+
+	wh.EXPECT().
+		CreateWebhook(gomock.Any(), gomock.AssignableToTypeOf(&webhookModel.Subscription{})).
+		DoAndReturn(func(ctx context.Context, sub *webhookModel.Subscription) (*webhookModel.Subscription, error) {
+			assert.Nil(t, sub.ID)
+			assert.Equal(t, want.URL, sub.URL)
+			return &want, nil
+		}).Times(1)
+
+	data, err := json.Marshal(map[string]interface{}{
+		"id":     "42",
+		"url":    *want.URL,
+		"events": want.Events,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, baseWebhookURL, bytes.NewBuffer(data))
+	require.NoError(t, err)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Data webhookModel.Subscription `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, want, res.Data)
+}
+
+func TestServer_Webhook_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		setup    func(wh *mocks.MockWebhook)
+		wantCode int
+	}{
+		{
+			name:     "invalid body",
+			method:   http.MethodPost,
+			url:      baseWebhookURL,
+			body:     `{"url":`,
+			setup:    func(wh *mocks.MockWebhook) {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid subscription",
+			method: http.MethodPost,
+			url:    baseWebhookURL,
+			body:   `{"url":"ftp://example.com"}`,
+			setup: func(wh *mocks.MockWebhook) {
+				wh.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(nil, webhooks.ErrInvalidWebhook).Times(1)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "webhook not found",
+			method: http.MethodGet,
+			url:    fmt.Sprintf(webhookURL, "1"),
+			setup: func(wh *mocks.MockWebhook) {
+				wh.EXPECT().GetWebhook(gomock.Any(), "1").Return(nil, webhooks.ErrWebhookNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:   "delivery not found",
+			method: http.MethodPost,
+			url:    fmt.Sprintf(redeliverURL, "1", "2"),
+			setup: func(wh *mocks.MockWebhook) {
+				wh.EXPECT().Redeliver(gomock.Any(), "1", "2").Return(nil, webhooks.ErrDeliveryNotFound).Times(1)
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := mocks.NewMockChat(ctrl)
+			m := mocks.NewMockMessage(ctrl)
+			d := mocks.NewMockDB(ctrl)
+			wh := mocks.NewMockWebhook(ctrl)
+
+			ht := httptransport.New(c, m, d, httptransport.WithWebhooks(wh))
+
+			r := mux.NewRouter()
+			require.NoError(t, ht.AddRoutes(r))
+
+			tt.setup(wh)
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var res struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.NotEmpty(t, res.Error)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultDeliveriesLimit = 50
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var sub model.Subscription
+	if err := decodeBody(r, &sub); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+	sub.ID = nil
+	sub.CreatedAt = nil
+
+	created, err := s.webhook.CreateWebhook(ctx, &sub)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, created)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subs, err := s.webhook.ListWebhooks(ctx)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, subs)
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sub, err := s.webhook.GetWebhook(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, sub)
+}
+
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var sub model.Subscription
+	if err := decodeBody(r, &sub); err != nil {
+		handleError(ctx, w, errors.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	updated, err := s.webhook.UpdateWebhook(ctx, mux.Vars(r)["id"], &sub)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, updated)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := s.webhook.DeleteWebhook(ctx, mux.Vars(r)["id"]); err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, "deleted")
+}
+
+func (s *Server) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseLimit(r, defaultDeliveriesLimit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	deliveries, err := s.webhook.ListDeliveries(ctx, mux.Vars(r)["id"], limit)
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, deliveries)
+}
+
+func (s *Server) redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	d, err := s.webhook.Redeliver(ctx, vars["id"], vars["delivery_id"])
+	if err != nil {
+		handleError(ctx, w, err)
+		return
+	}
+
+	handleResponse(ctx, w, d)
+}
+
+// parseLimit reads the optional "limit" query parameter, falling back to def when it is absent.
+func parseLimit(r *http.Request, def int64) (int64, error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return def, nil
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, errors.ErrValidation.Wrap(errors.New("invalid limit parameter"))
+	}
+
+	return limit, nil
+}
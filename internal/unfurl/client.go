@@ -0,0 +1,91 @@
+package unfurl
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+)
+
+// ErrBlockedAddress is returned when a link leads to an address that is not
+// publicly routable, such as the loopback interface or a private network.
+const ErrBlockedAddress = errors.Error("blocked_address: the address is not publicly routable")
+
+// maxRedirects is the number of redirects followed when fetching a page.
+const maxRedirects = 5
+
+// blockedPrefixes are the ranges that are not reachable from the internet but
+// are not covered by the netip.Addr predicates.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// NewClient returns an HTTP client for fetching the pages that links lead to.
+// Since anyone posting a message chooses the links, the client refuses to connect
+// to addresses inside the network the service runs in. Addresses are checked once
+// resolved, right before connecting, so redirects and DNS records pointing inside
+// are refused as well.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return ErrBlockedAddress.Wrap(err)
+			}
+			if !isPublic(ap.Addr()) {
+				return ErrBlockedAddress.Wrap(fmt.Errorf("%s is not public", ap.Addr()))
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// A proxy would make the connections, defeating the address check.
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          16,
+			IdleConnTimeout:       time.Minute,
+		},
+		CheckRedirect: checkRedirect,
+	}
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after too many redirects")
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirected to unsupported scheme %q", req.URL.Scheme)
+	}
+	return nil
+}
+
+// isPublic reports whether an address is reachable from the internet.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package unfurl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+
+	"github.com/AlekSi/pointer"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const errNotHTML = errors.Error("the page is not html")
+
+const (
+	// maxPageSize bounds how much of a page is read. The metadata is in the head of
+	// the page, so the rest is not needed anyway.
+	maxPageSize = 1 << 20
+
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+	maxURLLength         = 2048
+
+	userAgent = "LinkPreviewBot/1.0"
+)
+
+// fetch downloads a page and extracts its preview.
+func (u *UnfurlService) fetch(ctx context.Context, rawURL string) (*model.Preview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", userAgent)
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, errNotHTML
+	}
+
+	m := parseMeta(io.LimitReader(res.Body, maxPageSize))
+
+	p := &model.Preview{
+		URL:         pointer.ToString(rawURL),
+		Title:       optional(truncate(first(m["og:title"], m["twitter:title"], m["title"]), maxTitleLength)),
+		Description: optional(truncate(first(m["og:description"], m["twitter:description"], m["description"]), maxDescriptionLength)),
+		ImageURL:    optional(resolve(res.Request.URL, first(m["og:image"], m["og:image:url"], m["twitter:image"], m["twitter:image:src"]))),
+		SiteName:    optional(truncate(m["og:site_name"], maxTitleLength)),
+	}
+	if p.Title == nil && p.Description == nil && p.ImageURL == nil {
+		return nil, errors.New("the page has no preview metadata")
+	}
+
+	return p, nil
+}
+
+// parseMeta collects the content of the meta tags in the head of a page, keyed by
+// their property or name, along with the title of the page. Parsing stops at the
+// body, so a page with a huge head is the most that is ever read.
+func parseMeta(r io.Reader) map[string]string {
+	m := map[string]string{}
+	z := html.NewTokenizer(r)
+
+	inTitle := false
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return m
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			switch t.DataAtom {
+			case atom.Body:
+				return m
+			case atom.Title:
+				inTitle = true
+			case atom.Meta:
+				var key, content string
+				for _, a := range t.Attr {
+					switch strings.ToLower(a.Key) {
+					case "property", "name":
+						if key == "" {
+							key = strings.ToLower(strings.TrimSpace(a.Val))
+						}
+					case "content":
+						content = strings.TrimSpace(a.Val)
+					}
+				}
+				// "title" is kept for the title tag.
+				if key != "" && key != "title" && content != "" && m[key] == "" {
+					m[key] = content
+				}
+			}
+		case html.EndTagToken:
+			switch z.Token().DataAtom {
+			case atom.Head:
+				return m
+			case atom.Title:
+				inTitle = false
+			}
+		case html.TextToken:
+			if inTitle && m["title"] == "" {
+				m["title"] = strings.TrimSpace(string(z.Text()))
+			}
+		}
+	}
+}
+
+// resolve turns the URL of an image, which may be relative to the page, into an
+// absolute http or https URL. It returns an empty string for anything else.
+func resolve(page *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	u, err := page.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	s := u.String()
+	if len(s) > maxURLLength {
+		return ""
+	}
+	return s
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,59 @@
+package unfurl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxLinks is the number of links unfurled per message.
+const maxLinks = 5
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+
+// findLinks returns the distinct http and https URLs in a text, in the order they
+// appear, without their fragments. Punctuation ending a sentence or closing a
+// parenthesis around a link is not taken as part of it.
+func findLinks(text string) []string {
+	var links []string
+	seen := map[string]bool{}
+
+	for _, raw := range linkPattern.FindAllString(text, -1) {
+		raw = trimLink(raw)
+
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		u.Fragment = ""
+		u.RawFragment = ""
+
+		link := u.String()
+		if len(link) > maxURLLength || seen[link] {
+			continue
+		}
+		seen[link] = true
+
+		links = append(links, link)
+		if len(links) == maxLinks {
+			break
+		}
+	}
+
+	return links
+}
+
+func trimLink(s string) string {
+	for {
+		trimmed := strings.TrimRight(s, ".,;:!?")
+		// A closing parenthesis belongs to the link only when it opens one too,
+		// as in Wikipedia links.
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Polilo-User/test-task-hitalent/internal/unfurl (interfaces: Store)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimLink mocks base method.
+func (m *MockStore) ClaimLink(arg0 context.Context, arg1 time.Duration) (*model.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimLink", arg0, arg1)
+	ret0, _ := ret[0].(*model.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimLink indicates an expected call of ClaimLink.
+func (mr *MockStoreMockRecorder) ClaimLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimLink", reflect.TypeOf((*MockStore)(nil).ClaimLink), arg0, arg1)
+}
+
+// CompleteLink mocks base method.
+func (m *MockStore) CompleteLink(arg0 context.Context, arg1 *model.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLink indicates an expected call of CompleteLink.
+func (mr *MockStoreMockRecorder) CompleteLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLink", reflect.TypeOf((*MockStore)(nil).CompleteLink), arg0, arg1)
+}
+
+// GetPreview mocks base method.
+func (m *MockStore) GetPreview(arg0 context.Context, arg1 string) (*model.Preview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreview", arg0, arg1)
+	ret0, _ := ret[0].(*model.Preview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreview indicates an expected call of GetPreview.
+func (mr *MockStoreMockRecorder) GetPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreview", reflect.TypeOf((*MockStore)(nil).GetPreview), arg0, arg1)
+}
+
+// InsertLinks mocks base method.
+func (m *MockStore) InsertLinks(arg0 context.Context, arg1 []model.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLinks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertLinks indicates an expected call of InsertLinks.
+func (mr *MockStoreMockRecorder) InsertLinks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLinks", reflect.TypeOf((*MockStore)(nil).InsertLinks), arg0, arg1)
+}
+
+// ListPreviewsByMessages mocks base method.
+func (m *MockStore) ListPreviewsByMessages(arg0 context.Context, arg1 []string) ([]model.MessagePreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPreviewsByMessages", arg0, arg1)
+	ret0, _ := ret[0].([]model.MessagePreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPreviewsByMessages indicates an expected call of ListPreviewsByMessages.
+func (mr *MockStoreMockRecorder) ListPreviewsByMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPreviewsByMessages", reflect.TypeOf((*MockStore)(nil).ListPreviewsByMessages), arg0, arg1)
+}
+
+// SavePreview mocks base method.
+func (m *MockStore) SavePreview(arg0 context.Context, arg1 *model.Preview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePreview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePreview indicates an expected call of SavePreview.
+func (mr *MockStoreMockRecorder) SavePreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreview", reflect.TypeOf((*MockStore)(nil).SavePreview), arg0, arg1)
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	LinkPending = "pending"
+	LinkRunning = "running"
+	LinkDone    = "done"
+
+	PreviewOK     = "ok"
+	PreviewFailed = "failed"
+)
+
+// Link is a URL found in a message, queued to be unfurled. Position is where it
+// appears among the links of the message.
+type Link struct {
+	MessageID *string    `json:"message_id" db:"message_id" gorm:"primaryKey"`
+	URL       *string    `json:"url" db:"url" gorm:"primaryKey"`
+	ChatID    *string    `json:"chat_id" db:"chat_id"`
+	Position  *int       `json:"position" db:"position"`
+	Status    *string    `json:"status" db:"status"`
+	StartedAt *time.Time `json:"started_at" db:"started_at"`
+	CreatedAt *time.Time `json:"created_at" db:"created_at"`
+}
+
+func (Link) TableName() string {
+	return "message_links"
+}
+
+// Preview is what a page tells about itself through its OpenGraph or Twitter card
+// metadata. Previews are cached by URL and shared by every message linking to the
+// page; pages that could not be unfurled are cached too, with Status failed.
+type Preview struct {
+	URL         *string    `json:"url" db:"url" gorm:"primaryKey"`
+	Title       *string    `json:"title,omitempty" db:"title"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	ImageURL    *string    `json:"image_url,omitempty" db:"image_url"`
+	SiteName    *string    `json:"site_name,omitempty" db:"site_name"`
+	Status      *string    `json:"-" db:"status"`
+	Error       *string    `json:"-" db:"error"`
+	FetchedAt   *time.Time `json:"-" db:"fetched_at"`
+}
+
+func (Preview) TableName() string {
+	return "link_previews"
+}
+
+// MessagePreview is the preview of a link found in a message.
+type MessagePreview struct {
+	MessageID *string `db:"message_id"`
+	Preview   `gorm:"embedded"`
+}
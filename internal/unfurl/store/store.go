@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// InsertLinks queues the links of a message. Links already queued are left as
+// they are, so a message.created event seen twice queues its links once.
+func (s *Store) InsertLinks(ctx context.Context, links []model.Link) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// ClaimLink marks the oldest link waiting to be unfurled as running and returns
+// it. Links whose unfurling started more than staleAfter ago are claimed again.
+// It returns gorm.ErrRecordNotFound when there is nothing to unfurl.
+func (s *Store) ClaimLink(ctx context.Context, staleAfter time.Duration) (*model.Link, error) {
+	var l model.Link
+
+	res := s.db.WithContext(ctx).Raw(`
+		UPDATE message_links
+		SET status = ?, started_at = now()
+		WHERE (message_id, url) = (
+			SELECT message_id, url FROM message_links
+			WHERE status = ?
+				OR (status = ? AND started_at < now() - make_interval(secs => ?))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.LinkRunning, model.LinkPending, model.LinkRunning, staleAfter.Seconds(),
+	).Scan(&l)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &l, nil
+}
+
+func (s *Store) CompleteLink(ctx context.Context, l *model.Link) error {
+	return s.db.WithContext(ctx).Model(l).Update("status", model.LinkDone).Error
+}
+
+func (s *Store) GetPreview(ctx context.Context, url string) (*model.Preview, error) {
+	var p model.Preview
+	if err := s.db.WithContext(ctx).Where("url = ?", url).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SavePreview stores a preview, replacing the cached one of the same URL.
+func (s *Store) SavePreview(ctx context.Context, p *model.Preview) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(p).Error
+}
+
+// ListPreviewsByMessages returns the previews of the links found in the messages
+// that have been unfurled, in the order the links appear in each message.
+func (s *Store) ListPreviewsByMessages(ctx context.Context, messageIDs []string) ([]model.MessagePreview, error) {
+	var ps []model.MessagePreview
+
+	err := s.db.WithContext(ctx).
+		Table("message_links l").
+		Select("l.message_id, p.*").
+		Joins("JOIN link_previews p ON p.url = l.url").
+		Where("l.message_id IN ? AND l.status = ? AND p.status = ?", messageIDs, model.LinkDone, model.PreviewOK).
+		Order("l.message_id, l.position").
+		Scan(&ps).Error
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
@@ -0,0 +1,199 @@
+package unfurl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+
+	"github.com/AlekSi/pointer"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// unfurlStaleAfter is how long a link may be unfurled before it is considered
+	// abandoned by a worker that died and is unfurled again.
+	unfurlStaleAfter = time.Minute
+
+	// failedPreviewTTL is how long a page that could not be unfurled is left
+	// alone, which is shorter than for previews since the failure may be temporary.
+	failedPreviewTTL = time.Hour
+
+	maxErrorLength = 1024
+)
+
+type Store interface {
+	InsertLinks(ctx context.Context, links []model.Link) error
+	ClaimLink(ctx context.Context, staleAfter time.Duration) (*model.Link, error)
+	CompleteLink(ctx context.Context, l *model.Link) error
+	GetPreview(ctx context.Context, url string) (*model.Preview, error)
+	SavePreview(ctx context.Context, p *model.Preview) error
+	ListPreviewsByMessages(ctx context.Context, messageIDs []string) ([]model.MessagePreview, error)
+}
+
+// UnfurlService attaches previews of the pages linked from messages. It is
+// registered as an outbox sink to learn about new messages, whose links are then
+// unfurled in the background by any instance of the service.
+type UnfurlService struct {
+	store    Store
+	client   *http.Client
+	ttl      time.Duration
+	interval time.Duration
+}
+
+// New instantiates an UnfurlService fetching pages with the client, which should
+// come from NewClient. Previews are cached for ttl.
+func New(s Store, client *http.Client, ttl, interval time.Duration) *UnfurlService {
+	return &UnfurlService{
+		store:    s,
+		client:   client,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// message is the part of a message.created payload that links are looked for in.
+type message struct {
+	ID     string `json:"id"`
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// Publish queues the links of a newly posted message to be unfurled. Messages
+// imported in bulk are not unfurled.
+func (u *UnfurlService) Publish(ctx context.Context, e events.Event) error {
+	if e.Type != events.MessageCreated {
+		return nil
+	}
+
+	var m message
+	if err := json.Unmarshal(e.Data, &m); err != nil {
+		return err
+	}
+
+	found := findLinks(m.Text)
+	if len(found) == 0 {
+		return nil
+	}
+
+	links := make([]model.Link, 0, len(found))
+	for i, l := range found {
+		links = append(links, model.Link{
+			MessageID: pointer.ToString(m.ID),
+			ChatID:    pointer.ToString(m.ChatID),
+			URL:       pointer.ToString(l),
+			Position:  pointer.ToInt(i),
+			Status:    pointer.ToString(model.LinkPending),
+		})
+	}
+
+	return u.store.InsertLinks(ctx, links)
+}
+
+// Listen unfurls queued links until the context is cancelled.
+func (u *UnfurlService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("link unfurler starting")
+
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	for {
+		unfurled, err := u.UnfurlNext(ctx)
+		if err != nil {
+			logging.From(ctx).Error("failed to unfurl link", zap.Error(err))
+		}
+
+		// Another link may be waiting right behind the one unfurled.
+		if err == nil && unfurled {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.From(ctx).Info("link unfurler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// UnfurlNext claims the oldest queued link and unfurls it, reporting whether there
+// was one. The cached preview of the URL is used while it is fresh; otherwise the
+// page is fetched and the outcome cached, whether it has a preview or not. The
+// returned error only reports failures to claim or record a link.
+func (u *UnfurlService) UnfurlNext(ctx context.Context) (bool, error) {
+	l, err := u.store.ClaimLink(ctx, unfurlStaleAfter)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	link := pointer.GetString(l.URL)
+
+	p, err := u.store.GetPreview(ctx, link)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, err
+	}
+
+	if p == nil || u.expired(p) {
+		p, err = u.fetch(ctx, link)
+		if err != nil && ctx.Err() != nil {
+			return true, err
+		}
+		if err != nil {
+			logging.From(ctx).Info("link could not be unfurled", zap.String("url", link), zap.Error(err))
+
+			p = &model.Preview{
+				URL:    pointer.ToString(link),
+				Status: pointer.ToString(model.PreviewFailed),
+				Error:  pointer.ToString(truncate(err.Error(), maxErrorLength)),
+			}
+		} else {
+			p.Status = pointer.ToString(model.PreviewOK)
+		}
+		p.FetchedAt = pointer.ToTime(time.Now().UTC())
+
+		if err := u.store.SavePreview(ctx, p); err != nil {
+			return true, err
+		}
+	}
+
+	return true, u.store.CompleteLink(ctx, l)
+}
+
+// PreviewsByMessages returns the previews of the links found in the messages,
+// keyed by message id.
+func (u *UnfurlService) PreviewsByMessages(ctx context.Context, messageIDs []string) (map[string][]model.Preview, error) {
+	byMessage := make(map[string][]model.Preview, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return byMessage, nil
+	}
+
+	ps, err := u.store.ListPreviewsByMessages(ctx, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range ps {
+		id := pointer.GetString(p.MessageID)
+		byMessage[id] = append(byMessage[id], p.Preview)
+	}
+
+	return byMessage, nil
+}
+
+func (u *UnfurlService) expired(p *model.Preview) bool {
+	ttl := u.ttl
+	if pointer.GetString(p.Status) == model.PreviewFailed {
+		ttl = min(ttl, failedPreviewTTL)
+	}
+	return time.Since(pointer.GetTime(p.FetchedAt)) > ttl
+}
@@ -0,0 +1,385 @@
+package unfurl_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/unfurl/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func messageCreated(t *testing.T, text string) events.Event {
+	t.Helper()
+
+	e, err := events.New(events.MessageCreated, "1", map[string]string{"id": "2", "chat_id": "1", "text": text})
+	require.NoError(t, err)
+	return e
+}
+
+func TestUnfurlService_Publish_Success(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     func(t *testing.T) events.Event
+		wantLinks []string
+	}{
+		{
+			name: "links",
+			event: func(t *testing.T) events.Event {
+				return messageCreated(t, "see https://example.com/a, (https://en.wikipedia.org/wiki/Go_(language)) "+
+					"and HTTP://example.com/b#top or https://example.com/a again.")
+			},
+			wantLinks: []string{"https://example.com/a", "https://en.wikipedia.org/wiki/Go_(language)", "http://example.com/b"},
+		},
+		{
+			name: "at most five links",
+			event: func(t *testing.T) events.Event {
+				return messageCreated(t, "http://a.com http://b.com http://c.com http://d.com http://e.com http://f.com")
+			},
+			wantLinks: []string{"http://a.com", "http://b.com", "http://c.com", "http://d.com", "http://e.com"},
+		},
+		{
+			name:  "no links",
+			event: func(t *testing.T) events.Event { return messageCreated(t, "ftp://example.com is not a link") },
+		},
+		{
+			name: "other event",
+			event: func(t *testing.T) events.Event {
+				e, err := events.New(events.MessagesImported, "1", map[string]string{"text": "https://example.com"})
+				require.NoError(t, err)
+				return e
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			if tt.wantLinks != nil {
+				s.EXPECT().InsertLinks(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, links []model.Link) error {
+						require.Len(t, links, len(tt.wantLinks))
+						for i, l := range links {
+							assert.Equal(t, tt.wantLinks[i], pointer.GetString(l.URL))
+							assert.Equal(t, i, pointer.GetInt(l.Position))
+							assert.Equal(t, "2", pointer.GetString(l.MessageID))
+							assert.Equal(t, "1", pointer.GetString(l.ChatID))
+							assert.Equal(t, model.LinkPending, pointer.GetString(l.Status))
+						}
+						return nil
+					}).Times(1)
+			}
+
+			u := unfurl.New(s, http.DefaultClient, time.Hour, time.Second)
+			require.NoError(t, u.Publish(context.Background(), tt.event(t)))
+		})
+	}
+}
+
+func TestUnfurlService_Publish_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().InsertLinks(gomock.Any(), gomock.Any()).Return(errors.Error("test fail")).Times(1)
+
+	u := unfurl.New(s, http.DefaultClient, time.Hour, time.Second)
+
+	err := u.Publish(context.Background(), events.Event{Type: events.MessageCreated, Data: json.RawMessage(`[]`)})
+	assert.Error(t, err)
+
+	err = u.Publish(context.Background(), messageCreated(t, "https://example.com"))
+	assert.Equal(t, errors.Error("test fail"), err)
+}
+
+// pages serves the HTML pages previews are taken from.
+func pages() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/og", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<!doctype html><html><head>
+			<title>Ignored title</title>
+			<meta property="og:title" content="Gopher &amp; friends">
+			<meta property="og:description" content="  All about gophers.  ">
+			<meta property="og:image" content="/img/gopher.png">
+			<meta property="og:site_name" content="Example">
+			<meta name="twitter:title" content="Ignored twitter title">
+		</head><body><meta property="og:title" content="Not in the head"></body></html>`))
+	})
+	mux.HandleFunc("/twitter", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><title> Page title </title>
+			<meta name="description" content="Plain description">
+			<meta name="twitter:image" content="javascript:alert(1)">
+		</head></html>`))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/og", http.StatusFound)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head></head><body>nothing</body></html>`))
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":"not html"}`))
+	})
+	return mux
+}
+
+func TestUnfurlService_UnfurlNext_Success(t *testing.T) {
+	srv := httptest.NewServer(pages())
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		cached      *model.Preview
+		wantPreview *model.Preview
+	}{
+		{
+			name: "opengraph",
+			path: "/og",
+			wantPreview: &model.Preview{
+				Title:       pointer.ToString("Gopher & friends"),
+				Description: pointer.ToString("All about gophers."),
+				ImageURL:    pointer.ToString(srv.URL + "/img/gopher.png"),
+				SiteName:    pointer.ToString("Example"),
+				Status:      pointer.ToString(model.PreviewOK),
+			},
+		},
+		{
+			name: "title and description",
+			path: "/twitter",
+			wantPreview: &model.Preview{
+				Title:       pointer.ToString("Page title"),
+				Description: pointer.ToString("Plain description"),
+				Status:      pointer.ToString(model.PreviewOK),
+			},
+		},
+		{
+			name: "redirect",
+			path: "/moved",
+			wantPreview: &model.Preview{
+				Title:       pointer.ToString("Gopher & friends"),
+				Description: pointer.ToString("All about gophers."),
+				ImageURL:    pointer.ToString(srv.URL + "/img/gopher.png"),
+				SiteName:    pointer.ToString("Example"),
+				Status:      pointer.ToString(model.PreviewOK),
+			},
+		},
+		{
+			name:        "not found",
+			path:        "/missing",
+			wantPreview: &model.Preview{Status: pointer.ToString(model.PreviewFailed)},
+		},
+		{
+			name:        "not html",
+			path:        "/json",
+			wantPreview: &model.Preview{Status: pointer.ToString(model.PreviewFailed)},
+		},
+		{
+			name:        "no metadata",
+			path:        "/empty",
+			wantPreview: &model.Preview{Status: pointer.ToString(model.PreviewFailed)},
+		},
+		{
+			name: "cached",
+			path: "/json",
+			cached: &model.Preview{
+				Title:     pointer.ToString("Cached"),
+				Status:    pointer.ToString(model.PreviewOK),
+				FetchedAt: pointer.ToTime(time.Now().Add(-time.Minute)),
+			},
+		},
+		{
+			name: "cache expired",
+			path: "/twitter",
+			cached: &model.Preview{
+				Title:     pointer.ToString("Cached"),
+				Status:    pointer.ToString(model.PreviewOK),
+				FetchedAt: pointer.ToTime(time.Now().Add(-2 * time.Hour)),
+			},
+			wantPreview: &model.Preview{
+				Title:       pointer.ToString("Page title"),
+				Description: pointer.ToString("Plain description"),
+				Status:      pointer.ToString(model.PreviewOK),
+			},
+		},
+		{
+			name: "failure cached",
+			path: "/og",
+			cached: &model.Preview{
+				Status:    pointer.ToString(model.PreviewFailed),
+				FetchedAt: pointer.ToTime(time.Now().Add(-time.Minute)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			link := &model.Link{
+				MessageID: pointer.ToString("2"),
+				URL:       pointer.ToString(srv.URL + tt.path),
+				Status:    pointer.ToString(model.LinkRunning),
+			}
+
+			s := mocks.NewMockStore(ctrl)
+			s.EXPECT().ClaimLink(gomock.Any(), gomock.Any()).Return(link, nil).Times(1)
+			if tt.cached != nil {
+				s.EXPECT().GetPreview(gomock.Any(), srv.URL+tt.path).Return(tt.cached, nil).Times(1)
+			} else {
+				s.EXPECT().GetPreview(gomock.Any(), srv.URL+tt.path).Return(nil, gorm.ErrRecordNotFound).Times(1)
+			}
+			if tt.wantPreview != nil {
+				s.EXPECT().SavePreview(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p *model.Preview) error {
+						assert.Equal(t, srv.URL+tt.path, pointer.GetString(p.URL))
+						assert.Equal(t, tt.wantPreview.Title, p.Title)
+						assert.Equal(t, tt.wantPreview.Description, p.Description)
+						assert.Equal(t, tt.wantPreview.ImageURL, p.ImageURL)
+						assert.Equal(t, tt.wantPreview.SiteName, p.SiteName)
+						assert.Equal(t, tt.wantPreview.Status, p.Status)
+						assert.NotNil(t, p.FetchedAt)
+						if pointer.GetString(p.Status) == model.PreviewFailed {
+							assert.NotEmpty(t, pointer.GetString(p.Error))
+						}
+						return nil
+					}).Times(1)
+			}
+			s.EXPECT().CompleteLink(gomock.Any(), link).Return(nil).Times(1)
+
+			u := unfurl.New(s, srv.Client(), time.Hour, time.Second)
+
+			unfurled, err := u.UnfurlNext(context.Background())
+			require.NoError(t, err)
+			assert.True(t, unfurled)
+		})
+	}
+}
+
+func TestUnfurlService_UnfurlNext_Error(t *testing.T) {
+	link := &model.Link{MessageID: pointer.ToString("2"), URL: pointer.ToString("http://127.0.0.1:1/")}
+
+	tests := []struct {
+		name         string
+		setup        func(s *mocks.MockStore)
+		wantUnfurled bool
+		wantErr      error
+	}{
+		{
+			name: "nothing to unfurl",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimLink(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).Times(1)
+			},
+		},
+		{
+			name: "claim failed",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimLink(gomock.Any(), gomock.Any()).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantErr: errors.Error("test fail"),
+		},
+		{
+			name: "cache lookup failed",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimLink(gomock.Any(), gomock.Any()).Return(link, nil).Times(1)
+				s.EXPECT().GetPreview(gomock.Any(), gomock.Any()).Return(nil, errors.Error("test fail")).Times(1)
+			},
+			wantUnfurled: true,
+			wantErr:      errors.Error("test fail"),
+		},
+		{
+			name: "save failed",
+			setup: func(s *mocks.MockStore) {
+				s.EXPECT().ClaimLink(gomock.Any(), gomock.Any()).Return(link, nil).Times(1)
+				s.EXPECT().GetPreview(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).Times(1)
+				s.EXPECT().SavePreview(gomock.Any(), gomock.Any()).Return(errors.Error("test fail")).Times(1)
+			},
+			wantUnfurled: true,
+			wantErr:      errors.Error("test fail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+			tt.setup(s)
+
+			u := unfurl.New(s, unfurl.NewClient(time.Second), time.Hour, time.Second)
+
+			unfurled, err := u.UnfurlNext(context.Background())
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantUnfurled, unfurled)
+		})
+	}
+}
+
+func TestUnfurlService_PreviewsByMessages_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+	s.EXPECT().ListPreviewsByMessages(gomock.Any(), []string{"1", "2"}).Return([]model.MessagePreview{
+		{MessageID: pointer.ToString("1"), Preview: model.Preview{URL: pointer.ToString("https://a.com")}},
+		{MessageID: pointer.ToString("1"), Preview: model.Preview{URL: pointer.ToString("https://b.com")}},
+	}, nil).Times(1)
+
+	u := unfurl.New(s, http.DefaultClient, time.Hour, time.Second)
+
+	byMessage, err := u.PreviewsByMessages(context.Background(), []string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.Preview{
+		"1": {{URL: pointer.ToString("https://a.com")}, {URL: pointer.ToString("https://b.com")}},
+	}, byMessage)
+
+	byMessage, err = u.PreviewsByMessages(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, byMessage)
+}
+
+func TestNewClient_BlocksInternalAddresses(t *testing.T) {
+	var hit bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer srv.Close()
+
+	c := unfurl.NewClient(time.Second)
+
+	for _, u := range []string{
+		srv.URL,
+		strings.Replace(srv.URL, "127.0.0.1", "localhost", 1),
+		"http://10.0.0.1:1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]:1/",
+		"http://[::ffff:127.0.0.1]:1/",
+		"http://100.64.0.1:1/",
+	} {
+		_, err := c.Get(u)
+		assert.ErrorIs(t, err, unfurl.ErrBlockedAddress, u)
+	}
+	assert.False(t, hit)
+}
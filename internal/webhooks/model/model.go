@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryRunning   = "running"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Subscription struct {
+	ID        *string        `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	URL       *string        `json:"url" db:"url" validate:"required,url"`
+	Events    pq.StringArray `json:"events" db:"events" gorm:"type:text[]" validate:"required,min=1"`
+	ChatID    *string        `json:"chat_id" db:"chat_id"`
+	Secret    *string        `json:"secret,omitempty" db:"secret"`
+	CreatedAt *time.Time     `json:"created_at" db:"created_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhooks"
+}
+
+type Delivery struct {
+	ID             *string         `json:"id" db:"id" gorm:"primaryKey;autoIncrement"`
+	WebhookID      *string         `json:"webhook_id" db:"webhook_id"`
+	Event          *string         `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload" gorm:"type:jsonb"`
+	Status         *string         `json:"status" db:"status"`
+	Attempts       *int            `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status" db:"response_status"`
+	Error          *string         `json:"error" db:"error"`
+	CreatedAt      *time.Time      `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at" db:"delivered_at"`
+	// RedeliveryOf is the delivery this one sends again.
+	RedeliveryOf *string `json:"redelivery_of" db:"redelivery_of"`
+	// LockedUntil is when the claim of the dispatcher sending a running delivery
+	// lapses, after which another one may claim it.
+	LockedUntil *time.Time `json:"-" db:"locked_until"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
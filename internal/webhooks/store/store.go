@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+	"gorm.io/gorm"
+)
+
+type Store struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+func (s *Store) InsertWebhook(ctx context.Context, w *model.Subscription) (*model.Subscription, error) {
+	if err := s.db.WithContext(ctx).Create(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id string) (*model.Subscription, error) {
+	var w model.Subscription
+
+	if err := s.db.WithContext(ctx).Where("id = ?", id).Take(&w).Error; err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]model.Subscription, error) {
+	var w []model.Subscription
+
+	if err := s.db.WithContext(ctx).Order("id").Find(&w).Error; err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, w *model.Subscription) (*model.Subscription, error) {
+	err := s.db.WithContext(ctx).Model(w).
+		Select("url", "events", "chat_id", "secret").
+		Updates(w).Error
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&model.Subscription{}, "id = ?", id).Error
+}
+
+// ListMatchingWebhooks returns the subscriptions interested in the event type for the given chat.
+// Subscriptions without a chat filter match every chat.
+func (s *Store) ListMatchingWebhooks(ctx context.Context, event string, chatID string) ([]model.Subscription, error) {
+	var w []model.Subscription
+
+	err := s.db.WithContext(ctx).
+		Where("? = ANY(events)", event).
+		Where("chat_id IS NULL OR chat_id = ?", chatID).
+		Find(&w).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (s *Store) InsertDelivery(ctx context.Context, d *model.Delivery) (*model.Delivery, error) {
+	if err := s.db.WithContext(ctx).Create(d).Error; err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *Store) GetDelivery(ctx context.Context, webhookID, id string) (*model.Delivery, error) {
+	var d model.Delivery
+
+	if err := s.db.WithContext(ctx).Where("webhook_id = ? AND id = ?", webhookID, id).Take(&d).Error; err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, webhookID string, limit int64) ([]model.Delivery, error) {
+	var d []model.Delivery
+
+	err := s.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(int(limit)).
+		Find(&d).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// ClaimDeliveries marks up to limit pending deliveries as running until lease
+// from now and returns them. Running deliveries whose lease has lapsed are
+// claimed again, and rows claimed by another dispatcher are skipped.
+func (s *Store) ClaimDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]model.Delivery, error) {
+	var d []model.Delivery
+
+	err := s.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries
+		SET status = ?, locked_until = now() + make_interval(secs => ?)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? OR (status = ? AND locked_until < now())
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.DeliveryRunning, lease.Seconds(), model.DeliveryPending, model.DeliveryRunning, limit,
+	).Scan(&d).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (s *Store) UpdateDelivery(ctx context.Context, d *model.Delivery) error {
+	return s.db.WithContext(ctx).Model(d).
+		Select("status", "attempts", "response_status", "error", "delivered_at", "locked_until").
+		Updates(d).Error
+}
@@ -0,0 +1,465 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/core/errors"
+	"github.com/Polilo-User/test-task-hitalent/internal/core/logging"
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrWebhookNotFound  = errors.Error("webhook_not_found: webhook not found")
+	ErrDeliveryNotFound = errors.Error("delivery_not_found: delivery not found")
+	ErrInvalidWebhook   = errors.Error("invalid_webhook: invalid webhook subscription")
+	ErrDeliveryFailed   = errors.Error("delivery_failed: webhook delivery failed")
+)
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body keyed with the subscription secret.
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	queueSize      = 1024
+	workers        = 4
+	maxErrorLength = 1024
+
+	// deliveryLease is how long a dispatcher holds a delivery it claimed. It
+	// outlasts the 15 minutes the retries of a delivery are given, so a delivery
+	// is only claimed again when its dispatcher went away.
+	deliveryLease = 30 * time.Minute
+)
+
+type Store interface {
+	InsertWebhook(ctx context.Context, w *model.Subscription) (*model.Subscription, error)
+	GetWebhook(ctx context.Context, id string) (*model.Subscription, error)
+	ListWebhooks(ctx context.Context) ([]model.Subscription, error)
+	UpdateWebhook(ctx context.Context, w *model.Subscription) (*model.Subscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListMatchingWebhooks(ctx context.Context, event string, chatID string) ([]model.Subscription, error)
+	InsertDelivery(ctx context.Context, d *model.Delivery) (*model.Delivery, error)
+	GetDelivery(ctx context.Context, webhookID, id string) (*model.Delivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, limit int64) ([]model.Delivery, error)
+	ClaimDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]model.Delivery, error)
+	UpdateDelivery(ctx context.Context, d *model.Delivery) error
+}
+
+type job struct {
+	webhookID  string
+	deliveryID string
+}
+
+// WebhookService manages webhook subscriptions and delivers events to them.
+type WebhookService struct {
+	store      Store
+	client     *http.Client
+	maxRetries uint64
+	rescan     time.Duration
+	queue      chan job
+	validate   *validator.Validate
+}
+
+// New returns a WebhookService that claims pending deliveries every rescan,
+// picking up those that did not fit in the queue when they were published and
+// those left behind by a dispatcher that went away.
+func New(s Store, client *http.Client, maxRetries uint64, rescan time.Duration) *WebhookService {
+	return &WebhookService{
+		store:      s,
+		client:     client,
+		maxRetries: maxRetries,
+		rescan:     rescan,
+		queue:      make(chan job, queueSize),
+		validate:   validator.New(),
+	}
+}
+
+func (w *WebhookService) CreateWebhook(ctx context.Context, sub *model.Subscription) (*model.Subscription, error) {
+	if err := w.validateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	if sub.Secret == nil || *sub.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = &secret
+	}
+
+	return w.store.InsertWebhook(ctx, sub)
+}
+
+func (w *WebhookService) GetWebhook(ctx context.Context, id string) (*model.Subscription, error) {
+	sub, err := w.getWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Secret = nil
+
+	return sub, nil
+}
+
+func (w *WebhookService) ListWebhooks(ctx context.Context) ([]model.Subscription, error) {
+	subs, err := w.store.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subs {
+		subs[i].Secret = nil
+	}
+
+	return subs, nil
+}
+
+// UpdateWebhook replaces the subscription settings. The secret is kept unless a new one is provided.
+func (w *WebhookService) UpdateWebhook(ctx context.Context, id string, sub *model.Subscription) (*model.Subscription, error) {
+	existing, err := w.getWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.validateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	existing.URL = sub.URL
+	existing.Events = sub.Events
+	existing.ChatID = sub.ChatID
+	if sub.Secret != nil && *sub.Secret != "" {
+		existing.Secret = sub.Secret
+	}
+
+	updated, err := w.store.UpdateWebhook(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	updated.Secret = nil
+
+	return updated, nil
+}
+
+func (w *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := w.getWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	return w.store.DeleteWebhook(ctx, id)
+}
+
+func (w *WebhookService) ListDeliveries(ctx context.Context, webhookID string, limit int64) ([]model.Delivery, error) {
+	if _, err := w.getWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return w.store.ListDeliveries(ctx, webhookID, limit)
+}
+
+// Redeliver records a new delivery sending the payload of an earlier one again
+// and queues it. The earlier delivery is left as it is in the log.
+func (w *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID string) (*model.Delivery, error) {
+	d, err := w.getDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.dispatch(ctx, &model.Delivery{
+		WebhookID:    d.WebhookID,
+		Event:        d.Event,
+		Payload:      d.Payload,
+		RedeliveryOf: d.ID,
+	})
+}
+
+// Publish records a delivery for every subscription interested in the event and queues it for sending.
+func (w *WebhookService) Publish(ctx context.Context, e events.Event) error {
+	subs, err := w.store.ListMatchingWebhooks(ctx, string(e.Type), e.ChatID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		_, err := w.dispatch(ctx, &model.Delivery{
+			WebhookID: sub.ID,
+			Event:     pointer.ToString(string(e.Type)),
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatch records a delivery already claimed by this dispatcher and queues it.
+func (w *WebhookService) dispatch(ctx context.Context, d *model.Delivery) (*model.Delivery, error) {
+	d.Status = pointer.ToString(model.DeliveryRunning)
+	d.Attempts = pointer.ToInt(0)
+	d.LockedUntil = pointer.ToTime(time.Now().UTC().Add(deliveryLease))
+
+	d, err := w.store.InsertDelivery(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	w.enqueue(ctx, d)
+
+	return d, nil
+}
+
+// Deliver sends a delivery to its subscription, retrying with exponential backoff,
+// and records the outcome in the delivery log. The delivery must have been
+// claimed, so that no other dispatcher sends it at the same time.
+func (w *WebhookService) Deliver(ctx context.Context, webhookID, deliveryID string) (*model.Delivery, error) {
+	sub, err := w.getWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := w.getDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := 0
+	if d.Attempts != nil {
+		attempts = *d.Attempts
+	}
+
+	b := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), w.maxRetries), ctx)
+
+	sendErr := backoff.Retry(func() error {
+		attempts++
+		status, err := w.send(ctx, sub, d)
+		if status != 0 {
+			d.ResponseStatus = pointer.ToInt(status)
+		}
+		return err
+	}, b)
+
+	d.Attempts = pointer.ToInt(attempts)
+	d.DeliveredAt = pointer.ToTime(time.Now().UTC())
+	d.LockedUntil = nil
+	if sendErr != nil {
+		d.Status = pointer.ToString(model.DeliveryFailed)
+		d.Error = pointer.ToString(truncate(sendErr.Error(), maxErrorLength))
+	} else {
+		d.Status = pointer.ToString(model.DeliverySucceeded)
+		d.Error = nil
+	}
+
+	if err := w.store.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+
+	if sendErr != nil {
+		return d, ErrDeliveryFailed.Wrap(sendErr)
+	}
+
+	return d, nil
+}
+
+// Listen runs the delivery workers until the context is cancelled. Pending
+// deliveries are claimed and queued on start and then every rescan, so none is
+// left behind when the queue was full or a process stopped. Claims are
+// exclusive, so each delivery is sent by one replica only.
+func (w *WebhookService) Listen(ctx context.Context) error {
+	logging.From(ctx).Info("webhook dispatcher starting")
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(w.rescan)
+	defer ticker.Stop()
+
+	for {
+		w.claimPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logging.From(ctx).Info("webhook dispatcher stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimPending claims as many pending deliveries as there is room for in the queue.
+func (w *WebhookService) claimPending(ctx context.Context) {
+	free := cap(w.queue) - len(w.queue)
+	if free == 0 {
+		return
+	}
+
+	claimed, err := w.store.ClaimDeliveries(ctx, int64(free), deliveryLease)
+	if err != nil {
+		logging.From(ctx).Error("failed to claim pending webhook deliveries", zap.Error(err))
+		return
+	}
+	for i := range claimed {
+		w.enqueue(ctx, &claimed[i])
+	}
+}
+
+func (w *WebhookService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-w.queue:
+			if _, err := w.Deliver(ctx, j.webhookID, j.deliveryID); err != nil {
+				logging.From(ctx).Error("webhook delivery failed",
+					zap.String("webhook_id", j.webhookID),
+					zap.String("delivery_id", j.deliveryID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+// enqueue queues a claimed delivery. The claim on a delivery that does not fit
+// in the queue is given up, leaving it pending for the next rescan.
+func (w *WebhookService) enqueue(ctx context.Context, d *model.Delivery) {
+	j := job{webhookID: *d.WebhookID, deliveryID: *d.ID}
+
+	select {
+	case w.queue <- j:
+		return
+	default:
+	}
+
+	logging.From(ctx).Warn("webhook delivery queue is full, delivery left pending",
+		zap.String("webhook_id", j.webhookID),
+		zap.String("delivery_id", j.deliveryID),
+	)
+
+	d.Status = pointer.ToString(model.DeliveryPending)
+	d.LockedUntil = nil
+	if err := w.store.UpdateDelivery(ctx, d); err != nil {
+		logging.From(ctx).Error("failed to release webhook delivery", zap.Error(err))
+	}
+}
+
+func (w *WebhookService) send(ctx context.Context, sub *model.Subscription, d *model.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, backoff.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, pointer.GetString(d.Event))
+	req.Header.Set(DeliveryHeader, *d.ID)
+	req.Header.Set(SignatureHeader, Sign(pointer.GetString(sub.Secret), d.Payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res.StatusCode, nil
+	}
+
+	err = fmt.Errorf("unexpected response status %d", res.StatusCode)
+	if res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests {
+		return res.StatusCode, backoff.Permanent(err)
+	}
+
+	return res.StatusCode, err
+}
+
+func (w *WebhookService) getWebhook(ctx context.Context, id string) (*model.Subscription, error) {
+	sub, err := w.store.GetWebhook(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, err
+}
+
+func (w *WebhookService) getDelivery(ctx context.Context, webhookID, id string) (*model.Delivery, error) {
+	d, err := w.store.GetDelivery(ctx, webhookID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeliveryNotFound
+	}
+	return d, err
+}
+
+func (w *WebhookService) validateSubscription(sub *model.Subscription) error {
+	if err := w.validate.Struct(sub); err != nil {
+		return ErrInvalidWebhook.Wrap(err)
+	}
+
+	u, err := url.Parse(*sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidWebhook.Wrap(errors.New("url must be an http or https url"))
+	}
+
+	for _, e := range sub.Events {
+		if !events.Type(e).Valid() {
+			return ErrInvalidWebhook.Wrap(fmt.Errorf("unknown event type %q", e))
+		}
+	}
+
+	return nil
+}
+
+// Sign returns the signature header value for a payload: "sha256=" followed by the
+// hex encoded HMAC-SHA256 of the payload keyed with the secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
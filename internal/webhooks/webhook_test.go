@@ -0,0 +1,370 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Polilo-User/test-task-hitalent/internal/events"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks/mocks"
+	"github.com/Polilo-User/test-task-hitalent/internal/webhooks/model"
+
+	"github.com/AlekSi/pointer"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestWebhooks_CreateWebhook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+
+	w := webhooks.New(s, http.DefaultClient, 0, time.Minute)
+	require.NotNil(t, w)
+
+	sub := &model.Subscription{
+		URL:    pointer.ToString("https://example.com/hook"),
+		Events: pq.StringArray{string(events.MessageCreated)},
+	}
+
+	s.EXPECT().
+		InsertWebhook(gomock.Any(), sub).
+		DoAndReturn(func(ctx context.Context, w *model.Subscription) (*model.Subscription, error) {
+			w.ID = pointer.ToString("1")
+			return w, nil
+		}).Times(1)
+
+	created, err := w.CreateWebhook(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, "1", *created.ID)
+	require.NotNil(t, created.Secret)
+	assert.Len(t, *created.Secret, 64)
+}
+
+func TestWebhooks_CreateWebhook_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  *model.Subscription
+	}{
+		{
+			name: "missing url",
+			sub: &model.Subscription{
+				Events: pq.StringArray{string(events.ChatCreated)},
+			},
+		},
+		{
+			name: "unsupported scheme",
+			sub: &model.Subscription{
+				URL:    pointer.ToString("ftp://example.com/hook"),
+				Events: pq.StringArray{string(events.ChatCreated)},
+			},
+		},
+		{
+			name: "unknown event",
+			sub: &model.Subscription{
+				URL:    pointer.ToString("https://example.com/hook"),
+				Events: pq.StringArray{"chat.renamed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mocks.NewMockStore(ctrl)
+
+			w := webhooks.New(s, http.DefaultClient, 0, time.Minute)
+
+			created, err := w.CreateWebhook(context.Background(), tt.sub)
+			assert.ErrorIs(t, err, webhooks.ErrInvalidWebhook)
+			assert.Nil(t, created)
+		})
+	}
+}
+
+func TestWebhooks_GetWebhook_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+
+	w := webhooks.New(s, http.DefaultClient, 0, time.Minute)
+
+	s.EXPECT().GetWebhook(gomock.Any(), "1").Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	sub, err := w.GetWebhook(context.Background(), "1")
+	assert.ErrorIs(t, err, webhooks.ErrWebhookNotFound)
+	assert.Nil(t, sub)
+}
+
+func TestWebhooks_Publish_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+
+	w := webhooks.New(s, http.DefaultClient, 0, time.Minute)
+
+	e, err := events.New(events.MessageCreated, "1", map[string]string{"text": "hello"})
+	require.NoError(t, err)
+
+	s.EXPECT().
+		ListMatchingWebhooks(gomock.Any(), string(events.MessageCreated), "1").
+		Return([]model.Subscription{{ID: pointer.ToString("7")}}, nil).
+		Times(1)
+
+	s.EXPECT().
+		InsertDelivery(gomock.Any(), gomock.AssignableToTypeOf(&model.Delivery{})).
+		DoAndReturn(func(ctx context.Context, d *model.Delivery) (*model.Delivery, error) {
+			assert.Equal(t, "7", *d.WebhookID)
+			// The delivery is claimed as it is recorded, so no rescan sends it too.
+			assert.Equal(t, model.DeliveryRunning, *d.Status)
+			require.NotNil(t, d.LockedUntil)
+			assert.True(t, d.LockedUntil.After(time.Now()))
+
+			var got events.Event
+			require.NoError(t, json.Unmarshal(d.Payload, &got))
+			assert.Equal(t, e.Type, got.Type)
+
+			d.ID = pointer.ToString("11")
+			return d, nil
+		}).Times(1)
+
+	err = w.Publish(context.Background(), e)
+	assert.NoError(t, err)
+}
+
+func TestWebhooks_Redeliver_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mocks.NewMockStore(ctrl)
+
+	w := webhooks.New(s, http.DefaultClient, 0, time.Minute)
+
+	s.EXPECT().GetDelivery(gomock.Any(), "1", "2").Return(&model.Delivery{
+		ID:        pointer.ToString("2"),
+		WebhookID: pointer.ToString("1"),
+		Event:     pointer.ToString(string(events.ChatCreated)),
+		Payload:   []byte(`{}`),
+		Status:    pointer.ToString(model.DeliveryFailed),
+		Attempts:  pointer.ToInt(3),
+		Error:     pointer.ToString("unexpected response status 502"),
+	}, nil).Times(1)
+
+	// The earlier delivery is not touched, a new one is recorded instead.
+	s.EXPECT().
+		InsertDelivery(gomock.Any(), gomock.AssignableToTypeOf(&model.Delivery{})).
+		DoAndReturn(func(ctx context.Context, d *model.Delivery) (*model.Delivery, error) {
+			assert.Nil(t, d.ID)
+			assert.Equal(t, "2", pointer.GetString(d.RedeliveryOf))
+			assert.Equal(t, model.DeliveryRunning, *d.Status)
+			assert.Equal(t, 0, *d.Attempts)
+			assert.Nil(t, d.Error)
+			assert.Equal(t, []byte(`{}`), []byte(d.Payload))
+
+			d.ID = pointer.ToString("3")
+			return d, nil
+		}).Times(1)
+
+	d, err := w.Redeliver(context.Background(), "1", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "3", *d.ID)
+}
+
+func TestWebhooks_Deliver_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := []byte(`{"type":"chat.created","chat_id":"1"}`)
+
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(webhooks.SignatureHeader)
+		gotEvent = r.Header.Get(webhooks.EventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	s := mocks.NewMockStore(ctrl)
+
+	w := webhooks.New(s, receiver.Client(), 3, time.Minute)
+
+	s.EXPECT().GetWebhook(gomock.Any(), "1").Return(&model.Subscription{
+		ID:     pointer.ToString("1"),
+		URL:    pointer.ToString(receiver.URL),
+		Secret: pointer.ToString("secret"),
+	}, nil).Times(1)
+
+	s.EXPECT().GetDelivery(gomock.Any(), "1", "2").Return(&model.Delivery{
+		ID:        pointer.ToString("2"),
+		WebhookID: pointer.ToString("1"),
+		Event:     pointer.ToString(string(events.ChatCreated)),
+		Payload:   payload,
+		Status:    pointer.ToString(model.DeliveryPending),
+		Attempts:  pointer.ToInt(0),
+	}, nil).Times(1)
+
+	s.EXPECT().UpdateDelivery(gomock.Any(), gomock.AssignableToTypeOf(&model.Delivery{})).Return(nil).Times(1)
+
+	d, err := w.Deliver(context.Background(), "1", "2")
+	require.NoError(t, err)
+	assert.Equal(t, model.DeliverySucceeded, *d.Status)
+	assert.Equal(t, 1, *d.Attempts)
+	assert.Equal(t, http.StatusNoContent, *d.ResponseStatus)
+
+	assert.Equal(t, payload, gotBody)
+	assert.Equal(t, string(events.ChatCreated), gotEvent)
+	assert.Equal(t, webhooks.Sign("secret", payload), gotSignature)
+}
+
+func TestWebhooks_Deliver_Error(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantAttempts int
+	}{
+		{
+			name:         "client error is not retried",
+			status:       http.StatusGone,
+			wantAttempts: 1,
+		},
+		{
+			name:         "server error is retried",
+			status:       http.StatusBadGateway,
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			calls := 0
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			s := mocks.NewMockStore(ctrl)
+
+			w := webhooks.New(s, receiver.Client(), 2, time.Minute)
+
+			s.EXPECT().GetWebhook(gomock.Any(), "1").Return(&model.Subscription{
+				ID:     pointer.ToString("1"),
+				URL:    pointer.ToString(receiver.URL),
+				Secret: pointer.ToString("secret"),
+			}, nil).Times(1)
+
+			s.EXPECT().GetDelivery(gomock.Any(), "1", "2").Return(&model.Delivery{
+				ID:        pointer.ToString("2"),
+				WebhookID: pointer.ToString("1"),
+				Event:     pointer.ToString(string(events.ChatCreated)),
+				Payload:   []byte(`{}`),
+				Status:    pointer.ToString(model.DeliveryPending),
+			}, nil).Times(1)
+
+			s.EXPECT().UpdateDelivery(gomock.Any(), gomock.AssignableToTypeOf(&model.Delivery{})).Return(nil).Times(1)
+
+			d, err := w.Deliver(context.Background(), "1", "2")
+			assert.ErrorIs(t, err, webhooks.ErrDeliveryFailed)
+			require.NotNil(t, d)
+			assert.Equal(t, model.DeliveryFailed, *d.Status)
+			assert.Equal(t, tt.wantAttempts, *d.Attempts)
+			assert.Equal(t, tt.wantAttempts, calls)
+			assert.Equal(t, tt.status, *d.ResponseStatus)
+		})
+	}
+}
+
+func TestWebhooks_Listen_Rescan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var mu sync.Mutex
+	calls := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		// Slow enough for rescans to happen while the delivery is sent.
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	claimed := func() model.Delivery {
+		return model.Delivery{
+			ID:        pointer.ToString("2"),
+			WebhookID: pointer.ToString("1"),
+			Event:     pointer.ToString(string(events.ChatCreated)),
+			Payload:   []byte(`{}`),
+			Status:    pointer.ToString(model.DeliveryRunning),
+		}
+	}
+
+	s := mocks.NewMockStore(ctrl)
+
+	scans := 0
+	s.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, int64, time.Duration) ([]model.Delivery, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		// The delivery is only claimed by the second scan, as if it had been
+		// published while the queue was full. Claims are exclusive, so later
+		// scans do not return it again.
+		scans++
+		if scans != 2 {
+			return nil, nil
+		}
+		return []model.Delivery{claimed()}, nil
+	}).MinTimes(2)
+
+	s.EXPECT().GetWebhook(gomock.Any(), "1").Return(&model.Subscription{
+		ID:     pointer.ToString("1"),
+		URL:    pointer.ToString(receiver.URL),
+		Secret: pointer.ToString("secret"),
+	}, nil).Times(1)
+	d := claimed()
+	s.EXPECT().GetDelivery(gomock.Any(), "1", "2").Return(&d, nil).Times(1)
+
+	delivered := make(chan struct{})
+	s.EXPECT().UpdateDelivery(gomock.Any(), gomock.AssignableToTypeOf(&model.Delivery{})).DoAndReturn(func(_ context.Context, d *model.Delivery) error {
+		assert.Equal(t, model.DeliverySucceeded, *d.Status)
+		assert.Nil(t, d.LockedUntil)
+		close(delivered)
+		return nil
+	}).Times(1)
+
+	w := webhooks.New(s, receiver.Client(), 0, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Listen(ctx) }()
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending delivery was not rescanned")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, calls)
+}
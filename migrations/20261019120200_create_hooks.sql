@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS author VARCHAR(100);
+
+CREATE TABLE IF NOT EXISTS hooks (
+    id SERIAL PRIMARY KEY,
+    chat_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    rate_limit INT NOT NULL DEFAULT 60,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ,
+    CONSTRAINT hooks_chat_id_fkey
+        FOREIGN KEY (chat_id)
+        REFERENCES chats (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS hooks_chat_id_idx ON hooks (chat_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS hooks;
+ALTER TABLE messages DROP COLUMN IF EXISTS author;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_system BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE messages DROP COLUMN IF EXISTS is_system;
@@ -0,0 +1,42 @@
+-- +goose Up
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS messages_chat_id_id_idx ON messages (chat_id, id DESC);
+
+-- New messages are picked up through the latest message id, so the version only
+-- has to change when a chat or one of its existing messages is modified.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_chat_version() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'chats' THEN
+        NEW.version := OLD.version + 1;
+        NEW.updated_at := now();
+        RETURN NEW;
+    END IF;
+
+    UPDATE chats SET version = version + 1, updated_at = now() WHERE id = OLD.chat_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chats_bump_version
+    BEFORE UPDATE ON chats
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.* AND OLD.version = NEW.version)
+    EXECUTE FUNCTION bump_chat_version();
+
+CREATE TRIGGER messages_bump_chat_version
+    AFTER UPDATE OR DELETE ON messages
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_chat_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS messages_bump_chat_version ON messages;
+DROP TRIGGER IF EXISTS chats_bump_version ON chats;
+DROP FUNCTION IF EXISTS bump_chat_version();
+DROP INDEX IF EXISTS messages_chat_id_id_idx;
+ALTER TABLE chats DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE chats DROP COLUMN IF EXISTS version;
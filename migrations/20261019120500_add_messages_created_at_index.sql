@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS messages_chat_id_created_at_idx ON messages (chat_id, created_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS messages_chat_id_created_at_idx;
@@ -0,0 +1,37 @@
+-- +goose Up
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS source VARCHAR(32);
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS source_id VARCHAR(255);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS source_id VARCHAR(255);
+
+-- Chats and messages brought over from another system remember their id there,
+-- which is what makes re-running an import skip what it already imported.
+CREATE UNIQUE INDEX IF NOT EXISTS chats_source_idx ON chats (source, source_id) WHERE source IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS messages_source_idx ON messages (chat_id, source_id) WHERE source_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    archive BYTEA,
+    chats_total INT NOT NULL DEFAULT 0,
+    chats_created INT NOT NULL DEFAULT 0,
+    messages_total INT NOT NULL DEFAULT 0,
+    messages_imported INT NOT NULL DEFAULT 0,
+    messages_skipped INT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS import_jobs_unfinished_idx ON import_jobs (id) WHERE status IN ('pending', 'running');
+
+-- +goose Down
+DROP TABLE IF EXISTS import_jobs;
+DROP INDEX IF EXISTS messages_source_idx;
+DROP INDEX IF EXISTS chats_source_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS source_id;
+ALTER TABLE chats DROP COLUMN IF EXISTS source_id;
+ALTER TABLE chats DROP COLUMN IF EXISTS source;
@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS attachments (
+    id BIGSERIAL PRIMARY KEY,
+    message_id INT NOT NULL,
+    chat_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT message_id_fkey
+        FOREIGN KEY (message_id)
+        REFERENCES messages (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS attachments_message_id_idx ON attachments (message_id, id);
+
+-- Blobs live outside the database, so deleting an attachment, or the message or
+-- chat it belongs to, only queues its blob for deletion by the attachment sweeper.
+CREATE TABLE IF NOT EXISTS orphaned_blobs (
+    id BIGSERIAL PRIMARY KEY,
+    storage_key VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION queue_orphaned_blob() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO orphaned_blobs (storage_key) VALUES (OLD.storage_key);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER attachments_queue_orphaned_blob
+    AFTER DELETE ON attachments
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_orphaned_blob();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS attachments_queue_orphaned_blob ON attachments;
+DROP FUNCTION IF EXISTS queue_orphaned_blob();
+DROP TABLE IF EXISTS orphaned_blobs;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,62 @@
+-- +goose Up
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS height INT;
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS processing_status VARCHAR(16);
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS processing_error TEXT;
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS processing_started_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS attachments_processing_idx ON attachments (id)
+    WHERE processing_status IN ('pending', 'running');
+
+CREATE TABLE IF NOT EXISTS attachment_thumbnails (
+    attachment_id BIGINT NOT NULL,
+    name VARCHAR(16) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(512) NOT NULL,
+    PRIMARY KEY (attachment_id, name),
+    CONSTRAINT attachment_id_fkey
+        FOREIGN KEY (attachment_id)
+        REFERENCES attachments (id)
+        ON DELETE CASCADE
+);
+
+CREATE TRIGGER attachment_thumbnails_queue_orphaned_blob
+    AFTER DELETE ON attachment_thumbnails
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_orphaned_blob();
+
+-- Attachments are returned with the messages of a chat, so changing them has to
+-- change the chat's version for cached copies to be refreshed.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_attachment_chat_version() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE chats SET version = version + 1, updated_at = now() WHERE id = OLD.chat_id;
+    ELSE
+        UPDATE chats SET version = version + 1, updated_at = now() WHERE id = NEW.chat_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER attachments_bump_chat_version
+    AFTER INSERT OR DELETE OR UPDATE OF width, height, processing_status ON attachments
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_attachment_chat_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS attachments_bump_chat_version ON attachments;
+DROP FUNCTION IF EXISTS bump_attachment_chat_version();
+DROP TRIGGER IF EXISTS attachment_thumbnails_queue_orphaned_blob ON attachment_thumbnails;
+DROP TABLE IF EXISTS attachment_thumbnails;
+DROP INDEX IF EXISTS attachments_processing_idx;
+ALTER TABLE attachments DROP COLUMN IF EXISTS processing_started_at;
+ALTER TABLE attachments DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE attachments DROP COLUMN IF EXISTS processing_status;
+ALTER TABLE attachments DROP COLUMN IF EXISTS height;
+ALTER TABLE attachments DROP COLUMN IF EXISTS width;
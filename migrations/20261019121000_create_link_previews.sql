@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS link_previews (
+    url TEXT PRIMARY KEY,
+    status VARCHAR(16) NOT NULL,
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    error TEXT,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS message_links (
+    message_id BIGINT NOT NULL,
+    chat_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    position INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    started_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, url),
+    CONSTRAINT message_id_fkey
+        FOREIGN KEY (message_id)
+        REFERENCES messages (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS message_links_pending_idx ON message_links (created_at)
+    WHERE status IN ('pending', 'running');
+
+-- Previews are returned with the messages of a chat, so a link being unfurled has
+-- to change the chat's version for cached copies to be refreshed.
+CREATE TRIGGER message_links_bump_chat_version
+    AFTER UPDATE OF status ON message_links
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_attachment_chat_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS message_links_bump_chat_version ON message_links;
+DROP INDEX IF EXISTS message_links_pending_idx;
+DROP TABLE IF EXISTS message_links;
+DROP TABLE IF EXISTS link_previews;
@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS mentions JSONB;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    chat_id BIGINT NOT NULL,
+    message_id BIGINT NOT NULL,
+    author VARCHAR(100),
+    excerpt TEXT NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (username, type, message_id),
+    CONSTRAINT message_id_fkey
+        FOREIGN KEY (message_id)
+        REFERENCES messages (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS notifications_username_id_idx ON notifications (username, id DESC);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (username, id DESC)
+    WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS notifications_message_id_idx ON notifications (message_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
+ALTER TABLE messages DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS mentions;
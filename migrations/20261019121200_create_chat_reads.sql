@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chat_reads (
+    username VARCHAR(100) NOT NULL,
+    chat_id INT NOT NULL,
+    last_read_message_id INT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (username, chat_id),
+    CONSTRAINT chat_id_fkey
+        FOREIGN KEY (chat_id)
+        REFERENCES chats (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS chat_reads_chat_id_idx ON chat_reads (chat_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS chat_reads;
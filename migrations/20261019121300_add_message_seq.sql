@@ -0,0 +1,64 @@
+-- +goose Up
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+-- Handing out sequence numbers is not a modification of the chat; new messages
+-- are picked up through the latest message id.
+DROP TRIGGER IF EXISTS chats_bump_version ON chats;
+CREATE TRIGGER chats_bump_version
+    BEFORE UPDATE ON chats
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.* AND OLD.version = NEW.version AND OLD.last_seq = NEW.last_seq)
+    EXECUTE FUNCTION bump_chat_version();
+
+-- Existing messages are numbered in the order they were stored, without touching
+-- the version of their chats.
+ALTER TABLE messages DISABLE TRIGGER messages_bump_chat_version;
+
+UPDATE messages m SET seq = n.seq
+FROM (SELECT id, row_number() OVER (PARTITION BY chat_id ORDER BY id) AS seq FROM messages) n
+WHERE m.id = n.id;
+
+ALTER TABLE messages ENABLE TRIGGER messages_bump_chat_version;
+
+UPDATE chats c SET last_seq = m.last_seq
+FROM (SELECT chat_id, MAX(seq) AS last_seq FROM messages GROUP BY chat_id) m
+WHERE c.id = m.chat_id;
+
+ALTER TABLE messages ALTER COLUMN seq SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS messages_chat_id_seq_idx ON messages (chat_id, seq);
+
+-- Each new message takes the next number of its chat. Incrementing the counter
+-- locks the chat row until the inserting transaction ends, so concurrent inserts
+-- into one chat are numbered one after the other and a rolled back insert gives
+-- its number back. Inserts numbering their messages themselves have to reserve
+-- the numbers the same way.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION assign_message_seq() RETURNS trigger AS $$
+BEGIN
+    IF NEW.seq IS NULL THEN
+        UPDATE chats SET last_seq = last_seq + 1 WHERE id = NEW.chat_id RETURNING last_seq INTO NEW.seq;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER messages_assign_seq
+    BEFORE INSERT ON messages
+    FOR EACH ROW
+    EXECUTE FUNCTION assign_message_seq();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS messages_assign_seq ON messages;
+DROP FUNCTION IF EXISTS assign_message_seq();
+DROP INDEX IF EXISTS messages_chat_id_seq_idx;
+DROP TRIGGER IF EXISTS chats_bump_version ON chats;
+CREATE TRIGGER chats_bump_version
+    BEFORE UPDATE ON chats
+    FOR EACH ROW
+    WHEN (OLD.* IS DISTINCT FROM NEW.* AND OLD.version = NEW.version)
+    EXECUTE FUNCTION bump_chat_version();
+ALTER TABLE messages DROP COLUMN IF EXISTS seq;
+ALTER TABLE chats DROP COLUMN IF EXISTS last_seq;
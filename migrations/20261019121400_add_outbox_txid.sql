@@ -0,0 +1,23 @@
+-- +goose Up
+-- The outbox doubles as the change log clients sync from. Ids are allocated
+-- before commit, so reading by id alone could skip a change committed after a
+-- higher one was read; reading in transaction order up to the oldest running
+-- transaction cannot.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS txid xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS outbox_txid_idx ON outbox (txid, id);
+
+-- The horizon is the position of the last change trimmed from the log. Clients
+-- that synced up to an earlier position have to resync in full.
+CREATE TABLE IF NOT EXISTS outbox_horizon (
+    singleton BOOLEAN PRIMARY KEY DEFAULT true CHECK (singleton),
+    txid xid8 NOT NULL,
+    id BIGINT NOT NULL
+);
+
+INSERT INTO outbox_horizon (txid, id) VALUES ('0', 0) ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_horizon;
+DROP INDEX IF EXISTS outbox_txid_idx;
+ALTER TABLE outbox DROP COLUMN IF EXISTS txid;
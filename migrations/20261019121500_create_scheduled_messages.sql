@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+    id SERIAL PRIMARY KEY,
+    chat_id INT NOT NULL,
+    text TEXT NOT NULL,
+    author VARCHAR(100),
+    send_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chat_id_fkey
+        FOREIGN KEY (chat_id)
+        REFERENCES chats (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_messages_send_at_idx ON scheduled_messages (send_at, id);
+CREATE INDEX IF NOT EXISTS scheduled_messages_chat_id_idx ON scheduled_messages (chat_id, send_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS scheduled_messages;
@@ -0,0 +1,33 @@
+-- +goose Up
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS message_ttl INT CHECK (message_ttl > 0);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS messages_expires_at_idx ON messages (expires_at) WHERE expires_at IS NOT NULL;
+
+-- Messages stored without an expiry expire after the message TTL of their chat,
+-- whichever way they are inserted.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION apply_message_ttl() RETURNS trigger AS $$
+BEGIN
+    IF NEW.expires_at IS NULL THEN
+        SELECT COALESCE(NEW.created_at, now()) + make_interval(secs => c.message_ttl)
+        INTO NEW.expires_at
+        FROM chats c
+        WHERE c.id = NEW.chat_id AND c.message_ttl IS NOT NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER messages_apply_ttl
+    BEFORE INSERT ON messages
+    FOR EACH ROW
+    EXECUTE FUNCTION apply_message_ttl();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS messages_apply_ttl ON messages;
+DROP FUNCTION IF EXISTS apply_message_ttl();
+DROP INDEX IF EXISTS messages_expires_at_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE chats DROP COLUMN IF EXISTS message_ttl;
@@ -0,0 +1,60 @@
+-- +goose Up
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS retention_days INT CHECK (retention_days > 0);
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT false;
+
+-- Each batch of messages purged by the retention job is reported per chat. The
+-- report outlives the chat, so it does not reference it.
+CREATE TABLE IF NOT EXISTS retention_purges (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id INT NOT NULL,
+    messages INT NOT NULL,
+    oldest_at TIMESTAMPTZ NOT NULL,
+    newest_at TIMESTAMPTZ NOT NULL,
+    purged_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS retention_purges_chat_id_idx ON retention_purges (chat_id, id);
+
+-- A chat under legal hold, and its messages, cannot be deleted by any means until
+-- the hold is lifted. The service checks the hold itself to report it properly;
+-- these triggers make sure nothing gets past it.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION enforce_chat_legal_hold() RETURNS trigger AS $$
+BEGIN
+    IF OLD.legal_hold THEN
+        RAISE EXCEPTION 'chat % is under legal hold', OLD.id USING ERRCODE = 'restrict_violation';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION enforce_message_legal_hold() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM chats WHERE id = OLD.chat_id AND legal_hold) THEN
+        RAISE EXCEPTION 'chat % is under legal hold', OLD.chat_id USING ERRCODE = 'restrict_violation';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chats_legal_hold
+    BEFORE DELETE ON chats
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_chat_legal_hold();
+
+CREATE TRIGGER messages_legal_hold
+    BEFORE DELETE ON messages
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_message_legal_hold();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS messages_legal_hold ON messages;
+DROP TRIGGER IF EXISTS chats_legal_hold ON chats;
+DROP FUNCTION IF EXISTS enforce_message_legal_hold();
+DROP FUNCTION IF EXISTS enforce_chat_legal_hold();
+DROP TABLE IF EXISTS retention_purges;
+ALTER TABLE chats DROP COLUMN IF EXISTS legal_hold;
+ALTER TABLE chats DROP COLUMN IF EXISTS retention_days;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- chat_id only filters the events a subscription receives. Cascading the
+-- deletion of a chat to its subscriptions deleted them before the chat.deleted
+-- event was delivered, so the chat filter is no longer a foreign key.
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_chat_id_fkey;
+
+-- +goose Down
+DELETE FROM webhooks w WHERE w.chat_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM chats c WHERE c.id = w.chat_id);
+
+ALTER TABLE webhooks
+    ADD CONSTRAINT webhooks_chat_id_fkey
+        FOREIGN KEY (chat_id)
+        REFERENCES chats (id)
+        ON DELETE CASCADE;
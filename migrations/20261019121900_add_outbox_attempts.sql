@@ -0,0 +1,9 @@
+-- +goose Up
+-- A record that fails to be published is retried with a backoff rather than on
+-- every poll. Its chat is held back meanwhile, so events stay in order.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Unread counts are derived from the seq of the last read message rather than
+-- counted message by message.
+ALTER TABLE chat_reads ADD COLUMN IF NOT EXISTS last_read_seq BIGINT NOT NULL DEFAULT 0;
+
+UPDATE chat_reads r SET last_read_seq = COALESCE(
+    (SELECT MAX(m.seq) FROM messages m WHERE m.chat_id = r.chat_id AND m.id <= r.last_read_message_id),
+    0
+);
+
+-- +goose Down
+ALTER TABLE chat_reads DROP COLUMN IF EXISTS last_read_seq;
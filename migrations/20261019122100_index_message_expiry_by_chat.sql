@@ -0,0 +1,6 @@
+-- +goose Up
+-- Chat versions look up the latest expiry of the expired messages of a chat.
+CREATE INDEX IF NOT EXISTS messages_chat_id_expires_at_idx ON messages (chat_id, expires_at) WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS messages_chat_id_expires_at_idx;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- The attachments of a chat under legal hold cannot be deleted either. Like
+-- messages, attachments carry the id of their chat for the check.
+CREATE TRIGGER attachments_legal_hold
+    BEFORE DELETE ON attachments
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_message_legal_hold();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS attachments_legal_hold ON attachments;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Deliveries are claimed by one dispatcher at a time, and redeliveries are new
+-- rows pointing at the delivery they repeat.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS redelivery_of INT REFERENCES webhook_deliveries (id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS webhook_deliveries_pending_idx;
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (id) WHERE status IN ('pending', 'running');
+
+-- +goose Down
+DROP INDEX IF EXISTS webhook_deliveries_pending_idx;
+UPDATE webhook_deliveries SET status = 'pending' WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (id) WHERE status = 'pending';
+
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS redelivery_of,
+    DROP COLUMN IF EXISTS locked_until;
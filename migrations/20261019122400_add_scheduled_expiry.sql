@@ -0,0 +1,6 @@
+-- +goose Up
+-- Scheduled messages keep the expiry they were sent with until they are posted.
+ALTER TABLE scheduled_messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE scheduled_messages DROP COLUMN IF EXISTS expires_at;